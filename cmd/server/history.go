@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	v2 "charm.land/log/v2"
+	"github.com/larsartmann/complaints-mcp/internal/config"
+	"github.com/larsartmann/complaints-mcp/internal/domain"
+	"github.com/larsartmann/complaints-mcp/internal/repo"
+	"github.com/larsartmann/complaints-mcp/internal/service"
+	"github.com/larsartmann/complaints-mcp/internal/tracing"
+	"github.com/spf13/cobra"
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history <complaint-id>",
+	Short: "Show every recorded change to a complaint",
+	Long: `History prints a complaint's audit trail, oldest first: who changed it,
+when, through which MCP tool, and the old and new value of every field.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runHistory,
+}
+
+func init() {
+	historyCmd.Flags().Bool("json", false, "print the events as JSON")
+	rootCmd.AddCommand(historyCmd)
+}
+
+func runHistory(cmd *cobra.Command, args []string) error {
+	logLevel, _ := cmd.Flags().GetString("log-level")
+	devMode, _ := cmd.Flags().GetBool("dev")
+	asJSON, _ := cmd.Flags().GetBool("json")
+
+	logger := newLogger(logLevel, devMode)
+	ctx := v2.WithContext(context.Background(), logger)
+
+	id, err := domain.ParseComplaintID(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid complaint ID: %w", err)
+	}
+
+	cfg, err := config.Load(ctx, cmd)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	tracer := tracing.NewNoOpTracer()
+
+	complaintRepo, err := repo.NewRepositoryFromConfig(cfg, tracer)
+	if err != nil {
+		return fmt.Errorf("failed to initialize repository: %w", err)
+	}
+
+	if closer, ok := complaintRepo.(io.Closer); ok {
+		defer closer.Close()
+	}
+
+	events, err := service.NewComplaintService(complaintRepo, tracer).GetComplaintHistory(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(events)
+	}
+
+	for _, event := range events {
+		printEvent(os.Stdout, event)
+	}
+
+	return nil
+}
+
+// printEvent writes one history event as a header line followed by one
+// indented line per changed field.
+func printEvent(w io.Writer, event domain.Event) {
+	fmt.Fprintf(w, "%s  %s  %s", event.At.Local().Format(time.DateTime), event.Action, event.Actor)
+
+	if event.Tool != "" {
+		fmt.Fprintf(w, " (via %s)", event.Tool)
+	}
+
+	fmt.Fprintln(w)
+
+	if event.Reason != "" {
+		fmt.Fprintf(w, "    reason: %s\n", event.Reason)
+	}
+
+	for _, change := range event.Changes {
+		fmt.Fprintf(w, "    %s: %q -> %q\n", change.Field, change.Old, change.New)
+	}
+}
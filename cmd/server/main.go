@@ -3,15 +3,23 @@ package main
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"syscall"
 	"time"
 
 	v2 "charm.land/log/v2"
+	"github.com/larsartmann/complaints-mcp/internal/backup"
 	"github.com/larsartmann/complaints-mcp/internal/config"
 	delivery "github.com/larsartmann/complaints-mcp/internal/delivery/mcp"
+	"github.com/larsartmann/complaints-mcp/internal/docs"
+	"github.com/larsartmann/complaints-mcp/internal/embed"
+	"github.com/larsartmann/complaints-mcp/internal/filelock"
+	"github.com/larsartmann/complaints-mcp/internal/projectdetect"
 	"github.com/larsartmann/complaints-mcp/internal/repo"
+	"github.com/larsartmann/complaints-mcp/internal/retention"
 	"github.com/larsartmann/complaints-mcp/internal/service"
 	"github.com/larsartmann/complaints-mcp/internal/tracing"
 	"github.com/spf13/cobra"
@@ -63,6 +71,15 @@ func newLogger(logLevel string, reportCaller bool) *v2.Logger {
 	})
 }
 
+// newEmbedder creates the embedder find_similar_complaints compares complaints with.
+func newEmbedder(cfg config.EmbeddingsConfig) embed.Embedder {
+	if cfg.Backend == "http" {
+		return embed.NewHTTPEmbedder(cfg.URL, cfg.Model, cfg.Timeout)
+	}
+
+	return embed.NewHashEmbedder(embed.DefaultDimensions)
+}
+
 func runServer(cmd *cobra.Command, args []string) error {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -100,8 +117,57 @@ func runServer(cmd *cobra.Command, args []string) error {
 	// Initialize dependencies
 	tracerConfig := tracing.DefaultTracerConfig()
 	tracer := tracing.NewTracer(tracerConfig)
-	complaintRepo := repo.NewRepositoryFromConfig(cfg, tracer)
+
+	complaintRepo, err := repo.NewRepositoryFromConfig(cfg, tracer)
+	if err != nil {
+		return fmt.Errorf("failed to initialize repository: %w", err)
+	}
+
+	// Read the project-local store of the project the server runs in
+	if registry, ok := complaintRepo.(repo.ProjectRegistry); ok {
+		if cwd, err := os.Getwd(); err == nil {
+			if info, err := projectdetect.DetectProject(ctx, cwd); err == nil {
+				registry.AddProject(info.Name, info.RootPath)
+			}
+		}
+	}
+
+	if !filelock.CrossProcess {
+		logger.Warn("File locks only guard writers in this process on this platform; "+
+			"do not share the complaint store with other server processes",
+			"base_dir", cfg.Storage.BaseDir)
+	}
+
+	// Quarantine complaint files left corrupt by crashes before anything reads them
+	if repairer, ok := complaintRepo.(repo.Repairer); ok {
+		report, err := repairer.Repair(ctx)
+		if err != nil {
+			logger.Warn("Storage integrity check failed", "error", err)
+		} else if len(report.Quarantined) > 0 || report.StaleTemps > 0 {
+			logger.Warn("Repaired complaint storage",
+				"scanned", report.Scanned,
+				"quarantined", len(report.Quarantined),
+				"quarantined_files", report.Quarantined,
+				"stale_temp_files", report.StaleTemps)
+		} else {
+			logger.Info("Complaint storage integrity check passed", "scanned", report.Scanned)
+		}
+	}
+
 	complaintService := service.NewComplaintService(complaintRepo, tracer)
+	complaintService.SetCategories(cfg.Complaints.Categories)
+	complaintService.SetFuzziness(cfg.Search.FuzzyDistance)
+	embeddings := embed.NewStore(newEmbedder(cfg.Embeddings), filepath.Join(cfg.Storage.BaseDir, embed.FileName))
+	complaintService.SetEmbeddings(embeddings)
+
+	if cfg.Storage.DocsEnabled {
+		exporter, err := docs.NewExporter(cfg.Storage.Docs, cfg.Storage.BaseDir)
+		if err != nil {
+			return fmt.Errorf("failed to initialize docs exporter: %w", err)
+		}
+
+		complaintService.SetDocsExporter(exporter)
+	}
 
 	// Initialize MCP server
 	var server *delivery.MCPServer
@@ -124,6 +190,57 @@ func runServer(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Background jobs share a context cancelled on shutdown
+	jobsCtx, jobsCancel := context.WithCancel(ctx)
+	defer jobsCancel()
+
+	// Enforce retention_days in the background
+	retentionDone := make(chan struct{})
+
+	if cfg.Storage.Retention > 0 {
+		sweeper := retention.NewSweeper(complaintRepo, retention.Policy{
+			MaxAge:       time.Duration(cfg.Storage.Retention) * 24 * time.Hour,
+			Mode:         cfg.Storage.PruneMode,
+			ResolvedOnly: cfg.Storage.RetentionResolvedOnly,
+			Interval:     cfg.Storage.RetentionInterval,
+		}, cfg.Storage.BaseDir, tracer)
+		sweeper.SetEmbeddings(embeddings)
+
+		logger.Info("Starting retention sweeper",
+			"retention_days", cfg.Storage.Retention,
+			"mode", cfg.Storage.PruneMode.String(),
+			"resolved_only", cfg.Storage.RetentionResolvedOnly)
+
+		go func() {
+			defer close(retentionDone)
+
+			sweeper.Run(jobsCtx)
+		}()
+	} else {
+		close(retentionDone)
+	}
+
+	// Take rotating backup snapshots in the background
+	backupDone := make(chan struct{})
+
+	if cfg.Storage.AutoBackup {
+		manager := backup.NewManager(
+			complaintRepo,
+			backup.Dir(cfg.Storage.BaseDir),
+			int(cfg.Storage.BackupKeep),
+			cfg.Storage.BackupInterval,
+			tracer,
+		)
+
+		go func() {
+			defer close(backupDone)
+
+			manager.Run(jobsCtx)
+		}()
+	} else {
+		close(backupDone)
+	}
+
 	// Setup graceful shutdown
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
@@ -163,6 +280,18 @@ func runServer(cmd *cobra.Command, args []string) error {
 		logger.Info("MCP server stopped gracefully")
 	}
 
+	// Stop background jobs before their repository goes away
+	jobsCancel()
+	<-retentionDone
+	<-backupDone
+
+	// Release repository resources (e.g. the SQLite handle)
+	if closer, ok := complaintRepo.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			logger.Error("Error closing repository", "error", err)
+		}
+	}
+
 	// Shutdown tracer to flush pending spans
 	if err := tracer.Close(); err != nil {
 		logger.Error("Error during tracer shutdown", "error", err)
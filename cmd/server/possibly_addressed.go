@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	v2 "charm.land/log/v2"
+	"github.com/larsartmann/complaints-mcp/internal/config"
+	delivery "github.com/larsartmann/complaints-mcp/internal/delivery/mcp"
+	"github.com/larsartmann/complaints-mcp/internal/repo"
+	"github.com/larsartmann/complaints-mcp/internal/service"
+	"github.com/larsartmann/complaints-mcp/internal/tracing"
+	"github.com/spf13/cobra"
+)
+
+var possiblyAddressedCmd = &cobra.Command{
+	Use:   "possibly-addressed",
+	Short: "List open complaints whose files changed after they were filed",
+	Long: `Possibly-addressed checks the git history of the repository each open
+complaint was filed from for commits, made after the complaint, that changed
+the files it references or mentions in its context or missing information.
+Those complaints are worth re-verifying, e.g. after a docs sprint.`,
+	Args: cobra.NoArgs,
+	RunE: runPossiblyAddressed,
+}
+
+func init() {
+	possiblyAddressedCmd.Flags().Bool("json", false, "print the complaints as JSON")
+	possiblyAddressedCmd.Flags().Int("limit", 50, "maximum number of complaints to list")
+	rootCmd.AddCommand(possiblyAddressedCmd)
+}
+
+func runPossiblyAddressed(cmd *cobra.Command, args []string) error {
+	logLevel, _ := cmd.Flags().GetString("log-level")
+	devMode, _ := cmd.Flags().GetBool("dev")
+	asJSON, _ := cmd.Flags().GetBool("json")
+	limit, _ := cmd.Flags().GetInt("limit")
+
+	logger := newLogger(logLevel, devMode)
+	ctx := v2.WithContext(context.Background(), logger)
+
+	cfg, err := config.Load(ctx, cmd)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	tracer := tracing.NewNoOpTracer()
+
+	complaintRepo, err := repo.NewRepositoryFromConfig(cfg, tracer)
+	if err != nil {
+		return fmt.Errorf("failed to initialize repository: %w", err)
+	}
+
+	if closer, ok := complaintRepo.(io.Closer); ok {
+		defer closer.Close()
+	}
+
+	found, err := service.NewComplaintService(complaintRepo, tracer).FindPossiblyAddressed(ctx, limit)
+	if err != nil {
+		return err
+	}
+
+	if asJSON {
+		complaints := make([]delivery.PossiblyAddressedDTO, 0, len(found))
+		for _, complaint := range found {
+			complaints = append(complaints, delivery.ToPossiblyAddressedDTO(complaint))
+		}
+
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(complaints)
+	}
+
+	for _, complaint := range found {
+		printPossiblyAddressed(os.Stdout, complaint)
+	}
+
+	return nil
+}
+
+// printPossiblyAddressed writes a complaint as a header line followed by one
+// indented line per changed file.
+func printPossiblyAddressed(w io.Writer, found service.PossiblyAddressed) {
+	complaint := found.Complaint
+
+	fmt.Fprintf(w, "%s  %s  %s  %s\n", complaint.ID, complaint.Timestamp.Local().Format(time.DateTime),
+		complaint.ResolutionState, complaint.TaskDescription)
+
+	for _, change := range found.Changes {
+		fmt.Fprintf(w, "    %s  changed %s in %s\n",
+			change.Path, change.At.Local().Format(time.DateTime), change.Commit[:min(len(change.Commit), 12)])
+	}
+}
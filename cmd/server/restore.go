@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	v2 "charm.land/log/v2"
+	"github.com/larsartmann/complaints-mcp/internal/backup"
+	"github.com/larsartmann/complaints-mcp/internal/config"
+	"github.com/larsartmann/complaints-mcp/internal/repo"
+	"github.com/larsartmann/complaints-mcp/internal/tracing"
+	"github.com/spf13/cobra"
+)
+
+var restoreCmd = &cobra.Command{
+	Use:   "restore <snapshot.tar.gz>",
+	Short: "Restore complaints and documents from a backup snapshot",
+	Long: `Restore validates every entry of a snapshot taken by storage.auto_backup
+(checksums and complaint validation) before writing anything, then saves the
+complaints to the configured storage. Their documents are written to the docs
+directory under storage.base_dir, never to paths named inside the snapshot.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRestore,
+}
+
+func init() {
+	restoreCmd.Flags().Bool("dry-run", false, "validate the snapshot without restoring it")
+	rootCmd.AddCommand(restoreCmd)
+}
+
+func runRestore(cmd *cobra.Command, args []string) error {
+	logLevel, _ := cmd.Flags().GetString("log-level")
+	devMode, _ := cmd.Flags().GetBool("dev")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	logger := newLogger(logLevel, devMode)
+	ctx := v2.WithContext(context.Background(), logger)
+
+	cfg, err := config.Load(ctx, cmd)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	tracer := tracing.NewNoOpTracer()
+
+	complaintRepo, err := repo.NewRepositoryFromConfig(cfg, tracer)
+	if err != nil {
+		return fmt.Errorf("failed to initialize repository: %w", err)
+	}
+
+	if closer, ok := complaintRepo.(io.Closer); ok {
+		defer closer.Close()
+	}
+
+	docsDir := filepath.Join(cfg.Storage.BaseDir, cfg.Storage.Docs.Dir)
+
+	result, err := backup.Restore(ctx, args[0], complaintRepo, docsDir, dryRun)
+	if err != nil {
+		return fmt.Errorf("failed to restore snapshot: %w", err)
+	}
+
+	verb := "Restored"
+	if dryRun {
+		verb = "Validated"
+	}
+
+	fmt.Fprintf(os.Stdout, "%s %d complaints and %d documents from %s\n",
+		verb, result.Complaints, result.Docs, args[0])
+
+	return nil
+}
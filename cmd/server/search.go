@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	v2 "charm.land/log/v2"
+	"github.com/larsartmann/complaints-mcp/internal/config"
+	delivery "github.com/larsartmann/complaints-mcp/internal/delivery/mcp"
+	"github.com/larsartmann/complaints-mcp/internal/repo"
+	"github.com/larsartmann/complaints-mcp/internal/service"
+	"github.com/larsartmann/complaints-mcp/internal/tracing"
+	"github.com/spf13/cobra"
+)
+
+var searchCmd = &cobra.Command{
+	Use:   "search <query>...",
+	Short: "Search complaints, best match first",
+	Long: `Search ranks complaints against a query, best match first, and prints a
+snippet of each with the matched words in **bold**. The query takes the same
+syntax as the search_complaints tool: words, "quoted phrases", -excluded
+words, and filters such as
+
+  severity:high,critical project:complaints-mcp agent:claude* session:s1
+  resolved:false state:open tag:cobra category:docs
+  after:2026-09-01 before:2026-10-01
+
+A filter prefixed with - is negated. Dates are midnight UTC; RFC 3339 times
+are accepted too. Quote the query for the shell as needed. With --fuzzy,
+words also match with typos, up to search.fuzzy_distance edits.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runSearch,
+}
+
+func init() {
+	searchCmd.Flags().Bool("json", false, "print the results as JSON")
+	searchCmd.Flags().Bool("fuzzy", false, "also match words with typos")
+	searchCmd.Flags().Int("limit", 20, "maximum number of complaints to list")
+	rootCmd.AddCommand(searchCmd)
+}
+
+func runSearch(cmd *cobra.Command, args []string) error {
+	logLevel, _ := cmd.Flags().GetString("log-level")
+	devMode, _ := cmd.Flags().GetBool("dev")
+	asJSON, _ := cmd.Flags().GetBool("json")
+	limit, _ := cmd.Flags().GetInt("limit")
+	fuzzy, _ := cmd.Flags().GetBool("fuzzy")
+
+	logger := newLogger(logLevel, devMode)
+	ctx := v2.WithContext(context.Background(), logger)
+
+	cfg, err := config.Load(ctx, cmd)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	tracer := tracing.NewNoOpTracer()
+
+	complaintRepo, err := repo.NewRepositoryFromConfig(cfg, tracer)
+	if err != nil {
+		return fmt.Errorf("failed to initialize repository: %w", err)
+	}
+
+	if closer, ok := complaintRepo.(io.Closer); ok {
+		defer closer.Close()
+	}
+
+	complaintService := service.NewComplaintService(complaintRepo, tracer)
+	complaintService.SetFuzziness(cfg.Search.FuzzyDistance)
+
+	query := strings.Join(args, " ")
+
+	hits, err := complaintService.SearchComplaintsRanked(ctx, query, limit, fuzzy)
+	if err != nil {
+		return err
+	}
+
+	if asJSON {
+		output := delivery.SearchComplaintsOutput{Query: query}
+
+		for _, hit := range hits {
+			output.Complaints = append(output.Complaints, delivery.ToDTO(hit.Complaint))
+			output.Hits = append(output.Hits, delivery.ToSearchHitDTO(hit))
+		}
+
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(output)
+	}
+
+	for _, hit := range hits {
+		printSearchHit(os.Stdout, hit)
+	}
+
+	return nil
+}
+
+// printSearchHit writes a complaint as a header line followed, if any words
+// matched, by an indented snippet.
+func printSearchHit(w io.Writer, hit repo.SearchHit) {
+	complaint := hit.Complaint
+
+	fmt.Fprintf(w, "%s  %s  %-8s  %-12s  %s\n", complaint.ID, complaint.Timestamp.Local().Format(time.DateTime),
+		complaint.Severity, complaint.ResolutionState, complaint.TaskDescription)
+
+	if hit.Snippet != "" {
+		fmt.Fprintf(w, "    %s: %s\n", hit.Field, hit.Snippet)
+	}
+}
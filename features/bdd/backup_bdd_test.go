@@ -0,0 +1,261 @@
+package bdd_test
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/larsartmann/complaints-mcp/internal/backup"
+	"github.com/larsartmann/complaints-mcp/internal/docs"
+	"github.com/larsartmann/complaints-mcp/internal/domain"
+	"github.com/larsartmann/complaints-mcp/internal/repo"
+	"github.com/larsartmann/complaints-mcp/internal/service"
+	"github.com/larsartmann/complaints-mcp/internal/tracing"
+	"github.com/larsartmann/complaints-mcp/internal/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Backup and Restore BDD Tests", func() {
+	var (
+		storageDir       string
+		projectDir       string
+		docsDir          string
+		tracer           tracing.Tracer
+		repository       *repo.FileRepository
+		complaintService *service.ComplaintService
+		manager          *backup.Manager
+	)
+
+	BeforeEach(func() {
+		storageDir = GinkgoT().TempDir()
+		projectDir = GinkgoT().TempDir()
+		docsDir = filepath.Join(GinkgoT().TempDir(), "docs", "complaints")
+		tracer = tracing.NewMockTracer("test")
+		repository = repo.NewFileRepository(storageDir, tracer)
+		complaintService = service.NewComplaintServiceWithDetector(
+			repository, tracer, fixedProjectDetector{root: projectDir},
+		)
+
+		exporter, err := docs.NewExporter(types.DocsConfig{
+			Dir:     "docs/complaints",
+			Format:  types.DocsFormatMarkdown,
+			Enabled: true,
+		}, storageDir)
+		Expect(err).NotTo(HaveOccurred())
+		complaintService.SetDocsExporter(exporter)
+
+		manager = backup.NewManager(repository, backup.Dir(storageDir), 2, time.Hour, tracer)
+	})
+
+	It("should restore complaints and documents after they are wiped", func(ctx SpecContext) {
+		complaint := fileTestComplaint(ctx, complaintService, testComplaint{
+			ProjectRoot: projectDir, Task: "Please back me up",
+		})
+		Expect(complaint.DocsPath).To(BeAnExistingFile())
+
+		path, manifest, err := manager.Snapshot(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(manifest.Complaints).To(Equal(1))
+		Expect(manifest.Docs).To(Equal(1))
+
+		Expect(os.RemoveAll(filepath.Join(storageDir, "complaints"))).To(Succeed())
+		Expect(os.RemoveAll(filepath.Join(projectDir, "docs"))).To(Succeed())
+
+		result, err := backup.Restore(ctx, path, repository, docsDir, false)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(backup.RestoreResult{Complaints: 1, Docs: 1}))
+
+		restored, err := repository.FindByID(ctx, complaint.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(restored.TaskDescription).To(Equal("Please back me up"))
+		Expect(restored.DocsPath).To(Equal(filepath.Join(docsDir, filepath.Base(complaint.DocsPath))))
+		Expect(restored.DocsPath).To(BeAnExistingFile())
+	})
+
+	It("should restore over newer copies with a higher version", func(ctx SpecContext) {
+		complaint := fileTestComplaint(ctx, complaintService, testComplaint{
+			ProjectRoot: projectDir, Task: "Restore me",
+		})
+
+		path, _, err := manager.Snapshot(ctx)
+		Expect(err).NotTo(HaveOccurred())
+
+		resolved, err := complaintService.ResolveComplaint(ctx, complaint.ID, "lead")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resolved.Version).To(BeNumerically(">", complaint.Version))
+
+		_, err = backup.Restore(ctx, path, repository, docsDir, false)
+		Expect(err).NotTo(HaveOccurred())
+
+		restored, err := repository.FindByID(ctx, complaint.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(restored.IsResolved()).To(BeFalse())
+		Expect(restored.Version).To(BeNumerically(">", resolved.Version))
+
+		// A client still holding the resolved copy cannot write over the restore
+		Expect(repository.Update(ctx, resolved)).To(HaveOccurred())
+	})
+
+	It("should only restore documents inside the docs directory", func(ctx SpecContext) {
+		fileTestComplaint(ctx, complaintService, testComplaint{
+			ProjectRoot: projectDir, Task: "Point my document elsewhere",
+		})
+
+		path, _, err := manager.Snapshot(ctx)
+		Expect(err).NotTo(HaveOccurred())
+
+		// The manifest is not checksummed, so anyone can rewrite the paths in it
+		outside := filepath.Join(GinkgoT().TempDir(), ".ssh", "authorized_keys")
+		tampered := filepath.Join(GinkgoT().TempDir(), "tampered.tar.gz")
+		rewriteSnapshot(path, tampered, func(name string, data []byte) []byte {
+			if name != "manifest.json" {
+				return data
+			}
+
+			var manifest backup.Manifest
+			Expect(json.Unmarshal(data, &manifest)).To(Succeed())
+
+			for i := range manifest.Entries {
+				if manifest.Entries[i].Kind == "doc" {
+					manifest.Entries[i].RestorePath = outside
+				}
+			}
+
+			data, err := json.Marshal(manifest)
+			Expect(err).NotTo(HaveOccurred())
+
+			return data
+		})
+
+		result, err := backup.Restore(ctx, tampered, repository, docsDir, false)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Docs).To(Equal(1))
+		Expect(outside).NotTo(BeAnExistingFile())
+		Expect(filepath.Dir(outside)).NotTo(BeADirectory())
+
+		entries, err := os.ReadDir(docsDir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(entries).To(HaveLen(1))
+	})
+
+	It("should keep only the newest snapshots", func(ctx SpecContext) {
+		fileTestComplaint(ctx, complaintService, testComplaint{ProjectRoot: projectDir, Task: "Rotate me"})
+
+		var paths []string
+
+		for range 3 {
+			path, _, err := manager.Snapshot(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			paths = append(paths, path)
+		}
+
+		snapshots, err := manager.Snapshots()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(snapshots).To(Equal(paths[1:]))
+	})
+
+	It("should validate without writing on a dry run", func(ctx SpecContext) {
+		complaint := fileTestComplaint(ctx, complaintService, testComplaint{ProjectRoot: projectDir, Task: "Dry run"})
+
+		path, _, err := manager.Snapshot(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(repository.Delete(ctx, complaint.ID)).To(Succeed())
+
+		result, err := backup.Restore(ctx, path, repository, docsDir, true)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Complaints).To(Equal(1))
+
+		_, err = repository.FindByID(ctx, complaint.ID)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should refuse a snapshot whose contents do not match the manifest", func(ctx SpecContext) {
+		complaint := fileTestComplaint(ctx, complaintService, testComplaint{
+			ProjectRoot: projectDir, Task: "Tamper with me",
+		})
+
+		path, _, err := manager.Snapshot(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(repository.Delete(ctx, complaint.ID)).To(Succeed())
+
+		tampered := filepath.Join(GinkgoT().TempDir(), "tampered.tar.gz")
+		rewriteSnapshot(path, tampered, func(name string, data []byte) []byte {
+			if filepath.Ext(name) == ".json" && name != "manifest.json" {
+				return append(data, ' ')
+			}
+
+			return data
+		})
+
+		_, err = backup.Restore(ctx, tampered, repository, docsDir, false)
+		Expect(err).To(MatchError(ContainSubstring("checksum mismatch")))
+
+		_, err = repository.FindByID(ctx, complaint.ID)
+		Expect(err).To(HaveOccurred(), "nothing may be restored from a bad snapshot")
+	})
+
+	It("should refuse to restore complaints that fail validation", func(ctx SpecContext) {
+		id, err := domain.NewComplaintID()
+		Expect(err).NotTo(HaveOccurred())
+
+		// Written behind the repository's back, so it never went through Validate
+		Expect(os.MkdirAll(filepath.Join(storageDir, "complaints"), 0o755)).To(Succeed())
+		Expect(os.WriteFile(
+			filepath.Join(storageDir, "complaints", id.String()+".json"),
+			[]byte(`{"id":"`+id.String()+`","task_description":"","severity":"bogus"}`),
+			0o644,
+		)).To(Succeed())
+
+		path, _, err := manager.Snapshot(ctx)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = backup.Restore(ctx, path, repository, docsDir, true)
+		Expect(err).To(MatchError(ContainSubstring("invalid complaint")))
+	})
+})
+
+// rewriteSnapshot copies a snapshot, passing every entry through edit.
+func rewriteSnapshot(src, dst string, edit func(name string, data []byte) []byte) {
+	in, err := os.Open(src)
+	Expect(err).NotTo(HaveOccurred())
+	defer in.Close()
+
+	gzIn, err := gzip.NewReader(in)
+	Expect(err).NotTo(HaveOccurred())
+
+	out, err := os.Create(dst)
+	Expect(err).NotTo(HaveOccurred())
+	defer out.Close()
+
+	gzOut := gzip.NewWriter(out)
+	tr := tar.NewReader(gzIn)
+	tw := tar.NewWriter(gzOut)
+
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+
+		Expect(err).NotTo(HaveOccurred())
+
+		data, err := io.ReadAll(tr)
+		Expect(err).NotTo(HaveOccurred())
+
+		data = edit(header.Name, data)
+		header.Size = int64(len(data))
+
+		Expect(tw.WriteHeader(header)).To(Succeed())
+		_, err = tw.Write(data)
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	Expect(tw.Close()).To(Succeed())
+	Expect(gzOut.Close()).To(Succeed())
+}
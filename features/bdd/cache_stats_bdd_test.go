@@ -1,13 +1,18 @@
 package bdd_test
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"os"
+	"time"
 
+	"github.com/larsartmann/complaints-mcp/internal/config"
 	"github.com/larsartmann/complaints-mcp/internal/domain"
 	"github.com/larsartmann/complaints-mcp/internal/repo"
 	"github.com/larsartmann/complaints-mcp/internal/service"
 	"github.com/larsartmann/complaints-mcp/internal/tracing"
+	"github.com/larsartmann/complaints-mcp/internal/types"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
@@ -203,3 +208,178 @@ var _ = Describe("Cache Statistics BDD Tests", func() {
 		})
 	})
 })
+
+var _ = Describe("Cache Eviction Policy BDD Tests", func() {
+	var (
+		tempDir          string
+		tracer           tracing.Tracer
+		complaintService *service.ComplaintService
+	)
+
+	newCachedService := func(policy types.CacheEvictionPolicy) {
+		base := repo.NewFileRepository(tempDir, tracer)
+		cached := repo.NewSimpleCachedRepository(base, 2, policy)
+		complaintService = service.NewComplaintService(cached, tracer)
+	}
+
+	expectLookup := func(ctx SpecContext, id domain.ComplaintID, hit bool) {
+		before := complaintService.GetCacheStats()
+
+		_, err := complaintService.GetComplaint(ctx, id)
+		Expect(err).NotTo(HaveOccurred())
+
+		after := complaintService.GetCacheStats()
+		if hit {
+			Expect(after.Hits).To(Equal(before.Hits + 1))
+		} else {
+			Expect(after.Misses).To(Equal(before.Misses + 1))
+		}
+	}
+
+	BeforeEach(func() {
+		tempDir = GinkgoT().TempDir()
+		tracer = tracing.NewMockTracer("test")
+	})
+
+	It("should evict the least recently used entry under LRU", func(ctx SpecContext) {
+		newCachedService(types.EvictionLRU)
+
+		first := fileTestComplaint(ctx, complaintService, testComplaint{Task: "First"})
+		second := fileTestComplaint(ctx, complaintService, testComplaint{Task: "Second"})
+		expectLookup(ctx, first.ID, true)
+
+		fileTestComplaint(ctx, complaintService, testComplaint{Task: "Third"})
+
+		stats := complaintService.GetCacheStats()
+		Expect(stats.Evictions).To(Equal(int64(1)))
+		Expect(stats.CurrentSize).To(Equal(int64(2)))
+		expectLookup(ctx, first.ID, true)
+		expectLookup(ctx, second.ID, false)
+	})
+
+	It("should evict the oldest inserted entry under FIFO", func(ctx SpecContext) {
+		newCachedService(types.EvictionFIFO)
+
+		first := fileTestComplaint(ctx, complaintService, testComplaint{Task: "First"})
+		second := fileTestComplaint(ctx, complaintService, testComplaint{Task: "Second"})
+		expectLookup(ctx, first.ID, true)
+
+		fileTestComplaint(ctx, complaintService, testComplaint{Task: "Third"})
+
+		Expect(complaintService.GetCacheStats().Evictions).To(Equal(int64(1)))
+		expectLookup(ctx, second.ID, true)
+		expectLookup(ctx, first.ID, false)
+	})
+
+	It("should stop admitting entries when full under none", func(ctx SpecContext) {
+		newCachedService(types.EvictionNone)
+
+		fileTestComplaint(ctx, complaintService, testComplaint{Task: "First"})
+		fileTestComplaint(ctx, complaintService, testComplaint{Task: "Second"})
+		third := fileTestComplaint(ctx, complaintService, testComplaint{Task: "Third"})
+
+		stats := complaintService.GetCacheStats()
+		Expect(stats.Evictions).To(Equal(int64(0)))
+		Expect(stats.CurrentSize).To(Equal(int64(2)))
+		expectLookup(ctx, third.ID, false)
+		expectLookup(ctx, third.ID, false)
+	})
+
+	It("should wrap the configured backend when caching is enabled", func() {
+		cfg := &config.Config{Storage: config.StorageConfig{
+			BaseDir:        tempDir,
+			CacheEnabled:   true,
+			CacheSize:      types.CacheSize(5),
+			EvictionPolicy: types.EvictionFIFO,
+		}}
+
+		repository, err := repo.NewRepositoryFromConfig(cfg, tracer)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(repository).To(BeAssignableToTypeOf(&repo.IndexedRepository{}))
+		Expect(repository.(*repo.IndexedRepository).Repository).To(BeAssignableToTypeOf(&repo.SimpleCachedRepository{}))
+		Expect(repository.GetCacheStats().MaxCacheSize).To(Equal(int64(5)))
+
+		cfg.Storage.CacheEnabled = false
+
+		repository, err = repo.NewRepositoryFromConfig(cfg, tracer)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(repository).To(BeAssignableToTypeOf(&repo.IndexedRepository{}))
+		Expect(repository.(*repo.IndexedRepository).Repository).To(BeAssignableToTypeOf(&repo.FileRepository{}))
+	})
+})
+
+var _ = Describe("Cache Warm-up BDD Tests", func() {
+	var (
+		tempDir    string
+		tracer     tracing.Tracer
+		fileRepo   *repo.FileRepository
+		complaints []*domain.Complaint
+	)
+
+	BeforeEach(func(ctx SpecContext) {
+		tempDir = GinkgoT().TempDir()
+		tracer = tracing.NewMockTracer("test")
+		fileRepo = repo.NewFileRepository(tempDir, tracer)
+		complaintService := service.NewComplaintService(fileRepo, tracer)
+		complaints = nil
+
+		base := time.Now().Add(-time.Hour)
+
+		for i := range 5 {
+			complaint := fileTestComplaint(ctx, complaintService, testComplaint{Task: fmt.Sprintf("Warm task %d", i)})
+
+			// Give every file a distinct modification time so "most recent" is well defined
+			path, err := fileRepo.GetFilePath(ctx, complaint.ID)
+			Expect(err).NotTo(HaveOccurred())
+
+			mtime := base.Add(time.Duration(i) * time.Minute)
+			Expect(os.Chtimes(path, mtime, mtime)).To(Succeed())
+
+			complaints = append(complaints, complaint)
+		}
+	})
+
+	It("should preload the most recent complaints up to the cache size", func(ctx SpecContext) {
+		cached := repo.NewSimpleCachedRepository(fileRepo, 3, types.EvictionLRU)
+
+		Expect(cached.WarmCache(ctx)).To(Succeed())
+
+		stats := cached.GetCacheStats()
+		Expect(stats.WarmedComplaints).To(Equal(int64(3)))
+		Expect(stats.WarmSkipped).To(Equal(int64(0)))
+		Expect(stats.CurrentSize).To(Equal(int64(3)))
+
+		_, err := cached.FindByID(ctx, complaints[4].ID)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = cached.FindByID(ctx, complaints[0].ID)
+		Expect(err).NotTo(HaveOccurred())
+
+		stats = cached.GetCacheStats()
+		Expect(stats.Hits).To(Equal(int64(1)), "newest complaint should be warm")
+		Expect(stats.Misses).To(Equal(int64(1)), "oldest complaint should be cold")
+	})
+
+	It("should skip and count unparsable complaint files", func(ctx SpecContext) {
+		id, err := domain.NewComplaintID()
+		Expect(err).NotTo(HaveOccurred())
+
+		path, err := fileRepo.GetFilePath(ctx, id)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.WriteFile(path, []byte("{not json"), 0o644)).To(Succeed())
+
+		cached := repo.NewSimpleCachedRepository(fileRepo, 10, types.EvictionLRU)
+		Expect(cached.WarmCache(ctx)).To(Succeed())
+
+		stats := cached.GetCacheStats()
+		Expect(stats.WarmedComplaints).To(Equal(int64(5)))
+		Expect(stats.WarmSkipped).To(Equal(int64(1)))
+	})
+
+	It("should stop when the context is cancelled", func(ctx SpecContext) {
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		cached := repo.NewSimpleCachedRepository(fileRepo, 10, types.EvictionLRU)
+		Expect(cached.WarmCache(cancelled)).To(MatchError(context.Canceled))
+	})
+})
@@ -0,0 +1,105 @@
+package bdd_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/larsartmann/complaints-mcp/internal/docs"
+	"github.com/larsartmann/complaints-mcp/internal/domain"
+	"github.com/larsartmann/complaints-mcp/internal/repo"
+	"github.com/larsartmann/complaints-mcp/internal/service"
+	"github.com/larsartmann/complaints-mcp/internal/tracing"
+	"github.com/larsartmann/complaints-mcp/internal/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Complaint Code References BDD Tests", func() {
+	var (
+		storageDir       string
+		projectDir       string
+		repository       *repo.FileRepository
+		complaintService *service.ComplaintService
+	)
+
+	fileComplaint := func(ctx context.Context, refs ...domain.CodeRef) (*domain.Complaint, error) {
+		return complaintService.CreateComplaint(ctx,
+			"Reference Agent", "reference-session", "Confused by the exporter",
+			"", "", "", "", domain.SeverityMedium, "reference-project", projectDir,
+			service.WithReferences(refs...))
+	}
+
+	BeforeEach(func() {
+		storageDir = GinkgoT().TempDir()
+		projectDir = GinkgoT().TempDir()
+
+		source := "package docs\n\nfunc Export() {\n\t// ...\n}\n"
+		Expect(os.MkdirAll(filepath.Join(projectDir, "internal", "docs"), 0o755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(projectDir, "internal", "docs", "exporter.go"), []byte(source), 0o644)).
+			To(Succeed())
+
+		tracer := tracing.NewMockTracer("test")
+		repository = repo.NewFileRepository(storageDir, tracer)
+		complaintService = service.NewComplaintServiceWithDetector(
+			repository, tracer, fixedProjectDetector{root: projectDir},
+		)
+	})
+
+	Context("When filing a complaint with references", func() {
+		It("should store references relative to the repository root", func(ctx SpecContext) {
+			complaint := fileTestComplaint(ctx, complaintService, testComplaint{
+				ProjectRoot: projectDir,
+				Options: []service.ComplaintOption{service.WithReferences(
+					domain.CodeRef{Path: "internal/docs/exporter.go", StartLine: 3, EndLine: 5, Symbol: "Export"},
+					domain.CodeRef{Path: filepath.Join(projectDir, "internal", "docs"), Commit: "A91FE57"},
+				)},
+			})
+
+			stored, err := repository.FindByID(ctx, complaint.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stored.References).To(Equal([]domain.CodeRef{
+				{Path: "internal/docs/exporter.go", StartLine: 3, EndLine: 5, Symbol: "Export"},
+				{Path: "internal/docs", Commit: "a91fe57"},
+			}))
+		})
+
+		It("should reject references the repository does not have", func(ctx SpecContext) {
+			_, err := fileComplaint(ctx, domain.CodeRef{Path: "internal/missing.go"})
+			Expect(err).To(MatchError(ContainSubstring("reference internal/missing.go not found")))
+
+			_, err = fileComplaint(ctx, domain.CodeRef{Path: "internal/docs/exporter.go", StartLine: 3, EndLine: 40})
+			Expect(err).To(MatchError(ContainSubstring("line 40 is past the end of the file (5 lines)")))
+
+			_, err = fileComplaint(ctx, domain.CodeRef{Path: filepath.Join(os.TempDir(), "outside.go")})
+			Expect(err).To(MatchError(ContainSubstring("is outside the repository")))
+
+			complaints, err := repository.FindAll(ctx, 10, 0)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(complaints).To(BeEmpty())
+		})
+	})
+
+	It("should link references from the exported document", func(ctx SpecContext) {
+		exporter, err := docs.NewExporter(types.DocsConfig{
+			Dir:     "docs/complaints",
+			Format:  types.DocsFormatMarkdown,
+			Enabled: true,
+		}, storageDir)
+		Expect(err).NotTo(HaveOccurred())
+		complaintService.SetDocsExporter(exporter)
+
+		complaint := fileTestComplaint(ctx, complaintService, testComplaint{
+			ProjectRoot: projectDir,
+			Options: []service.ComplaintOption{service.WithReferences(
+				domain.CodeRef{Path: "internal/docs/exporter.go", StartLine: 3, EndLine: 5, Symbol: "Export"},
+			)},
+		})
+
+		content, err := os.ReadFile(complaint.DocsPath)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(content)).To(ContainSubstring("## Code References"))
+		Expect(string(content)).To(ContainSubstring(
+			"- [`internal/docs/exporter.go:3-5 (Export)`](../../internal/docs/exporter.go#L3-L5)"))
+	})
+})
@@ -0,0 +1,122 @@
+package bdd_test
+
+import (
+	"context"
+	"os"
+
+	"github.com/larsartmann/complaints-mcp/internal/docs"
+	"github.com/larsartmann/complaints-mcp/internal/domain"
+	"github.com/larsartmann/complaints-mcp/internal/repo"
+	"github.com/larsartmann/complaints-mcp/internal/service"
+	"github.com/larsartmann/complaints-mcp/internal/tracing"
+	"github.com/larsartmann/complaints-mcp/internal/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Complaint Comments BDD Tests", func() {
+	var (
+		storageDir string
+		projectDir string
+		tracer     tracing.Tracer
+	)
+
+	// discuss leaves a follow-up from the filing agent and a maintainer's question.
+	discuss := func(ctx context.Context, complaintService *service.ComplaintService, id domain.ComplaintID) {
+		_, _, err := complaintService.AddComment(ctx, id,
+			"BDD Agent", domain.CommentAuthorAgent, "Found the missing config key later", 0)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, _, err = complaintService.AddComment(ctx, id,
+			"maintainer", domain.CommentAuthorHuman, "Which version were you on?", 0)
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	expectThread := func(ctx context.Context, complaintService *service.ComplaintService, id domain.ComplaintID) {
+		comments, err := complaintService.ListComments(ctx, id)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(comments).To(HaveLen(2))
+
+		Expect(comments[0].Author).To(Equal("BDD Agent"))
+		Expect(comments[0].AuthorType).To(Equal(domain.CommentAuthorAgent))
+		Expect(comments[1].Author).To(Equal("maintainer"))
+		Expect(comments[1].AuthorType).To(Equal(domain.CommentAuthorHuman))
+		Expect(comments[1].Body).To(Equal("Which version were you on?"))
+		Expect(comments[0].ID).NotTo(Equal(comments[1].ID))
+	}
+
+	BeforeEach(func() {
+		storageDir = GinkgoT().TempDir()
+		projectDir = GinkgoT().TempDir()
+		tracer = tracing.NewMockTracer("test")
+	})
+
+	DescribeTable("should keep the thread in every storage backend",
+		func(ctx SpecContext, newRepository func() repo.Repository) {
+			complaintService := service.NewComplaintServiceWithDetector(
+				newRepository(), tracer, fixedProjectDetector{root: projectDir},
+			)
+
+			complaint := fileTestComplaint(ctx, complaintService, testComplaint{ProjectRoot: projectDir})
+			discuss(ctx, complaintService, complaint.ID)
+
+			// A fresh repository reads the thread back from storage
+			expectThread(ctx, service.NewComplaintService(newRepository(), tracer), complaint.ID)
+		},
+		Entry("file", func() repo.Repository {
+			return repo.NewFileRepository(storageDir, tracer)
+		}),
+		Entry("sqlite", func() repo.Repository {
+			repository, err := repo.NewSQLiteRepository(storageDir, tracer)
+			Expect(err).NotTo(HaveOccurred())
+			DeferCleanup(repository.Close)
+
+			return repository
+		}),
+		Entry("dual", func() repo.Repository {
+			return repo.NewDualRepository(storageDir, tracer)
+		}),
+	)
+
+	It("should record comments in the history and the rendered document", func(ctx SpecContext) {
+		complaintService := service.NewComplaintServiceWithDetector(
+			repo.NewFileRepository(storageDir, tracer), tracer, fixedProjectDetector{root: projectDir},
+		)
+
+		exporter, err := docs.NewExporter(types.DocsConfig{
+			Dir:     "docs/complaints",
+			Format:  types.DocsFormatMarkdown,
+			Enabled: true,
+		}, storageDir)
+		Expect(err).NotTo(HaveOccurred())
+		complaintService.SetDocsExporter(exporter)
+
+		complaint := fileTestComplaint(ctx, complaintService, testComplaint{ProjectRoot: projectDir})
+		discuss(ctx, complaintService, complaint.ID)
+
+		events, err := complaintService.GetComplaintHistory(ctx, complaint.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(events).To(HaveLen(3))
+		Expect(events[2].Action).To(Equal(domain.EventCommented))
+		Expect(events[2].Actor).To(Equal("maintainer"))
+
+		content, err := os.ReadFile(complaint.DocsPath)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(content)).To(ContainSubstring("## Comments"))
+		Expect(string(content)).To(ContainSubstring("**maintainer** (human)"))
+		Expect(string(content)).To(ContainSubstring("Found the missing config key later"))
+	})
+
+	It("should reject empty comments without touching the complaint", func(ctx SpecContext) {
+		complaintService := service.NewComplaintService(repo.NewFileRepository(storageDir, tracer), tracer)
+		complaint := fileTestComplaint(ctx, complaintService, testComplaint{ProjectRoot: projectDir})
+
+		_, _, err := complaintService.AddComment(ctx, complaint.ID, "maintainer", domain.CommentAuthorHuman, "   ", 0)
+		Expect(err).To(MatchError(ContainSubstring("comment body cannot be empty")))
+
+		stored, err := complaintService.GetComplaint(ctx, complaint.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stored.Comments).To(BeEmpty())
+		Expect(stored.Version).To(Equal(uint64(1)))
+	})
+})
@@ -0,0 +1,125 @@
+package bdd_test
+
+import (
+	"github.com/larsartmann/complaints-mcp/internal/domain"
+	"github.com/larsartmann/complaints-mcp/internal/repo"
+	"github.com/larsartmann/complaints-mcp/internal/service"
+	"github.com/larsartmann/complaints-mcp/internal/tracing"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Complaint Duplicates BDD Tests", func() {
+	var (
+		repository       *repo.FileRepository
+		complaintService *service.ComplaintService
+	)
+
+	BeforeEach(func() {
+		tracer := tracing.NewMockTracer("test")
+		repository = repo.NewFileRepository(GinkgoT().TempDir(), tracer)
+		complaintService = service.NewComplaintService(repository, tracer)
+	})
+
+	Context("When filing a complaint", func() {
+		It("should suggest similar open complaints in the same project", func(ctx SpecContext) {
+			original := fileTestComplaint(ctx, complaintService, testComplaint{
+				Agent: "Agent One", Project: "readme-project", Missing: "The README is missing an installation section",
+			})
+			fileTestComplaint(ctx, complaintService, testComplaint{
+				Agent: "Agent Two", Project: "other-project", Missing: "The README is missing an installation section",
+			})
+			fileTestComplaint(ctx, complaintService, testComplaint{
+				Agent: "Agent Three", Project: "readme-project", Missing: "No idea which database migrations to run",
+			})
+
+			duplicate := fileTestComplaint(ctx, complaintService, testComplaint{
+				Agent: "Agent Four", Project: "readme-project", Missing: "README missing the installation section",
+			})
+			Expect(duplicate.SimilarTo).To(HaveLen(1))
+			Expect(duplicate.SimilarTo[0].ID).To(Equal(original.ID))
+			Expect(duplicate.SimilarTo[0].Score).To(BeNumerically(">=", domain.LikelyDuplicateScore))
+
+			stored, err := repository.FindByID(ctx, duplicate.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stored.SimilarTo).To(Equal(duplicate.SimilarTo))
+		})
+
+		It("should not suggest closed complaints", func(ctx SpecContext) {
+			original := fileTestComplaint(ctx, complaintService, testComplaint{
+				Agent: "Agent One", Project: "readme-project", Missing: "The README is missing an installation section",
+			})
+			_, err := complaintService.ResolveComplaint(ctx, original.ID, "maintainer")
+			Expect(err).NotTo(HaveOccurred())
+
+			duplicate := fileTestComplaint(ctx, complaintService, testComplaint{
+				Agent: "Agent Two", Project: "readme-project", Missing: "The README is missing an installation section",
+			})
+			Expect(duplicate.SimilarTo).To(BeEmpty())
+		})
+	})
+
+	Context("When marking duplicates", func() {
+		It("should close the duplicate and aggregate reports on the original", func(ctx SpecContext) {
+			original := fileTestComplaint(ctx, complaintService, testComplaint{
+				Agent: "Agent One", Project: "readme-project", Missing: "The README is missing an installation section",
+			})
+			second := fileTestComplaint(ctx, complaintService, testComplaint{
+				Agent: "Agent Two", Project: "readme-project", Missing: "README has no installation section",
+			})
+			third := fileTestComplaint(ctx, complaintService, testComplaint{
+				Agent: "Agent Three", Project: "readme-project", Missing: "Installation section missing from README",
+			})
+
+			marked, err := complaintService.MarkDuplicate(ctx, second.ID, original.ID, "triager", "", 0)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(marked.ResolutionState).To(Equal(domain.ResolutionStateDuplicate))
+			Expect(marked.DuplicateOf).To(Equal(original.ID))
+			transitions := marked.Transitions()
+			Expect(transitions[len(transitions)-1].Reason).To(Equal("duplicate of " + original.ID.String()))
+
+			// Marking against a duplicate links to the original it duplicates
+			marked, err = complaintService.MarkDuplicate(ctx, third.ID, second.ID, "triager", "same README gap", 0)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(marked.DuplicateOf).To(Equal(original.ID))
+
+			stored, err := repository.FindByID(ctx, original.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stored.Duplicates).To(Equal([]domain.ComplaintID{second.ID, third.ID}))
+			Expect(stored.IsClosed()).To(BeFalse())
+
+			events, err := complaintService.GetComplaintHistory(ctx, original.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(events[len(events)-1].Action).To(Equal(domain.EventDuplicateLinked))
+
+			// Marking again changes nothing
+			again, err := complaintService.MarkDuplicate(ctx, second.ID, original.ID, "triager", "", 0)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(again.Version).To(Equal(uint64(2)))
+
+			stored, err = repository.FindByID(ctx, original.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stored.Duplicates).To(HaveLen(2))
+		})
+
+		It("should reject self links and links across projects", func(ctx SpecContext) {
+			original := fileTestComplaint(ctx, complaintService, testComplaint{
+				Agent: "Agent One", Project: "readme-project", Missing: "The README is missing an installation section",
+			})
+			other := fileTestComplaint(ctx, complaintService, testComplaint{
+				Agent: "Agent Two", Project: "other-project", Missing: "The README is missing an installation section",
+			})
+
+			_, err := complaintService.MarkDuplicate(ctx, original.ID, original.ID, "triager", "", 0)
+			Expect(err).To(MatchError(ContainSubstring("cannot be a duplicate of itself")))
+
+			_, err = complaintService.MarkDuplicate(ctx, other.ID, original.ID, "triager", "", 0)
+			Expect(err).To(MatchError(ContainSubstring("same project")))
+
+			stored, err := repository.FindByID(ctx, other.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stored.IsClosed()).To(BeFalse())
+			Expect(stored.DuplicateOf.IsZero()).To(BeTrue())
+		})
+	})
+})
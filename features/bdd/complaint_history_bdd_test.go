@@ -0,0 +1,115 @@
+package bdd_test
+
+import (
+	"github.com/larsartmann/complaints-mcp/internal/domain"
+	apperrors "github.com/larsartmann/complaints-mcp/internal/errors"
+	"github.com/larsartmann/complaints-mcp/internal/repo"
+	"github.com/larsartmann/complaints-mcp/internal/service"
+	"github.com/larsartmann/complaints-mcp/internal/tracing"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Complaint History BDD Tests", func() {
+	var (
+		tracer           tracing.Tracer
+		repository       *repo.FileRepository
+		complaintService *service.ComplaintService
+	)
+
+	BeforeEach(func() {
+		tracer = tracing.NewMockTracer("test")
+		repository = repo.NewFileRepository(GinkgoT().TempDir(), tracer)
+		complaintService = service.NewComplaintService(repository, tracer)
+	})
+
+	It("should record who filed, moved and closed a complaint, and through which tool", func(ctx SpecContext) {
+		complaint := fileTestComplaint(service.WithTool(ctx, "file_complaint"), complaintService, testComplaint{})
+
+		_, err := complaintService.TransitionComplaint(service.WithTool(ctx, "acknowledge_complaint"),
+			complaint.ID, domain.ResolutionStateAcknowledged, "triager", "looking into it", 0)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = complaintService.ResolveComplaint(service.WithTool(ctx, "resolve_complaint"),
+			complaint.ID, "fixer")
+		Expect(err).NotTo(HaveOccurred())
+
+		events, err := complaintService.GetComplaintHistory(ctx, complaint.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(events).To(HaveLen(3))
+
+		Expect(events[0].Action).To(Equal(domain.EventFiled))
+		Expect(events[0].Actor).To(Equal("BDD Agent"))
+		Expect(events[0].Tool).To(Equal("file_complaint"))
+
+		Expect(events[1].Action).To(Equal(domain.EventStateChanged))
+		Expect(events[1].Actor).To(Equal("triager"))
+		Expect(events[1].Tool).To(Equal("acknowledge_complaint"))
+		Expect(events[1].Reason).To(Equal("looking into it"))
+		Expect(events[1].Changes).To(ContainElement(domain.FieldChange{
+			Field: "resolution_state", Old: "open", New: "acknowledged",
+		}))
+
+		Expect(events[2].Action).To(Equal(domain.EventResolved))
+		Expect(events[2].Actor).To(Equal("fixer"))
+		Expect(events[2].Tool).To(Equal("resolve_complaint"))
+		Expect(events[2].Changes).To(ContainElement(domain.FieldChange{
+			Field: "resolved_by", Old: "", New: "fixer",
+		}))
+
+		for i := 1; i < len(events); i++ {
+			Expect(events[i].At).NotTo(BeTemporally("<", events[i-1].At), "events are ordered oldest first")
+		}
+	})
+
+	It("should keep the original resolver when a resolved complaint is resolved again", func(ctx SpecContext) {
+		complaint := fileTestComplaint(ctx, complaintService, testComplaint{})
+
+		_, err := complaintService.ResolveComplaint(ctx, complaint.ID, "first-resolver")
+		Expect(err).NotTo(HaveOccurred())
+
+		again, err := complaintService.ResolveComplaint(ctx, complaint.ID, "second-resolver")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(again.ResolvedBy).To(Equal("first-resolver"))
+
+		stored, err := repository.FindByID(ctx, complaint.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stored.ResolvedBy).To(Equal("first-resolver"))
+		Expect(stored.Version).To(Equal(uint64(2)), "a no-op resolve is not written")
+		Expect(stored.History).To(HaveLen(2))
+	})
+
+	It("should record old and new values of edited fields", func(ctx SpecContext) {
+		complaint := fileTestComplaint(ctx, complaintService, testComplaint{Severity: domain.SeverityHigh})
+
+		before := complaint.Clone()
+		complaint.Severity = domain.SeverityLow
+
+		Expect(complaint.Record(before, domain.Event{Actor: "editor", Action: "edited"})).To(BeTrue())
+		Expect(complaint.History).To(HaveLen(2))
+		Expect(complaint.History[1].Changes).To(Equal([]domain.FieldChange{
+			{Field: "severity", Old: "high", New: "low"},
+		}))
+
+		Expect(complaint.Record(complaint.Clone(), domain.Event{Actor: "editor", Action: "edited"})).
+			To(BeFalse(), "unchanged complaints record nothing")
+	})
+
+	It("should refuse updates that rewrite history", func(ctx SpecContext) {
+		complaint := fileTestComplaint(ctx, complaintService, testComplaint{})
+
+		stored, err := repository.FindByID(ctx, complaint.ID)
+		Expect(err).NotTo(HaveOccurred())
+
+		stored.History = nil
+		err = repository.Update(ctx, stored)
+
+		appErr, ok := apperrors.IsAppError(err)
+		Expect(ok).To(BeTrue(), "expected an AppError, got %v", err)
+		Expect(appErr.Code).To(Equal(apperrors.ErrCodeValidation))
+
+		events, err := complaintService.GetComplaintHistory(ctx, complaint.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(events).To(HaveLen(1))
+	})
+})
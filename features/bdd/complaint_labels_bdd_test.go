@@ -0,0 +1,148 @@
+package bdd_test
+
+import (
+	"context"
+
+	"github.com/larsartmann/complaints-mcp/internal/domain"
+	"github.com/larsartmann/complaints-mcp/internal/query"
+	"github.com/larsartmann/complaints-mcp/internal/repo"
+	"github.com/larsartmann/complaints-mcp/internal/service"
+	"github.com/larsartmann/complaints-mcp/internal/tracing"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Complaint Tags and Categories BDD Tests", func() {
+	var (
+		repository       *repo.FileRepository
+		complaintService *service.ComplaintService
+	)
+
+	fileComplaint := func(ctx context.Context, opts ...service.ComplaintOption) (*domain.Complaint, error) {
+		return complaintService.CreateComplaint(ctx,
+			"Label Agent", "label-session", "Labelled complaint",
+			"", "", "", "", domain.SeverityMedium, "label-project", "", opts...)
+	}
+
+	mustFile := func(ctx context.Context, opts ...service.ComplaintOption) *domain.Complaint {
+		return fileTestComplaint(ctx, complaintService, testComplaint{Options: opts})
+	}
+
+	BeforeEach(func() {
+		tracer := tracing.NewMockTracer("test")
+		repository = repo.NewFileRepository(GinkgoT().TempDir(), tracer)
+		complaintService = service.NewComplaintService(repository, tracer)
+	})
+
+	Context("When filing a complaint", func() {
+		It("should store normalized tags and categories", func(ctx SpecContext) {
+			complaint := mustFile(ctx,
+				service.WithTags(" Cobra ", "viper", "cobra", ""),
+				service.WithCategories("Docs", "build"))
+
+			stored, err := repository.FindByID(ctx, complaint.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stored.Tags).To(Equal([]string{"cobra", "viper"}))
+			Expect(stored.Categories).To(Equal([]string{"docs", "build"}))
+		})
+
+		It("should reject categories outside the configured vocabulary", func(ctx SpecContext) {
+			_, err := fileComplaint(ctx, service.WithCategories("feelings"))
+			Expect(err).To(MatchError(ContainSubstring(`unknown category "feelings"`)))
+
+			complaintService.SetCategories([]string{"feelings"})
+
+			_, err = fileComplaint(ctx, service.WithCategories("feelings"))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should reject malformed tags in Validate", func(ctx SpecContext) {
+			_, err := fileComplaint(ctx, service.WithTags("has space"))
+			Expect(err).To(MatchError(ContainSubstring(`invalid tags entry: "has space"`)))
+
+			complaint := mustFile(ctx)
+			complaint.Tags = []string{"dup", "dup"}
+			Expect(complaint.Validate()).To(MatchError(ContainSubstring("duplicate tags entry")))
+		})
+	})
+
+	Context("When editing labels", func() {
+		It("should add and remove tags and record the change", func(ctx SpecContext) {
+			complaint := mustFile(ctx, service.WithTags("cobra", "flags"))
+
+			tagged, err := complaintService.TagComplaint(ctx, complaint.ID, "triager",
+				[]string{"Viper"}, []string{"flags"}, 0)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tagged.Tags).To(Equal([]string{"cobra", "viper"}))
+
+			events, err := complaintService.GetComplaintHistory(ctx, complaint.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(events[len(events)-1].Action).To(Equal(domain.EventTagged))
+			Expect(events[len(events)-1].Changes).To(ConsistOf(domain.FieldChange{
+				Field: "tags", Old: "cobra,flags", New: "cobra,viper",
+			}))
+		})
+
+		It("should replace categories from the vocabulary only", func(ctx SpecContext) {
+			complaint := mustFile(ctx, service.WithCategories("docs"))
+
+			categorized, err := complaintService.CategorizeComplaint(ctx, complaint.ID, "triager",
+				[]string{"api", "tests"}, 0)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(categorized.Categories).To(Equal([]string{"api", "tests"}))
+
+			_, err = complaintService.CategorizeComplaint(ctx, complaint.ID, "triager",
+				[]string{"unknown"}, 0)
+			Expect(err).To(MatchError(ContainSubstring("unknown category")))
+
+			stored, err := repository.FindByID(ctx, complaint.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stored.Categories).To(Equal([]string{"api", "tests"}))
+		})
+	})
+
+	Context("When filtering by labels", func() {
+		It("should support any and all semantics", func(ctx SpecContext) {
+			both := mustFile(ctx, service.WithTags("cobra", "viper"), service.WithCategories("docs"))
+			cobraOnly := mustFile(ctx, service.WithTags("cobra"), service.WithCategories("build"))
+			untagged := mustFile(ctx)
+
+			matching := func(filter domain.LabelFilter) []domain.ComplaintID {
+				var ids []domain.ComplaintID
+
+				for _, complaint := range []*domain.Complaint{both, cobraOnly, untagged} {
+					if filter.Matches(complaint) {
+						ids = append(ids, complaint.ID)
+					}
+				}
+
+				return ids
+			}
+
+			Expect(matching(domain.LabelFilter{})).To(HaveLen(3))
+			Expect(matching(domain.LabelFilter{
+				Tags: []string{"cobra", "viper"}, Mode: domain.MatchAny,
+			})).To(ConsistOf(both.ID, cobraOnly.ID))
+			Expect(matching(domain.LabelFilter{
+				Tags: []string{"cobra", "viper"}, Mode: domain.MatchAll,
+			})).To(ConsistOf(both.ID))
+			Expect(matching(domain.LabelFilter{
+				Tags: []string{"cobra"}, Categories: []string{"build"}, Mode: domain.MatchAny,
+			})).To(ConsistOf(cobraOnly.ID))
+		})
+
+		It("should filter ranked search hits before the limit", func(ctx SpecContext) {
+			tagged := mustFile(ctx, service.WithTags("cobra"))
+
+			for range 5 {
+				mustFile(ctx)
+			}
+
+			hits, err := complaintService.SearchComplaintsRanked(ctx, "filing", 2, false,
+				query.TagFilter{"cobra"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(hits).To(HaveLen(1))
+			Expect(hits[0].Complaint.ID).To(Equal(tagged.ID))
+		})
+	})
+})
@@ -0,0 +1,133 @@
+package bdd_test
+
+import (
+	"context"
+
+	"github.com/larsartmann/complaints-mcp/internal/domain"
+	"github.com/larsartmann/complaints-mcp/internal/repo"
+	"github.com/larsartmann/complaints-mcp/internal/service"
+	"github.com/larsartmann/complaints-mcp/internal/tracing"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Complaint Lifecycle BDD Tests", func() {
+	var (
+		tempDir          string
+		tracer           tracing.Tracer
+		repository       repo.Repository
+		complaintService *service.ComplaintService
+	)
+
+	move := func(
+		ctx context.Context,
+		id domain.ComplaintID,
+		to domain.ResolutionState,
+		reason string,
+	) *domain.Complaint {
+		complaint, err := complaintService.TransitionComplaint(ctx, id, to, "maintainer", reason, 0)
+		Expect(err).NotTo(HaveOccurred())
+
+		return complaint
+	}
+
+	BeforeEach(func() {
+		tempDir = GinkgoT().TempDir()
+		tracer = tracing.NewMockTracer("test")
+		repository = repo.NewFileRepository(tempDir, tracer)
+		complaintService = service.NewComplaintService(repository, tracer)
+	})
+
+	Context("When maintainers work through a complaint", func() {
+		It("should record every step from open to resolved", func(ctx SpecContext) {
+			complaint := fileTestComplaint(ctx, complaintService, testComplaint{})
+			Expect(complaint.ResolutionState).To(Equal(domain.ResolutionStateOpen))
+
+			move(ctx, complaint.ID, domain.ResolutionStateAcknowledged, "")
+			move(ctx, complaint.ID, domain.ResolutionStateInProgress, "picking this up")
+			resolved := move(ctx, complaint.ID, domain.ResolutionStateResolved, "docs added")
+
+			Expect(resolved.IsResolved()).To(BeTrue())
+			Expect(resolved.ResolvedBy).To(Equal("maintainer"))
+
+			stored, err := repository.FindByID(ctx, complaint.ID)
+			Expect(err).NotTo(HaveOccurred())
+			transitions := stored.Transitions()
+			Expect(transitions).To(HaveLen(3))
+
+			states := make([]domain.ResolutionState, 0, len(transitions))
+			for _, transition := range transitions {
+				Expect(transition.Actor).To(Equal("maintainer"))
+				states = append(states, transition.To)
+			}
+
+			Expect(states).To(Equal([]domain.ResolutionState{
+				domain.ResolutionStateAcknowledged,
+				domain.ResolutionStateInProgress,
+				domain.ResolutionStateResolved,
+			}))
+			Expect(transitions[1].Reason).To(Equal("picking this up"))
+		})
+
+		It("should reject moves the lifecycle does not allow", func(ctx SpecContext) {
+			complaint := fileTestComplaint(ctx, complaintService, testComplaint{})
+
+			_, err := complaintService.TransitionComplaint(ctx, complaint.ID,
+				domain.ResolutionStateReopened, "maintainer", "not closed yet", 0)
+			Expect(err).To(MatchError(domain.ErrInvalidTransition))
+
+			stored, err := repository.FindByID(ctx, complaint.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stored.ResolutionState).To(Equal(domain.ResolutionStateOpen))
+			Expect(stored.Version).To(Equal(uint64(1)), "rejected moves are not written")
+		})
+	})
+
+	Context("When a closed complaint comes back", func() {
+		It("should clear the resolution and need attention again", func(ctx SpecContext) {
+			complaint := fileTestComplaint(ctx, complaintService, testComplaint{})
+
+			move(ctx, complaint.ID, domain.ResolutionStateWontFix, "working as intended")
+
+			unresolved, err := repository.FindUnresolved(ctx, 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(unresolved).To(BeEmpty(), "wont_fix complaints are closed")
+
+			move(ctx, complaint.ID, domain.ResolutionStateReopened, "still confusing")
+
+			unresolved, err = repository.FindUnresolved(ctx, 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(unresolved).To(HaveLen(1))
+		})
+	})
+
+	Context("When complaints live in SQLite", func() {
+		BeforeEach(func() {
+			sqliteRepo, err := repo.NewSQLiteRepository(tempDir, tracer)
+			Expect(err).NotTo(HaveOccurred())
+			DeferCleanup(sqliteRepo.Close)
+
+			repository = sqliteRepo
+			complaintService = service.NewComplaintService(repository, tracer)
+		})
+
+		It("should treat every closed state as resolved for unresolved queries", func(ctx SpecContext) {
+			open := fileTestComplaint(ctx, complaintService, testComplaint{})
+			acknowledged := fileTestComplaint(ctx, complaintService, testComplaint{})
+			duplicate := fileTestComplaint(ctx, complaintService, testComplaint{})
+
+			move(ctx, acknowledged.ID, domain.ResolutionStateAcknowledged, "")
+			move(ctx, duplicate.ID, domain.ResolutionStateDuplicate, "same as another complaint")
+
+			unresolved, err := repository.FindUnresolved(ctx, 10)
+			Expect(err).NotTo(HaveOccurred())
+
+			ids := make([]domain.ComplaintID, 0, len(unresolved))
+			for _, complaint := range unresolved {
+				ids = append(ids, complaint.ID)
+			}
+
+			Expect(ids).To(ConsistOf(open.ID, acknowledged.ID))
+		})
+	})
+})
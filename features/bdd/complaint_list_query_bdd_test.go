@@ -0,0 +1,168 @@
+package bdd_test
+
+import (
+	"time"
+
+	"github.com/larsartmann/complaints-mcp/internal/domain"
+	"github.com/larsartmann/complaints-mcp/internal/query"
+	"github.com/larsartmann/complaints-mcp/internal/repo"
+	"github.com/larsartmann/complaints-mcp/internal/service"
+	"github.com/larsartmann/complaints-mcp/internal/tracing"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Complaint List Query BDD Tests", func() {
+	var (
+		storageDir string
+		tracer     tracing.Tracer
+	)
+
+	BeforeEach(func() {
+		storageDir = GinkgoT().TempDir()
+		tracer = tracing.NewMockTracer("test")
+	})
+
+	DescribeTable("should filter and sort in every backend",
+		func(ctx SpecContext, newRepository func() repo.Repository) {
+			repository := newRepository()
+			complaintService := service.NewComplaintService(repository, tracer)
+
+			day := 24 * time.Hour
+
+			recentHigh := saveTestComplaint(ctx, repository, testComplaint{
+				Project: "alpha", Agent: "Claude Agent", Severity: domain.SeverityHigh, Age: day,
+			})
+			recentCritical := saveTestComplaint(ctx, repository, testComplaint{
+				Project: "alpha", Agent: "Other Agent", Session: "triage-session",
+				Severity: domain.SeverityCritical, Age: 3 * day,
+			})
+			oldCritical := saveTestComplaint(ctx, repository, testComplaint{
+				Project: "alpha", Agent: "Claude Agent", Severity: domain.SeverityCritical, Age: 10 * day,
+			})
+			recentLow := saveTestComplaint(ctx, repository, testComplaint{
+				Project: "alpha", Agent: "Claude Agent", Severity: domain.SeverityLow, Age: 2 * day,
+			})
+			saveTestComplaint(ctx, repository, testComplaint{
+				Project: "beta", Agent: "Claude Agent", Severity: domain.SeverityHigh, Age: time.Hour,
+			})
+			saveTestComplaint(ctx, repository, testComplaint{
+				Project: "alpha", Agent: "Claude Agent", Severity: domain.SeverityHigh, Age: 4 * day, Resolved: true,
+			})
+
+			recentCritical.Occurrences = []domain.Occurrence{{
+				AgentID:   domain.MustParseAgentID("Third Agent"),
+				SessionID: domain.MustParseSessionID("other-session"),
+				At:        time.Now(),
+			}}
+			Expect(repository.Update(ctx, recentCritical)).To(Succeed())
+
+			find := func(text, sortBy, direction string, limit int) []domain.ComplaintID {
+				q, err := query.Parse(text)
+				Expect(err).NotTo(HaveOccurred())
+
+				q.Sort, err = query.ParseSort(sortBy, direction)
+				Expect(err).NotTo(HaveOccurred())
+
+				complaints, err := complaintService.FindComplaints(ctx, q, limit)
+				Expect(err).NotTo(HaveOccurred())
+
+				ids := make([]domain.ComplaintID, len(complaints))
+				for i, complaint := range complaints {
+					ids[i] = complaint.ID
+				}
+
+				return ids
+			}
+
+			// The weekly triage: high and critical in alpha from the last 7 days, newest first
+			weekAgo := time.Now().Add(-7 * day).UTC().Format(time.RFC3339)
+			Expect(find("severity:high,critical project:alpha resolved:false after:"+weekAgo, "", "", 10)).
+				To(Equal([]domain.ComplaintID{recentHigh.ID, recentCritical.ID}))
+
+			open := "project:alpha resolved:false"
+			Expect(find(open, "severity", "asc", 10)).
+				To(Equal([]domain.ComplaintID{recentLow.ID, recentHigh.ID, oldCritical.ID, recentCritical.ID}))
+			Expect(find(open, "severity", "desc", 10)).
+				To(Equal([]domain.ComplaintID{recentCritical.ID, oldCritical.ID, recentHigh.ID, recentLow.ID}))
+			Expect(find(open, "timestamp", "asc", 2)).
+				To(Equal([]domain.ComplaintID{oldCritical.ID, recentCritical.ID}))
+			Expect(find(open, "occurrence", "desc", 1)).
+				To(Equal([]domain.ComplaintID{recentCritical.ID}))
+			Expect(find("agent:other* session:triage-session", "", "", 10)).
+				To(Equal([]domain.ComplaintID{recentCritical.ID}))
+		},
+		Entry("file", func() repo.Repository {
+			return repo.NewFileRepository(storageDir, tracer)
+		}),
+		Entry("sqlite", func() repo.Repository {
+			repository, err := repo.NewSQLiteRepository(storageDir, tracer)
+			Expect(err).NotTo(HaveOccurred())
+			DeferCleanup(repository.Close)
+
+			return repository
+		}),
+		Entry("indexed sqlite", func() repo.Repository {
+			repository, err := repo.NewSQLiteRepository(storageDir, tracer)
+			Expect(err).NotTo(HaveOccurred())
+			DeferCleanup(repository.Close)
+
+			return repo.NewIndexedRepository(repository)
+		}),
+		Entry("dual", func() repo.Repository {
+			return repo.NewDualRepository(storageDir, tracer)
+		}),
+	)
+
+	DescribeTable("should sort every stored complaint, not just the newest",
+		func(ctx SpecContext, newRepository func() repo.Repository) {
+			repository := newRepository()
+			complaintService := service.NewComplaintService(repository, tracer)
+
+			var oldest *domain.Complaint
+
+			// More complaints than a page, the most reported one filed first
+			for i := range 1100 {
+				complaint := newTestComplaint(testComplaint{
+					Agent:   "Bulk Agent",
+					Project: "bulk",
+					Task:    "Bulk complaint",
+					Age:     24*time.Hour - time.Duration(i)*time.Second,
+				})
+
+				if i == 0 {
+					oldest = complaint
+					complaint.Occurrences = []domain.Occurrence{
+						{AgentID: complaint.AgentID, SessionID: complaint.SessionID, At: complaint.Timestamp},
+						{AgentID: complaint.AgentID, SessionID: complaint.SessionID, At: complaint.Timestamp},
+					}
+				}
+
+				Expect(repository.Save(ctx, complaint)).To(Succeed())
+			}
+
+			for _, by := range []query.SortField{query.SortByOccurrence, query.SortByPriority} {
+				found, err := complaintService.FindComplaints(ctx, query.Query{Sort: query.Sort{By: by}}, 1)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(HaveLen(1))
+				Expect(found[0].ID).To(Equal(oldest.ID), "sorted by %s", by)
+			}
+
+			found, err := complaintService.FindComplaints(ctx,
+				query.Query{Sort: query.Sort{By: query.SortByTimestamp, Ascending: true}}, 1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(HaveLen(1))
+			Expect(found[0].ID).To(Equal(oldest.ID))
+		},
+		Entry("file", func() repo.Repository {
+			return repo.NewFileRepository(storageDir, tracer)
+		}),
+		Entry("sqlite", func() repo.Repository {
+			repository, err := repo.NewSQLiteRepository(storageDir, tracer)
+			Expect(err).NotTo(HaveOccurred())
+			DeferCleanup(repository.Close)
+
+			return repository
+		}),
+	)
+})
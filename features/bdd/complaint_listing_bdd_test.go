@@ -2,7 +2,10 @@ package bdd_test
 
 import (
 	"context"
+	"encoding/json"
+	"math"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -425,4 +428,35 @@ var _ = Describe("Complaint Listing BDD Tests", func() {
 			Expect(limitedResults).To(HaveLen(3))
 		})
 	})
+
+	Context("List stores of more than a thousand complaints", func() {
+		It("should filter every stored complaint", func(ctx SpecContext) {
+			for range 1100 {
+				bulk := newTestComplaint(testComplaint{
+					Project: "bulk-project", Task: "Bulk complaint", Severity: domain.SeverityLow,
+				})
+
+				data, err := json.Marshal(bulk)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(os.WriteFile(filepath.Join(tempDir, "complaints", bulk.ID.String()+".json"), data, 0o644)).
+					To(Succeed())
+			}
+
+			unresolved, err := repository.FindUnresolved(ctx, math.MaxInt)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(unresolved).To(HaveLen(1104))
+
+			low, err := repository.FindBySeverity(ctx, domain.SeverityLow, math.MaxInt)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(len(low)).To(BeNumerically(">=", 1100))
+
+			bulk, err := repository.FindByProject(ctx, "bulk-project", math.MaxInt)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(bulk).To(HaveLen(1100))
+
+			found, err := repository.Search(ctx, "bulk complaint", math.MaxInt)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(HaveLen(1100))
+		})
+	})
 })
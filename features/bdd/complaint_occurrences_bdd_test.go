@@ -0,0 +1,107 @@
+package bdd_test
+
+import (
+	"context"
+
+	"github.com/larsartmann/complaints-mcp/internal/domain"
+	"github.com/larsartmann/complaints-mcp/internal/query"
+	"github.com/larsartmann/complaints-mcp/internal/repo"
+	"github.com/larsartmann/complaints-mcp/internal/service"
+	"github.com/larsartmann/complaints-mcp/internal/tracing"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Complaint Occurrences BDD Tests", func() {
+	var (
+		repository       *repo.FileRepository
+		complaintService *service.ComplaintService
+	)
+
+	confirm := func(ctx context.Context, id domain.ComplaintID, agent, session string) *domain.Complaint {
+		complaint, err := complaintService.ConfirmComplaint(ctx, id, agent, session, 0)
+		Expect(err).NotTo(HaveOccurred())
+
+		return complaint
+	}
+
+	BeforeEach(func() {
+		tracer := tracing.NewMockTracer("test")
+		repository = repo.NewFileRepository(GinkgoT().TempDir(), tracer)
+		complaintService = service.NewComplaintService(repository, tracer)
+	})
+
+	Context("When agents confirm a complaint", func() {
+		It("should store occurrences and record them in the history", func(ctx SpecContext) {
+			complaint := fileTestComplaint(ctx, complaintService, testComplaint{Task: "README lacks install steps"})
+
+			confirm(ctx, complaint.ID, "Second Agent", "session-2")
+			confirmed := confirm(ctx, complaint.ID, "Third Agent", "session-3")
+			Expect(confirmed.OccurrenceCount()).To(Equal(3))
+
+			stored, err := repository.FindByID(ctx, complaint.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stored.Occurrences).To(HaveLen(2))
+			Expect(stored.Version).To(Equal(uint64(3)))
+
+			events, err := complaintService.GetComplaintHistory(ctx, complaint.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(events[len(events)-1].Action).To(Equal(domain.EventConfirmed))
+			Expect(events[len(events)-1].Actor).To(Equal("Third Agent"))
+		})
+
+		It("should count confirmations of a duplicate towards the original", func(ctx SpecContext) {
+			original := fileTestComplaint(ctx, complaintService, testComplaint{Task: "README lacks install steps"})
+			duplicate := fileTestComplaint(ctx, complaintService, testComplaint{Task: "README has no install steps"})
+
+			_, err := complaintService.MarkDuplicate(ctx, duplicate.ID, original.ID, "triager", "", 0)
+			Expect(err).NotTo(HaveOccurred())
+
+			confirmed := confirm(ctx, duplicate.ID, "Second Agent", "session-2")
+			Expect(confirmed.ID).To(Equal(original.ID))
+			Expect(confirmed.OccurrenceCount()).To(Equal(3))
+		})
+
+		It("should ask for closed complaints to be reopened", func(ctx SpecContext) {
+			complaint := fileTestComplaint(ctx, complaintService, testComplaint{Task: "README lacks install steps"})
+			_, err := complaintService.ResolveComplaint(ctx, complaint.ID, "maintainer")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = complaintService.ConfirmComplaint(ctx, complaint.ID, "Second Agent", "session-2", 0)
+			Expect(err).To(MatchError(ContainSubstring("reopen it")))
+		})
+	})
+
+	Context("When ranking complaints", func() {
+		It("should sort by occurrences and by effective priority", func(ctx SpecContext) {
+			critical := fileTestComplaint(ctx, complaintService, testComplaint{
+				Task: "Production deploy fails", Severity: domain.SeverityCritical,
+			})
+			frequent := fileTestComplaint(ctx, complaintService, testComplaint{Task: "README lacks install steps"})
+			rare := fileTestComplaint(ctx, complaintService, testComplaint{
+				Task: "Typo in a comment", Severity: domain.SeverityLow,
+			})
+
+			for _, session := range []string{"session-2", "session-3", "session-4"} {
+				confirm(ctx, frequent.ID, "Other Agent", session)
+			}
+
+			confirm(ctx, rare.ID, "Other Agent", "session-2")
+
+			ids := func(by query.SortField) []domain.ComplaintID {
+				complaints, err := complaintService.FindComplaints(ctx, query.Query{Sort: query.Sort{By: by}}, 10)
+				Expect(err).NotTo(HaveOccurred())
+
+				result := make([]domain.ComplaintID, 0, len(complaints))
+				for _, complaint := range complaints {
+					result = append(result, complaint.ID)
+				}
+
+				return result
+			}
+
+			Expect(ids(query.SortByOccurrence)).To(Equal([]domain.ComplaintID{frequent.ID, rare.ID, critical.ID}))
+			Expect(ids(query.SortByPriority)).To(Equal([]domain.ComplaintID{frequent.ID, critical.ID, rare.ID}))
+		})
+	})
+})
@@ -0,0 +1,150 @@
+package bdd_test
+
+import (
+	"time"
+
+	"github.com/larsartmann/complaints-mcp/internal/domain"
+	"github.com/larsartmann/complaints-mcp/internal/query"
+	"github.com/larsartmann/complaints-mcp/internal/repo"
+	"github.com/larsartmann/complaints-mcp/internal/service"
+	"github.com/larsartmann/complaints-mcp/internal/tracing"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Complaint Query Language BDD Tests", func() {
+	var (
+		storageDir string
+		tracer     tracing.Tracer
+	)
+
+	BeforeEach(func() {
+		storageDir = GinkgoT().TempDir()
+		tracer = tracing.NewMockTracer("test")
+	})
+
+	DescribeTable("should evaluate field filters, phrases and exclusions in every backend",
+		func(ctx SpecContext, newRepository func() repo.Repository) {
+			complaintService := service.NewComplaintService(newRepository(), tracer)
+
+			target := fileTestComplaint(ctx, complaintService, testComplaint{
+				Agent: "Claude Agent", Project: "alpha", Task: "Exact phrase about cobra flags", Severity: domain.SeverityHigh,
+			})
+			otherAgent := fileTestComplaint(ctx, complaintService, testComplaint{
+				Agent: "Other Agent", Project: "alpha", Task: "Cobra flags, exact phrase", Severity: domain.SeverityHigh,
+			})
+			lowSeverity := fileTestComplaint(ctx, complaintService, testComplaint{
+				Agent: "claude-helper", Project: "alpha", Task: "Exact phrase about cobra", Severity: domain.SeverityLow,
+			})
+			otherProject := fileTestComplaint(ctx, complaintService, testComplaint{
+				Agent: "Claude Agent", Project: "beta", Task: "Exact phrase about cobra flags", Severity: domain.SeverityHigh,
+			})
+			resolved := fileTestComplaint(ctx, complaintService, testComplaint{
+				Agent: "Claude Agent", Project: "alpha", Task: "Exact phrase about cobra flags", Severity: domain.SeverityCritical,
+			})
+
+			_, err := complaintService.ResolveComplaint(ctx, resolved.ID, "maintainer")
+			Expect(err).NotTo(HaveOccurred())
+
+			yesterday := time.Now().Add(-24 * time.Hour).UTC().Format(time.DateOnly)
+
+			search := func(text string) []domain.ComplaintID {
+				hits, err := complaintService.SearchComplaintsRanked(ctx, text, 10, false)
+				Expect(err).NotTo(HaveOccurred())
+
+				ids := make([]domain.ComplaintID, len(hits))
+				for i, hit := range hits {
+					ids[i] = hit.Complaint.ID
+				}
+
+				return ids
+			}
+
+			Expect(search("severity:high,critical project:alpha agent:claude* resolved:false after:" +
+				yesterday + ` "exact phrase" -windows`)).To(Equal([]domain.ComplaintID{target.ID}))
+
+			Expect(search("agent:claude* project:alpha")).To(ConsistOf(target.ID, lowSeverity.ID, resolved.ID))
+			Expect(search("project:beta")).To(Equal([]domain.ComplaintID{otherProject.ID}))
+			Expect(search("-severity:low resolved:false project:alpha")).To(ConsistOf(target.ID, otherAgent.ID))
+			Expect(search("resolved:true")).To(Equal([]domain.ComplaintID{resolved.ID}))
+			Expect(search("before:" + yesterday)).To(BeEmpty())
+			Expect(search(`"exact phrase" -flags`)).To(Equal([]domain.ComplaintID{lowSeverity.ID}))
+		},
+		Entry("file", func() repo.Repository {
+			return repo.NewFileRepository(storageDir, tracer)
+		}),
+		Entry("indexed file", func() repo.Repository {
+			return repo.NewIndexedRepository(repo.NewFileRepository(storageDir, tracer))
+		}),
+		Entry("sqlite", func() repo.Repository {
+			repository, err := repo.NewSQLiteRepository(storageDir, tracer)
+			Expect(err).NotTo(HaveOccurred())
+			DeferCleanup(repository.Close)
+
+			return repository
+		}),
+		Entry("indexed sqlite", func() repo.Repository {
+			repository, err := repo.NewSQLiteRepository(storageDir, tracer)
+			Expect(err).NotTo(HaveOccurred())
+			DeferCleanup(repository.Close)
+
+			return repo.NewIndexedRepository(repository)
+		}),
+		Entry("dual", func() repo.Repository {
+			return repo.NewDualRepository(storageDir, tracer)
+		}),
+	)
+
+	DescribeTable("should evaluate filters over every stored complaint",
+		func(ctx SpecContext, newRepository func() repo.Repository) {
+			repository := newRepository()
+			complaintService := service.NewComplaintService(repository, tracer)
+
+			// More complaints than a page or the old candidate cap, the needle oldest
+			for i := range 1100 {
+				bulk := testComplaint{
+					Agent:    "Bulk Agent",
+					Project:  "bulk",
+					Task:     "Bulk complaint",
+					Severity: domain.SeverityLow,
+					Age:      24*time.Hour - time.Duration(i)*time.Second,
+				}
+
+				if i == 0 {
+					bulk.Options = []service.ComplaintOption{service.WithTags("needle")}
+				}
+
+				saveTestComplaint(ctx, repository, bulk)
+			}
+
+			q, err := query.Parse("tag:needle")
+			Expect(err).NotTo(HaveOccurred())
+
+			found, err := complaintService.FindComplaints(ctx, q, 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(HaveLen(1))
+
+			hits, err := complaintService.SearchComplaintsRanked(ctx, "tag:needle bulk", 10, false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(hits).To(HaveLen(1))
+
+			q.Filters = nil
+			q.Sort = query.Sort{By: query.SortByTimestamp, Ascending: true}
+
+			oldest, err := complaintService.FindComplaints(ctx, q, 1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(oldest).To(HaveLen(1))
+			Expect(oldest[0].Tags).To(Equal([]string{"needle"}))
+		},
+		Entry("file", func() repo.Repository {
+			return repo.NewFileRepository(storageDir, tracer)
+		}),
+		Entry("sqlite", func() repo.Repository {
+			repository, err := repo.NewSQLiteRepository(storageDir, tracer)
+			Expect(err).NotTo(HaveOccurred())
+			DeferCleanup(repository.Close)
+
+			return repository
+		}),
+	)
+})
@@ -0,0 +1,148 @@
+package bdd_test
+
+import (
+	"github.com/larsartmann/complaints-mcp/internal/domain"
+	"github.com/larsartmann/complaints-mcp/internal/repo"
+	"github.com/larsartmann/complaints-mcp/internal/service"
+	"github.com/larsartmann/complaints-mcp/internal/tracing"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Ranked Complaint Search BDD Tests", func() {
+	var (
+		storageDir string
+		tracer     tracing.Tracer
+	)
+
+	hitIDs := func(hits []repo.SearchHit) []domain.ComplaintID {
+		ids := make([]domain.ComplaintID, len(hits))
+		for i, hit := range hits {
+			ids[i] = hit.Complaint.ID
+		}
+
+		return ids
+	}
+
+	BeforeEach(func() {
+		storageDir = GinkgoT().TempDir()
+		tracer = tracing.NewMockTracer("test")
+	})
+
+	DescribeTable("should rank the best match first",
+		func(ctx SpecContext, newRepository func() repo.Repository) {
+			complaintService := service.NewComplaintService(newRepository(), tracer)
+
+			envOnly := fileTestComplaint(ctx, complaintService, testComplaint{
+				Task: "Deploying the service", Missing: "Which environment to deploy to",
+			})
+			both := fileTestComplaint(ctx, complaintService, testComplaint{
+				Task: "Configuring the server", Missing: "Environment variables are not documented",
+			})
+			fileTestComplaint(ctx, complaintService, testComplaint{
+				Task: "Writing tests", Missing: "No example for table tests",
+			})
+
+			hits, err := complaintService.SearchComplaintsRanked(ctx, "env var", 10, false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(hitIDs(hits)).To(Equal([]domain.ComplaintID{both.ID, envOnly.ID}))
+			Expect(hits[0].Score).To(BeNumerically(">", hits[1].Score))
+			Expect(hits[0].Field).To(Equal("missing_info"))
+			Expect(hits[0].Snippet).To(Equal("**Environment** **variables** are not documented"))
+		},
+		Entry("with an index", func() repo.Repository {
+			return repo.NewIndexedRepository(repo.NewFileRepository(storageDir, tracer))
+		}),
+		Entry("without an index", func() repo.Repository {
+			return repo.NewFileRepository(storageDir, tracer)
+		}),
+	)
+
+	It("should keep the index up to date on writes", func(ctx SpecContext) {
+		repository := repo.NewIndexedRepository(repo.NewFileRepository(storageDir, tracer))
+		complaintService := service.NewComplaintService(repository, tracer)
+
+		complaint := fileTestComplaint(ctx, complaintService, testComplaint{
+			Task: "Reading the docs", Missing: "Nothing about plugins",
+		})
+
+		// Build the index before the complaint changes
+		Expect(complaintService.SearchComplaints(ctx, "plugin", 10)).To(HaveLen(1))
+
+		_, err := complaintService.TagComplaint(ctx, complaint.ID, "triager", []string{"cobra"}, nil, 0)
+		Expect(err).NotTo(HaveOccurred())
+
+		found, err := complaintService.SearchComplaints(ctx, "cobra", 10)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found).To(HaveLen(1))
+		Expect(found[0].Tags).To(Equal([]string{"cobra"}))
+
+		Expect(repository.Delete(ctx, complaint.ID)).To(Succeed())
+		Expect(complaintService.SearchComplaints(ctx, "plugin", 10)).To(BeEmpty())
+	})
+
+	DescribeTable("should pick up writes made by other processes sharing the store",
+		func(ctx SpecContext, newRepository func() repo.Repository) {
+			// Two repositories on one directory stand in for two server processes
+			first := service.NewComplaintService(repo.NewIndexedRepository(newRepository()), tracer)
+			second := service.NewComplaintService(repo.NewIndexedRepository(newRepository()), tracer)
+
+			fileTestComplaint(ctx, first, testComplaint{Task: "Reading the docs", Missing: "Nothing about plugins"})
+			Expect(first.SearchComplaints(ctx, "plugin", 10)).To(HaveLen(1))
+
+			other := fileTestComplaint(ctx, second, testComplaint{Task: "Writing a plugin", Missing: "No plugin examples"})
+			Expect(first.SearchComplaints(ctx, "plugin", 10)).To(HaveLen(2))
+
+			_, err := second.TagComplaint(ctx, other.ID, "triager", []string{"extensions"}, nil, 0)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(first.SearchComplaints(ctx, "extensions", 10)).To(HaveLen(1))
+
+			Expect(second.Repository().Delete(ctx, other.ID)).To(Succeed())
+			Expect(first.SearchComplaints(ctx, "extensions", 10)).To(BeEmpty())
+		},
+		Entry("file", func() repo.Repository {
+			return repo.NewFileRepository(storageDir, tracer)
+		}),
+		Entry("sqlite", func() repo.Repository {
+			repository, err := repo.NewSQLiteRepository(storageDir, tracer)
+			Expect(err).NotTo(HaveOccurred())
+			DeferCleanup(repository.Close)
+
+			return repository
+		}),
+		Entry("dual", func() repo.Repository {
+			return repo.NewDualRepository(storageDir, tracer)
+		}),
+	)
+
+	DescribeTable("should match typos only in fuzzy searches",
+		func(ctx SpecContext, newRepository func() repo.Repository) {
+			complaintService := service.NewComplaintService(newRepository(), tracer)
+
+			exact := fileTestComplaint(ctx, complaintService, testComplaint{Task: "Cobra flags are confusing"})
+			typo := fileTestComplaint(ctx, complaintService, testComplaint{Task: "Cobar flags are confusing"})
+
+			hits, err := complaintService.SearchComplaintsRanked(ctx, "cobar", 10, false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(hitIDs(hits)).To(Equal([]domain.ComplaintID{typo.ID}))
+
+			hits, err = complaintService.SearchComplaintsRanked(ctx, "cobar", 10, true)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(hitIDs(hits)).To(Equal([]domain.ComplaintID{typo.ID, exact.ID}))
+			Expect(hits[1].Score).To(BeNumerically("<", hits[0].Score))
+			Expect(hits[1].Snippet).To(Equal("**Cobra** flags are confusing"))
+
+			complaintService.SetFuzziness(0)
+
+			hits, err = complaintService.SearchComplaintsRanked(ctx, "cobar", 10, true)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(hitIDs(hits)).To(Equal([]domain.ComplaintID{typo.ID}))
+		},
+		Entry("with an index", func() repo.Repository {
+			return repo.NewIndexedRepository(repo.NewFileRepository(storageDir, tracer))
+		}),
+		Entry("without an index", func() repo.Repository {
+			return repo.NewFileRepository(storageDir, tracer)
+		}),
+	)
+})
@@ -0,0 +1,88 @@
+package bdd_test
+
+import (
+	"path/filepath"
+
+	"github.com/larsartmann/complaints-mcp/internal/domain"
+	"github.com/larsartmann/complaints-mcp/internal/embed"
+	"github.com/larsartmann/complaints-mcp/internal/repo"
+	"github.com/larsartmann/complaints-mcp/internal/service"
+	"github.com/larsartmann/complaints-mcp/internal/tracing"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Similar Complaints BDD Tests", func() {
+	var (
+		storageDir       string
+		complaintService *service.ComplaintService
+		noComplaint      domain.ComplaintID // compares free text instead
+	)
+
+	BeforeEach(func() {
+		storageDir = GinkgoT().TempDir()
+		tracer := tracing.NewMockTracer("test")
+		complaintService = service.NewComplaintService(repo.NewFileRepository(storageDir, tracer), tracer)
+	})
+
+	similarIDs := func(similar []service.SimilarComplaint) []domain.ComplaintID {
+		ids := make([]domain.ComplaintID, len(similar))
+		for i, found := range similar {
+			ids[i] = found.Complaint.ID
+		}
+
+		return ids
+	}
+
+	It("should find complaints about the same gap in other words", func(ctx SpecContext) {
+		envVars := fileTestComplaint(ctx, complaintService, testComplaint{
+			Task: "Configuring the server", Missing: "Environment variables are not documented",
+		})
+		envDocs := fileTestComplaint(ctx, complaintService, testComplaint{
+			Task: "Setting up configuration", Missing: "No docs for the env vars the server reads",
+		})
+		tests := fileTestComplaint(ctx, complaintService, testComplaint{
+			Task: "Writing table-driven tests", Missing: "No example of a test table",
+		})
+
+		similar, err := complaintService.FindSimilarComplaints(ctx, envVars.ID, "", 10)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(similarIDs(similar)).NotTo(ContainElement(envVars.ID))
+		Expect(similarIDs(similar)[0]).To(Equal(envDocs.ID))
+		Expect(similar[0].Score).To(BeNumerically("<=", 1))
+
+		similar, err = complaintService.FindSimilarComplaints(ctx, noComplaint, "example tests", 1)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(similarIDs(similar)).To(Equal([]domain.ComplaintID{tests.ID}))
+	})
+
+	It("should persist vectors next to the complaints", func(ctx SpecContext) {
+		path := filepath.Join(storageDir, embed.FileName)
+		complaintService.SetEmbeddings(embed.NewStore(embed.NewHashEmbedder(embed.DefaultDimensions), path))
+
+		first := fileTestComplaint(ctx, complaintService, testComplaint{
+			Task: "Reading the docs", Missing: "Nothing about plugins",
+		})
+		second := fileTestComplaint(ctx, complaintService, testComplaint{
+			Task: "Writing a plugin", Missing: "The plugin API is undocumented",
+		})
+
+		similar, err := complaintService.FindSimilarComplaints(ctx, first.ID, "", 10)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(similarIDs(similar)).To(Equal([]domain.ComplaintID{second.ID}))
+
+		Expect(path).To(BeAnExistingFile(), "vectors are flushed after a search")
+	})
+
+	It("should require either a complaint or text", func(ctx SpecContext) {
+		complaint := fileTestComplaint(ctx, complaintService, testComplaint{
+			Task: "Reading the docs", Missing: "Nothing about plugins",
+		})
+
+		_, err := complaintService.FindSimilarComplaints(ctx, noComplaint, "  ", 10)
+		Expect(err).To(MatchError("either a complaint ID or text is required"))
+
+		_, err = complaintService.FindSimilarComplaints(ctx, complaint.ID, "plugins", 10)
+		Expect(err).To(MatchError("give either a complaint ID or text, not both"))
+	})
+})
@@ -0,0 +1,235 @@
+package bdd_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/larsartmann/complaints-mcp/internal/docs"
+	"github.com/larsartmann/complaints-mcp/internal/domain"
+	"github.com/larsartmann/complaints-mcp/internal/projectdetect"
+	"github.com/larsartmann/complaints-mcp/internal/repo"
+	"github.com/larsartmann/complaints-mcp/internal/service"
+	"github.com/larsartmann/complaints-mcp/internal/tracing"
+	"github.com/larsartmann/complaints-mcp/internal/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// fixedProjectDetector reports every working directory as the root of one project.
+type fixedProjectDetector struct {
+	root string
+}
+
+func (d fixedProjectDetector) Detect(
+	ctx context.Context,
+	workingDir string,
+) (*projectdetect.ProjectInfo, error) {
+	return &projectdetect.ProjectInfo{Name: "docs-project", RootPath: d.root}, nil
+}
+
+var _ = Describe("Complaint Docs Export BDD Tests", func() {
+	var (
+		storageDir       string
+		projectDir       string
+		complaintService *service.ComplaintService
+	)
+
+	newService := func(format types.DocsFormat) {
+		tracer := tracing.NewMockTracer("test")
+		repository := repo.NewFileRepository(storageDir, tracer)
+		complaintService = service.NewComplaintServiceWithDetector(
+			repository, tracer, fixedProjectDetector{root: projectDir},
+		)
+
+		exporter, err := docs.NewExporter(types.DocsConfig{
+			Dir:     "docs/complaints",
+			Format:  format,
+			Enabled: true,
+		}, storageDir)
+		Expect(err).NotTo(HaveOccurred())
+
+		complaintService.SetDocsExporter(exporter)
+	}
+
+	BeforeEach(func() {
+		storageDir = GinkgoT().TempDir()
+		projectDir = GinkgoT().TempDir()
+	})
+
+	Context("Filing a complaint", func() {
+		BeforeEach(func() {
+			newService(types.DocsFormatMarkdown)
+		})
+
+		It("should write a Markdown document under the project's docs dir", func(ctx SpecContext) {
+			complaint := fileTestComplaint(ctx, complaintService, testComplaint{
+				Agent: "Docs Agent", ProjectRoot: projectDir, Context: "Some context",
+			})
+
+			Expect(complaint.DocsPath).To(HavePrefix(filepath.Join(projectDir, "docs/complaints")))
+			Expect(complaint.DocsPath).To(HaveSuffix(".md"))
+
+			content, err := os.ReadFile(complaint.DocsPath)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(ContainSubstring("# Docs Agent Complaint"))
+			Expect(string(content)).To(ContainSubstring("Some context"))
+			Expect(string(content)).NotTo(ContainSubstring("Missing Information"))
+
+			_, docsPath, err := complaintService.GetFilePaths(ctx, complaint.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(docsPath).To(Equal(complaint.DocsPath))
+		})
+
+		It("should fall back to the storage dir without a working dir", func(ctx SpecContext) {
+			complaint := fileTestComplaint(ctx, complaintService, testComplaint{})
+
+			Expect(complaint.DocsPath).To(HavePrefix(filepath.Join(storageDir, "docs/complaints")))
+			Expect(complaint.DocsPath).To(BeAnExistingFile())
+		})
+
+		It("should not overwrite a document filed in the same second", func(ctx SpecContext) {
+			first := fileTestComplaint(ctx, complaintService, testComplaint{ProjectRoot: projectDir})
+			second := fileTestComplaint(ctx, complaintService, testComplaint{ProjectRoot: projectDir})
+
+			Expect(second.DocsPath).NotTo(Equal(first.DocsPath))
+			Expect(first.DocsPath).To(BeAnExistingFile())
+			Expect(second.DocsPath).To(BeAnExistingFile())
+		})
+	})
+
+	Context("Resolving a complaint", func() {
+		BeforeEach(func() {
+			newService(types.DocsFormatText)
+		})
+
+		It("should re-render the same document with the resolution", func(ctx SpecContext) {
+			complaint := fileTestComplaint(ctx, complaintService, testComplaint{ProjectRoot: projectDir})
+
+			resolved, err := complaintService.ResolveComplaint(ctx, complaint.ID, "maintainer")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resolved.DocsPath).To(Equal(complaint.DocsPath))
+
+			content, err := os.ReadFile(resolved.DocsPath)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(ContainSubstring("Resolved by maintainer"))
+		})
+	})
+
+	Context("Rejected writes", func() {
+		It("should not leave a document for a complaint that was never saved", func(ctx SpecContext) {
+			tracer := tracing.NewMockTracer("test")
+			quotaRepo, err := repo.NewQuotaRepository(repo.NewFileRepository(storageDir, tracer), 1, false)
+			Expect(err).NotTo(HaveOccurred())
+
+			complaintService = service.NewComplaintServiceWithDetector(
+				quotaRepo, tracer, fixedProjectDetector{root: projectDir},
+			)
+
+			exporter, err := docs.NewExporter(types.DocsConfig{
+				Dir:     "docs/complaints",
+				Format:  types.DocsFormatMarkdown,
+				Enabled: true,
+			}, storageDir)
+			Expect(err).NotTo(HaveOccurred())
+			complaintService.SetDocsExporter(exporter)
+
+			_, err = complaintService.CreateComplaint(ctx,
+				"Docs Agent", "docs-session", "Over quota",
+				"", "", "", "", domain.SeverityLow, "", projectDir)
+			Expect(err).To(HaveOccurred())
+
+			Expect(filepath.Join(projectDir, "docs/complaints")).NotTo(BeADirectory())
+		})
+
+		It("should keep the document of a complaint whose update was rejected", func(ctx SpecContext) {
+			newService(types.DocsFormatText)
+
+			complaint := fileTestComplaint(ctx, complaintService, testComplaint{ProjectRoot: projectDir})
+
+			_, err := complaintService.ResolveComplaintAtVersion(ctx, complaint.ID, "maintainer", complaint.Version+1)
+			Expect(err).To(HaveOccurred())
+
+			content, err := os.ReadFile(complaint.DocsPath)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).NotTo(ContainSubstring("Resolved by"))
+		})
+	})
+
+	Context("Custom templates", func() {
+		writeProjectTemplate := func(name, source string) {
+			dir := filepath.Join(projectDir, ".complaints-mcp", "templates")
+			Expect(os.MkdirAll(dir, 0o755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(dir, name), []byte(source), 0o644)).To(Succeed())
+		}
+
+		BeforeEach(func() {
+			newService(types.DocsFormatMarkdown)
+		})
+
+		It("should prefer the project's template over the built-in one", func(ctx SpecContext) {
+			writeProjectTemplate("complaint.md.tmpl",
+				"RFC {{.ID}} [{{.Severity}}] {{.TaskDescription}} status={{.Status}}"+
+					"{{if .Resolved}} by={{.ResolvedBy}} at={{formatTime .ResolvedAt}}{{end}}\n")
+
+			complaint := fileTestComplaint(ctx, complaintService, testComplaint{
+				ProjectRoot: projectDir, Task: "Custom layout", Severity: domain.SeverityHigh,
+			})
+
+			content, err := os.ReadFile(complaint.DocsPath)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(Equal(
+				"RFC " + complaint.ID.String() + " [high] Custom layout status=open\n"))
+
+			_, err = complaintService.ResolveComplaint(ctx, complaint.ID, "maintainer")
+			Expect(err).NotTo(HaveOccurred())
+
+			content, err = os.ReadFile(complaint.DocsPath)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(ContainSubstring("status=resolved by=maintainer at=2"))
+		})
+
+		It("should only use templates matching the configured format", func(ctx SpecContext) {
+			writeProjectTemplate("complaint.html.tmpl", "HTML {{.ID}}")
+
+			complaint := fileTestComplaint(ctx, complaintService, testComplaint{
+				Agent: "Docs Agent", ProjectRoot: projectDir,
+			})
+
+			content, err := os.ReadFile(complaint.DocsPath)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(ContainSubstring("# Docs Agent Complaint"))
+		})
+
+		It("should still file the complaint when the template is broken", func(ctx SpecContext) {
+			writeProjectTemplate("complaint.md.tmpl", "{{.Broken")
+
+			complaint := fileTestComplaint(ctx, complaintService, testComplaint{
+				ProjectRoot: projectDir, Task: "Broken template",
+			})
+			Expect(complaint.DocsPath).To(BeEmpty())
+
+			found, err := complaintService.GetComplaint(ctx, complaint.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found.TaskDescription).To(Equal("Broken template"))
+		})
+	})
+
+	Context("HTML format", func() {
+		BeforeEach(func() {
+			newService(types.DocsFormatHTML)
+		})
+
+		It("should escape complaint content", func(ctx SpecContext) {
+			complaint := fileTestComplaint(ctx, complaintService, testComplaint{
+				ProjectRoot: projectDir, Task: "<script>alert(1)</script>",
+			})
+			Expect(complaint.DocsPath).To(HaveSuffix(".html"))
+
+			content, err := os.ReadFile(complaint.DocsPath)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).NotTo(ContainSubstring("<script>"))
+			Expect(string(content)).To(ContainSubstring("&lt;script&gt;"))
+		})
+	})
+})
@@ -0,0 +1,195 @@
+package bdd_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	v2 "charm.land/log/v2"
+	"github.com/larsartmann/complaints-mcp/internal/domain"
+	"github.com/larsartmann/complaints-mcp/internal/repo"
+	"github.com/larsartmann/complaints-mcp/internal/service"
+	"github.com/larsartmann/complaints-mcp/internal/tracing"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Dual Storage BDD Tests", func() {
+	var (
+		globalDir        string
+		projectDir       string
+		repository       *repo.DualRepository
+		complaintService *service.ComplaintService
+	)
+
+	BeforeEach(func() {
+		globalDir = GinkgoT().TempDir()
+		projectDir = GinkgoT().TempDir()
+
+		tracer := tracing.NewMockTracer("test")
+		repository = repo.NewDualRepository(globalDir, tracer)
+		complaintService = service.NewComplaintServiceWithDetector(
+			repository, tracer, fixedProjectDetector{root: projectDir},
+		)
+	})
+
+	It("should write the complaint to the global and project-local stores", func(ctx SpecContext) {
+		complaint := fileTestComplaint(ctx, complaintService, testComplaint{
+			ProjectRoot: projectDir, Task: "Stored twice",
+		})
+		fileName := complaint.ID.String() + ".json"
+
+		Expect(filepath.Join(globalDir, "projects", "bdd-project", "complaints", fileName)).
+			To(BeAnExistingFile())
+		Expect(filepath.Join(projectDir, ".complaints-mcp", "complaints", fileName)).
+			To(BeAnExistingFile())
+
+		filePath, _, err := complaintService.GetFilePaths(ctx, complaint.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(filePath).To(HavePrefix(globalDir))
+	})
+
+	It("should merge and deduplicate reads across stores", func(ctx SpecContext) {
+		first := fileTestComplaint(ctx, complaintService, testComplaint{ProjectRoot: projectDir, Task: "Filed here"})
+
+		// A teammate's complaint committed to the repository, unknown globally
+		teammateID, err := domain.NewComplaintID()
+		Expect(err).NotTo(HaveOccurred())
+
+		teammate := *first
+		teammate.ID = teammateID
+		teammate.TaskDescription = "Filed by a teammate"
+		teammate.Timestamp = first.Timestamp.Add(time.Second)
+
+		data, err := json.Marshal(&teammate)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.WriteFile(
+			filepath.Join(projectDir, ".complaints-mcp", "complaints", teammateID.String()+".json"),
+			data, 0o644,
+		)).To(Succeed())
+
+		all, err := complaintService.ListComplaints(ctx, 10, 0)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(all).To(HaveLen(2))
+		Expect(all[0].ID).To(Equal(first.ID))
+		Expect(all[1].ID).To(Equal(teammateID))
+
+		found, err := complaintService.GetComplaint(ctx, teammateID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found.TaskDescription).To(Equal("Filed by a teammate"))
+
+		results, err := complaintService.SearchComplaints(ctx, "teammate", 10)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(results).To(HaveLen(1))
+	})
+
+	It("should read the project-local stores of projects added on this machine", func(ctx SpecContext) {
+		known := fileTestComplaint(ctx, complaintService, testComplaint{ProjectRoot: projectDir, Task: "Known globally"})
+		localOnly := fileTestComplaint(ctx, complaintService, testComplaint{
+			ProjectRoot: projectDir, Task: "Only kept in the project",
+		})
+
+		Expect(os.Remove(filepath.Join(
+			globalDir, "projects", "bdd-project", "complaints", localOnly.ID.String()+".json",
+		))).To(Succeed())
+
+		// A fresh repository only knows the global dir, whatever roots complaints record
+		fresh := repo.NewDualRepository(globalDir, tracing.NewMockTracer("test"))
+
+		all, err := fresh.FindAll(ctx, 10, 0)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(all).To(HaveLen(1))
+		Expect(all[0].ID).To(Equal(known.ID))
+
+		Expect(fresh.AddProject("bdd-project", projectDir)).To(BeTrue())
+
+		all, err = fresh.FindAll(ctx, 10, 0)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(all).To(HaveLen(2))
+		Expect(all[1].ID).To(Equal(localOnly.ID))
+
+		found, err := fresh.FindByID(ctx, localOnly.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found.TaskDescription).To(Equal("Only kept in the project"))
+	})
+
+	It("should not write to project roots recorded on other machines", func(ctx SpecContext) {
+		elsewhere := filepath.Join(GinkgoT().TempDir(), "home", "teammate", "project")
+
+		complaint := fileTestComplaint(ctx, complaintService, testComplaint{
+			ProjectRoot: projectDir, Task: "Filed by a teammate",
+		})
+		complaint.ProjectRoot = elsewhere
+
+		fresh := repo.NewDualRepository(globalDir, tracing.NewMockTracer("test"))
+		Expect(fresh.AddProject("bdd-project", elsewhere)).To(BeFalse(), "the root does not exist here")
+		Expect(fresh.Update(ctx, complaint)).To(Succeed())
+
+		Expect(elsewhere).NotTo(BeADirectory())
+	})
+
+	It("should read stores holding more complaints than one page", func(ctx SpecContext) {
+		template := fileTestComplaint(ctx, complaintService, testComplaint{ProjectRoot: projectDir, Task: "Bulk"})
+		localDir := filepath.Join(projectDir, ".complaints-mcp", "complaints")
+
+		for range 1100 {
+			id, err := domain.NewComplaintID()
+			Expect(err).NotTo(HaveOccurred())
+
+			bulk := *template
+			bulk.ID = id
+
+			data, err := json.Marshal(&bulk)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(os.WriteFile(filepath.Join(localDir, id.String()+".json"), data, 0o644)).To(Succeed())
+		}
+
+		all, err := repository.FindAll(ctx, 2000, 0)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(all).To(HaveLen(1101))
+	})
+
+	It("should count writes in the generation shared by the global store", func(ctx SpecContext) {
+		var logs bytes.Buffer
+		logCtx := v2.WithContext(ctx, v2.New(&logs))
+
+		start, _, err := repository.Changes(logCtx, 0)
+		Expect(err).NotTo(HaveOccurred())
+
+		complaint := saveTestComplaint(logCtx, repository, testComplaint{Task: "Counted once"})
+		Expect(repository.Delete(logCtx, complaint.ID)).To(Succeed())
+
+		current, foreign, err := repository.Changes(logCtx, start)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(current).To(Equal(start + 2))
+		Expect(foreign).To(BeFalse())
+		Expect(logs.String()).NotTo(ContainSubstring("Failed to record store write"))
+
+		other := repo.NewDualRepository(globalDir, tracing.NewMockTracer("test"))
+		saveTestComplaint(logCtx, other, testComplaint{Task: "Written elsewhere"})
+
+		_, foreign, err = repository.Changes(logCtx, current)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(foreign).To(BeTrue())
+	})
+
+	It("should update both copies on resolve", func(ctx SpecContext) {
+		complaint := fileTestComplaint(ctx, complaintService, testComplaint{
+			ProjectRoot: projectDir, Task: "Resolve everywhere",
+		})
+
+		_, err := complaintService.ResolveComplaint(ctx, complaint.ID, "lead")
+		Expect(err).NotTo(HaveOccurred())
+
+		local := repo.NewFileRepository(filepath.Join(projectDir, ".complaints-mcp"), tracing.NewMockTracer("test"))
+		localCopy, err := local.FindByID(ctx, complaint.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(localCopy.IsResolved()).To(BeTrue())
+
+		unresolved, err := complaintService.ListUnresolvedComplaints(ctx, 10)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(unresolved).To(BeEmpty())
+	})
+})
@@ -0,0 +1,153 @@
+package bdd_test
+
+import (
+	"hash/fnv"
+	"time"
+
+	"github.com/larsartmann/complaints-mcp/internal/domain"
+	apperrors "github.com/larsartmann/complaints-mcp/internal/errors"
+	"github.com/larsartmann/complaints-mcp/internal/repo"
+	"github.com/larsartmann/complaints-mcp/internal/retention"
+	"github.com/larsartmann/complaints-mcp/internal/service"
+	"github.com/larsartmann/complaints-mcp/internal/tracing"
+	"github.com/larsartmann/complaints-mcp/internal/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("File Locking BDD Tests", func() {
+	var (
+		tempDir string
+		tracer  tracing.Tracer
+		// first and second stand in for two server processes sharing tempDir
+		first  *repo.FileRepository
+		second *repo.FileRepository
+	)
+
+	expectTimeout := func(err error) {
+		appErr, ok := apperrors.IsAppError(err)
+		Expect(ok).To(BeTrue(), "expected an AppError, got %v", err)
+		Expect(appErr.Code).To(Equal(apperrors.ErrCodeTimeout))
+	}
+
+	BeforeEach(func() {
+		tempDir = GinkgoT().TempDir()
+		tracer = tracing.NewMockTracer("test")
+		first = repo.NewFileRepository(tempDir, tracer)
+		second = repo.NewFileRepository(tempDir, tracer)
+		second.SetLockTimeout(50 * time.Millisecond)
+	})
+
+	It("should time out writes while another process holds the complaint", func(ctx SpecContext) {
+		complaint := fileTestComplaint(ctx, service.NewComplaintService(first, tracer), testComplaint{})
+
+		_, unlock, err := first.LockComplaint(ctx, complaint.ID)
+		Expect(err).NotTo(HaveOccurred())
+
+		expectTimeout(second.Update(ctx, complaint))
+		expectTimeout(second.Delete(ctx, complaint.ID))
+
+		_, err = service.NewComplaintService(second, tracer).ResolveComplaint(ctx, complaint.ID, "other")
+		expectTimeout(err)
+
+		unlock()
+
+		Expect(second.Update(ctx, complaint)).To(Succeed())
+	})
+
+	It("should let the lock holder write without waiting on itself", func(ctx SpecContext) {
+		complaint := fileTestComplaint(ctx, service.NewComplaintService(first, tracer), testComplaint{})
+
+		lockedCtx, unlock, err := first.LockComplaint(ctx, complaint.ID)
+		Expect(err).NotTo(HaveOccurred())
+
+		defer unlock()
+
+		complaint.ContextInfo = "updated under lock"
+		Expect(first.Update(lockedCtx, complaint)).To(Succeed())
+	})
+
+	It("should not lose another process's update when resolving", func(ctx SpecContext) {
+		complaint := fileTestComplaint(ctx, service.NewComplaintService(first, tracer), testComplaint{})
+
+		// The first process has the complaint cached from an earlier read
+		cached := repo.NewSimpleCachedRepository(first, 10, types.EvictionLRU)
+		_, err := cached.FindByID(ctx, complaint.ID)
+		Expect(err).NotTo(HaveOccurred())
+
+		updated, err := second.FindByID(ctx, complaint.ID)
+		Expect(err).NotTo(HaveOccurred())
+
+		updated.ContextInfo = "written by the second process"
+		Expect(second.Update(ctx, updated)).To(Succeed())
+
+		resolved, err := service.NewComplaintService(cached, tracer).ResolveComplaint(ctx, complaint.ID, "first")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resolved.ContextInfo).To(Equal("written by the second process"))
+
+		stored, err := second.FindByID(ctx, complaint.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stored.IsResolved()).To(BeTrue())
+		Expect(stored.ContextInfo).To(Equal("written by the second process"))
+	})
+
+	It("should not hold up writes of the lock holder behind a waiting writer", func(ctx SpecContext) {
+		complaint := fileTestComplaint(ctx, service.NewComplaintService(first, tracer), testComplaint{})
+		repository := repo.NewIndexedRepository(repo.NewSimpleCachedRepository(first, 10, types.EvictionLRU))
+
+		lockedCtx, unlock, err := repository.LockComplaint(ctx, complaint.ID)
+		Expect(err).NotTo(HaveOccurred())
+
+		// A new complaint whose lock file is the held one
+		neighbour := complaint.Clone()
+		for neighbour.ID == complaint.ID || lockStripe(neighbour.ID) != lockStripe(complaint.ID) {
+			neighbour.ID, err = domain.NewComplaintID()
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		saved := make(chan error, 1)
+		go func() { saved <- repository.Save(ctx, neighbour) }()
+
+		// Let the save start waiting for the lock
+		Consistently(saved, 100*time.Millisecond).ShouldNot(Receive())
+
+		start := time.Now()
+		complaint.ContextInfo = "updated under lock"
+		Expect(repository.Update(lockedCtx, complaint)).To(Succeed())
+		Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+
+		unlock()
+
+		Eventually(saved).Should(Receive(BeNil()))
+	})
+
+	It("should keep maintenance jobs in different processes apart", func(ctx SpecContext) {
+		unlock, err := first.LockStore(ctx)
+		Expect(err).NotTo(HaveOccurred())
+
+		sweeper := retention.NewSweeper(second, retention.Policy{
+			MaxAge:   time.Hour,
+			Mode:     types.RetentionModeDelete,
+			Interval: time.Hour,
+		}, tempDir, tracer)
+
+		_, err = sweeper.Sweep(ctx)
+		expectTimeout(err)
+
+		_, err = second.Repair(ctx)
+		expectTimeout(err)
+
+		unlock()
+
+		_, err = sweeper.Sweep(ctx)
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
+
+// lockStripe returns which of the store's complaint lock files guards id.
+func lockStripe(id domain.ComplaintID) uint32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(id.String()))
+
+	return h.Sum32() % 64
+}
@@ -0,0 +1,133 @@
+package bdd_test
+
+import (
+	"github.com/larsartmann/complaints-mcp/internal/domain"
+	apperrors "github.com/larsartmann/complaints-mcp/internal/errors"
+	"github.com/larsartmann/complaints-mcp/internal/repo"
+	"github.com/larsartmann/complaints-mcp/internal/service"
+	"github.com/larsartmann/complaints-mcp/internal/tracing"
+	"github.com/larsartmann/complaints-mcp/internal/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Optimistic Concurrency BDD Tests", func() {
+	var (
+		tempDir          string
+		tracer           tracing.Tracer
+		repository       *repo.FileRepository
+		complaintService *service.ComplaintService
+	)
+
+	expectConflict := func(err error) {
+		appErr, ok := apperrors.IsAppError(err)
+		Expect(ok).To(BeTrue(), "expected an AppError, got %v", err)
+		Expect(appErr.Code).To(Equal(apperrors.ErrCodeConflict))
+	}
+
+	BeforeEach(func() {
+		tempDir = GinkgoT().TempDir()
+		tracer = tracing.NewMockTracer("test")
+		repository = repo.NewFileRepository(tempDir, tracer)
+		complaintService = service.NewComplaintService(repository, tracer)
+	})
+
+	It("should start new complaints at version 1 and bump it on every update", func(ctx SpecContext) {
+		complaint := fileTestComplaint(ctx, complaintService, testComplaint{})
+		Expect(complaint.Version).To(Equal(uint64(1)))
+
+		resolved, err := complaintService.ResolveComplaint(ctx, complaint.ID, "first-agent")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resolved.Version).To(Equal(uint64(2)))
+
+		stored, err := repository.FindByID(ctx, complaint.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stored.Version).To(Equal(uint64(2)))
+	})
+
+	It("should reject repository updates made against a stale version", func(ctx SpecContext) {
+		complaint := fileTestComplaint(ctx, complaintService, testComplaint{})
+
+		first, err := repository.FindByID(ctx, complaint.ID)
+		Expect(err).NotTo(HaveOccurred())
+
+		second, err := repository.FindByID(ctx, complaint.ID)
+		Expect(err).NotTo(HaveOccurred())
+
+		first.ContextInfo = "first edit"
+		Expect(repository.Update(ctx, first)).To(Succeed())
+
+		second.ContextInfo = "second edit"
+		err = repository.Update(ctx, second)
+		expectConflict(err)
+		Expect(second.Version).To(Equal(uint64(1)), "a rejected write keeps its version")
+
+		stored, err := repository.FindByID(ctx, complaint.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stored.ContextInfo).To(Equal("first edit"))
+	})
+
+	It("should reject a resolution against an expected version that moved on", func(ctx SpecContext) {
+		complaint := fileTestComplaint(ctx, complaintService, testComplaint{})
+
+		_, err := complaintService.ResolveComplaintAtVersion(ctx, complaint.ID, "first-agent", 1)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = complaintService.ResolveComplaintAtVersion(ctx, complaint.ID, "second-agent", 1)
+		expectConflict(err)
+
+		stored, err := repository.FindByID(ctx, complaint.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stored.ResolvedBy).To(Equal("first-agent"))
+	})
+
+	It("should check the expected version of comments and confirmations", func(ctx SpecContext) {
+		complaint := fileTestComplaint(ctx, complaintService, testComplaint{})
+
+		_, commented, err := complaintService.AddComment(ctx, complaint.ID,
+			"maintainer", domain.CommentAuthorHuman, "Which version were you on?", 1)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(commented.Version).To(Equal(uint64(2)))
+
+		_, _, err = complaintService.AddComment(ctx, complaint.ID,
+			"BDD Agent", domain.CommentAuthorAgent, "The latest one", 1)
+		expectConflict(err)
+
+		_, err = complaintService.ConfirmComplaint(ctx, complaint.ID, "Second Agent", "session-2", 1)
+		expectConflict(err)
+
+		confirmed, err := complaintService.ConfirmComplaint(ctx, complaint.ID, "Second Agent", "session-2", 2)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(confirmed.Version).To(Equal(uint64(3)))
+		Expect(confirmed.Comments).To(HaveLen(1))
+	})
+
+	It("should leave cached copies untouched when an update is rejected", func(ctx SpecContext) {
+		complaint := fileTestComplaint(ctx, complaintService, testComplaint{})
+
+		cached := repo.NewSimpleCachedRepository(repository, 10, types.EvictionLRU)
+		cachedService := service.NewComplaintService(cached, tracer)
+
+		_, err := cachedService.ResolveComplaintAtVersion(ctx, complaint.ID, "agent", 7)
+		expectConflict(err)
+
+		found, err := cached.FindByID(ctx, complaint.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found.IsResolved()).To(BeFalse())
+		Expect(found.Version).To(Equal(uint64(1)))
+	})
+
+	It("should version complaints stored before versioning existed", func(ctx SpecContext) {
+		complaint := fileTestComplaint(ctx, complaintService, testComplaint{})
+
+		legacy, err := repository.FindByID(ctx, complaint.ID)
+		Expect(err).NotTo(HaveOccurred())
+
+		legacy.Version = 0
+		Expect(repository.Save(ctx, legacy)).To(Succeed())
+
+		resolved, err := complaintService.ResolveComplaint(ctx, complaint.ID, "agent")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resolved.Version).To(Equal(uint64(1)))
+	})
+})
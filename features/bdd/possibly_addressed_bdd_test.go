@@ -0,0 +1,125 @@
+package bdd_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/larsartmann/complaints-mcp/internal/domain"
+	"github.com/larsartmann/complaints-mcp/internal/repo"
+	"github.com/larsartmann/complaints-mcp/internal/service"
+	"github.com/larsartmann/complaints-mcp/internal/tracing"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Possibly Addressed Complaints BDD Tests", func() {
+	var (
+		projectDir       string
+		worktree         *v5.Worktree
+		complaintService *service.ComplaintService
+	)
+
+	// commit writes files into the project repository and commits them at when.
+	commit := func(when time.Time, files map[string]string) {
+		for path, content := range files {
+			Expect(os.MkdirAll(filepath.Dir(filepath.Join(projectDir, path)), 0o755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(projectDir, path), []byte(content), 0o644)).To(Succeed())
+
+			_, err := worktree.Add(path)
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		_, err := worktree.Commit("Update docs", &v5.CommitOptions{
+			Author: &object.Signature{Name: "Maintainer", Email: "maintainer@example.com", When: when},
+		})
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	possiblyAddressed := func(ctx context.Context) []domain.ComplaintID {
+		found, err := complaintService.FindPossiblyAddressed(ctx, 10)
+		Expect(err).NotTo(HaveOccurred())
+
+		ids := make([]domain.ComplaintID, 0, len(found))
+		for _, complaint := range found {
+			ids = append(ids, complaint.Complaint.ID)
+		}
+
+		return ids
+	}
+
+	BeforeEach(func() {
+		projectDir = GinkgoT().TempDir()
+
+		gitRepo, err := v5.PlainInit(projectDir, false)
+		Expect(err).NotTo(HaveOccurred())
+
+		worktree, err = gitRepo.Worktree()
+		Expect(err).NotTo(HaveOccurred())
+
+		commit(time.Now().Add(-time.Hour), map[string]string{
+			"docs/setup.md": "# Setup\n",
+			"cmd/main.go":   "package main\n",
+			"README.md":     "# Project\n",
+		})
+
+		tracer := tracing.NewMockTracer("test")
+		complaintService = service.NewComplaintServiceWithDetector(
+			repo.NewFileRepository(GinkgoT().TempDir(), tracer), tracer, fixedProjectDetector{root: projectDir},
+		)
+	})
+
+	It("should flag open complaints whose files changed after they were filed", func(ctx SpecContext) {
+		referenced := fileTestComplaint(ctx, complaintService, testComplaint{
+			ProjectRoot: projectDir,
+			Task:        "Setup docs are wrong",
+			Options: []service.ComplaintOption{
+				service.WithReferences(domain.CodeRef{Path: "docs/setup.md", StartLine: 1}),
+			},
+		})
+		mentioned := fileTestComplaint(ctx, complaintService, testComplaint{
+			ProjectRoot: projectDir,
+			Task:        "Entry point is confusing",
+			Context:     "Read `cmd/main.go:1` twice",
+		})
+		fileTestComplaint(ctx, complaintService, testComplaint{
+			ProjectRoot: projectDir,
+			Task:        "Unrelated",
+			Context:     "Nothing to do with any file",
+		})
+
+		Expect(possiblyAddressed(ctx)).To(BeEmpty())
+
+		commit(time.Now().Add(time.Minute), map[string]string{"docs/setup.md": "# Setup\n\nRun make.\n"})
+
+		found, err := complaintService.FindPossiblyAddressed(ctx, 10)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found).To(HaveLen(1))
+		Expect(found[0].Complaint.ID).To(Equal(referenced.ID))
+		Expect(found[0].Changes).To(HaveLen(1))
+		Expect(found[0].Changes[0].Path).To(Equal("docs/setup.md"))
+
+		commit(time.Now().Add(2*time.Minute), map[string]string{
+			"cmd/main.go": "package main\n\nfunc main() {}\n",
+			"README.md":   "# Project\n\nSee docs.\n",
+		})
+		Expect(possiblyAddressed(ctx)).To(Equal([]domain.ComplaintID{mentioned.ID, referenced.ID}))
+
+		_, err = complaintService.ResolveComplaint(ctx, referenced.ID, "maintainer")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(possiblyAddressed(ctx)).To(Equal([]domain.ComplaintID{mentioned.ID}))
+	})
+
+	It("should skip complaints filed outside a known repository", func(ctx SpecContext) {
+		commit(time.Now().Add(time.Minute), map[string]string{"docs/setup.md": "# Setup\n\nRun make.\n"})
+
+		fileTestComplaint(ctx, complaintService, testComplaint{
+			Task:    "No working directory",
+			Context: "See docs/setup.md",
+		})
+
+		Expect(possiblyAddressed(ctx)).To(BeEmpty())
+	})
+})
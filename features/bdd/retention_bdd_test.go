@@ -0,0 +1,183 @@
+package bdd_test
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/larsartmann/complaints-mcp/internal/domain"
+	"github.com/larsartmann/complaints-mcp/internal/embed"
+	"github.com/larsartmann/complaints-mcp/internal/repo"
+	"github.com/larsartmann/complaints-mcp/internal/retention"
+	"github.com/larsartmann/complaints-mcp/internal/tracing"
+	"github.com/larsartmann/complaints-mcp/internal/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Retention Sweeper BDD Tests", func() {
+	var (
+		tempDir    string
+		tracer     tracing.Tracer
+		repository *repo.FileRepository
+	)
+
+	newSweeper := func(mode types.RetentionMode, resolvedOnly bool) *retention.Sweeper {
+		return retention.NewSweeper(repository, retention.Policy{
+			MaxAge:       30 * 24 * time.Hour,
+			Mode:         mode,
+			ResolvedOnly: resolvedOnly,
+			Interval:     time.Hour,
+		}, tempDir, tracer)
+	}
+
+	BeforeEach(func() {
+		tempDir = GinkgoT().TempDir()
+		tracer = tracing.NewMockTracer("test")
+		repository = repo.NewFileRepository(tempDir, tracer)
+	})
+
+	It("should only prune resolved complaints past the window by default", func(ctx SpecContext) {
+		oldResolved := saveTestComplaint(ctx, repository, testComplaint{Age: 40 * 24 * time.Hour, Resolved: true})
+		oldOpen := saveTestComplaint(ctx, repository, testComplaint{Age: 40 * 24 * time.Hour})
+		recentResolved := saveTestComplaint(ctx, repository, testComplaint{Age: 24 * time.Hour, Resolved: true})
+
+		result, err := newSweeper(types.RetentionModeDelete, true).Sweep(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Examined).To(Equal(3))
+		Expect(result.Pruned).To(Equal(1))
+		Expect(result.ArchivePath).To(BeEmpty())
+
+		_, err = repository.FindByID(ctx, oldResolved.ID)
+		Expect(err).To(HaveOccurred())
+
+		for _, kept := range []*domain.Complaint{oldOpen, recentResolved} {
+			_, err = repository.FindByID(ctx, kept.ID)
+			Expect(err).NotTo(HaveOccurred())
+		}
+	})
+
+	It("should measure the window of resolved complaints from their resolution", func(ctx SpecContext) {
+		lateResolved := newTestComplaint(testComplaint{Age: 40 * 24 * time.Hour, Resolved: true})
+		resolvedAt := time.Now().Add(-24 * time.Hour)
+		lateResolved.ResolvedAt = &resolvedAt
+		Expect(repository.Save(ctx, lateResolved)).To(Succeed())
+
+		result, err := newSweeper(types.RetentionModeDelete, true).Sweep(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Pruned).To(BeZero())
+
+		result, err = newSweeper(types.RetentionModeDelete, false).Sweep(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Pruned).To(Equal(1))
+	})
+
+	It("should remove the documents and vectors of pruned complaints", func(ctx SpecContext) {
+		pruned := newTestComplaint(testComplaint{Age: 40 * 24 * time.Hour, Resolved: true})
+		pruned.DocsPath = filepath.Join(tempDir, "docs", "pruned.md")
+		Expect(os.MkdirAll(filepath.Dir(pruned.DocsPath), 0o755)).To(Succeed())
+		Expect(os.WriteFile(pruned.DocsPath, []byte("# Pruned"), 0o644)).To(Succeed())
+		Expect(repository.Save(ctx, pruned)).To(Succeed())
+
+		kept := saveTestComplaint(ctx, repository, testComplaint{Age: 24 * time.Hour, Resolved: true})
+
+		vectorsPath := filepath.Join(tempDir, embed.FileName)
+		vectors := embed.NewStore(embed.NewHashEmbedder(embed.DefaultDimensions), vectorsPath)
+		_, err := vectors.Vectors(ctx, []*domain.Complaint{pruned, kept})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(vectors.Flush()).To(Succeed())
+
+		sweeper := newSweeper(types.RetentionModeDelete, true)
+		sweeper.SetEmbeddings(vectors)
+
+		result, err := sweeper.Sweep(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Pruned).To(Equal(1))
+
+		Expect(pruned.DocsPath).NotTo(BeAnExistingFile())
+
+		data, err := os.ReadFile(vectorsPath)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(ContainSubstring(kept.ID.String()))
+		Expect(string(data)).NotTo(ContainSubstring(pruned.ID.String()))
+	})
+
+	It("should prune open complaints when not restricted to resolved ones", func(ctx SpecContext) {
+		saveTestComplaint(ctx, repository, testComplaint{Age: 40 * 24 * time.Hour})
+		saveTestComplaint(ctx, repository, testComplaint{Age: 40 * 24 * time.Hour, Resolved: true})
+
+		result, err := newSweeper(types.RetentionModeDelete, false).Sweep(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Pruned).To(Equal(2))
+
+		remaining, err := repository.FindAll(ctx, 10, 0)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(remaining).To(BeEmpty())
+	})
+
+	It("should archive expired complaints and their documents into a compressed bundle", func(ctx SpecContext) {
+		expired := newTestComplaint(testComplaint{Age: 40 * 24 * time.Hour, Resolved: true})
+		expired.DocsPath = filepath.Join(tempDir, "docs", "expired.md")
+		Expect(os.MkdirAll(filepath.Dir(expired.DocsPath), 0o755)).To(Succeed())
+		Expect(os.WriteFile(expired.DocsPath, []byte("# Expired"), 0o644)).To(Succeed())
+		Expect(repository.Save(ctx, expired)).To(Succeed())
+
+		result, err := newSweeper(types.RetentionModeArchive, true).Sweep(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Pruned).To(Equal(1))
+		Expect(result.ArchivePath).To(HaveSuffix(".tar.gz"))
+
+		entries, err := os.ReadDir(filepath.Dir(result.ArchivePath))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(entries).To(HaveLen(1)) // no temp file left behind
+
+		f, err := os.Open(result.ArchivePath)
+		Expect(err).NotTo(HaveOccurred())
+		defer f.Close()
+
+		gz, err := gzip.NewReader(f)
+		Expect(err).NotTo(HaveOccurred())
+
+		tr := tar.NewReader(gz)
+
+		header, err := tr.Next()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(header.Name).To(Equal(expired.ID.String() + ".json"))
+
+		header, err = tr.Next()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(header.Name).To(Equal("docs/" + expired.ID.String() + ".md"))
+
+		doc, err := io.ReadAll(tr)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(doc)).To(Equal("# Expired"))
+		Expect(expired.DocsPath).NotTo(BeAnExistingFile())
+
+		_, err = tr.Next()
+		Expect(err).To(MatchError(io.EOF))
+	})
+
+	It("should stop running when its context is cancelled", func(ctx SpecContext) {
+		saveTestComplaint(ctx, repository, testComplaint{Age: 40 * 24 * time.Hour, Resolved: true})
+
+		runCtx, cancel := context.WithCancel(ctx)
+		done := make(chan struct{})
+
+		go func() {
+			defer close(done)
+
+			newSweeper(types.RetentionModeDelete, true).Run(runCtx)
+		}()
+
+		Eventually(func() ([]*domain.Complaint, error) {
+			return repository.FindAll(ctx, 10, 0)
+		}).Should(BeEmpty())
+
+		cancel()
+		Eventually(done).Should(BeClosed())
+	})
+})
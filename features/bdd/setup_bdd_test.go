@@ -1,8 +1,14 @@
 package bdd_test
 
 import (
+	"cmp"
+	"context"
 	"testing"
+	"time"
 
+	"github.com/larsartmann/complaints-mcp/internal/domain"
+	"github.com/larsartmann/complaints-mcp/internal/repo"
+	"github.com/larsartmann/complaints-mcp/internal/service"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
@@ -11,3 +17,90 @@ func TestBDD(t *testing.T) {
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "BDD Test Suite", Label("bdd"))
 }
+
+// testComplaint describes a complaint a spec needs. Empty fields get
+// defaults, so specs only spell out what they check.
+type testComplaint struct {
+	Agent       string
+	Session     string
+	Project     string
+	ProjectRoot string // working directory the project is detected from
+	Task        string
+	Context     string
+	Missing     string
+	Severity    domain.Severity
+	Age         time.Duration // backdates a complaint built by newTestComplaint, resolution included
+	Resolved    bool          // resolves a complaint built by newTestComplaint
+	Options     []service.ComplaintOption
+}
+
+// withDefaults fills in the fields a spec left empty.
+func (c testComplaint) withDefaults() testComplaint {
+	c.Agent = cmp.Or(c.Agent, "BDD Agent")
+	c.Session = cmp.Or(c.Session, "bdd-session")
+	c.Project = cmp.Or(c.Project, "bdd-project")
+	c.Task = cmp.Or(c.Task, "Filing a complaint")
+	c.Severity = cmp.Or(c.Severity, domain.SeverityMedium)
+
+	return c
+}
+
+// fileTestComplaint files a complaint through the service and expects it to succeed.
+func fileTestComplaint(
+	ctx context.Context,
+	complaintService *service.ComplaintService,
+	c testComplaint,
+) *domain.Complaint {
+	c = c.withDefaults()
+
+	complaint, err := complaintService.CreateComplaint(ctx,
+		c.Agent, c.Session, c.Task, c.Context, c.Missing, "", "",
+		c.Severity, c.Project, c.ProjectRoot, c.Options...)
+	Expect(err).NotTo(HaveOccurred())
+
+	return complaint
+}
+
+// newTestComplaint builds a complaint without storing it.
+func newTestComplaint(c testComplaint) *domain.Complaint {
+	c = c.withDefaults()
+
+	id, err := domain.NewComplaintID()
+	Expect(err).NotTo(HaveOccurred())
+
+	complaint := &domain.Complaint{
+		ID:              id,
+		AgentID:         domain.MustParseAgentID(c.Agent),
+		SessionID:       domain.MustParseSessionID(c.Session),
+		ProjectID:       domain.MustParseProjectID(c.Project),
+		TaskDescription: c.Task,
+		ContextInfo:     c.Context,
+		MissingInfo:     c.Missing,
+		Severity:        c.Severity,
+		Timestamp:       time.Now().Add(-c.Age),
+		ResolutionState: domain.ResolutionStateOpen,
+	}
+
+	for _, opt := range c.Options {
+		opt(complaint)
+	}
+
+	if c.Resolved {
+		Expect(complaint.Resolve("bdd-test")).To(Succeed())
+
+		resolvedAt := complaint.Timestamp
+		complaint.ResolvedAt = &resolvedAt
+	}
+
+	return complaint
+}
+
+// saveTestComplaint saves a complaint straight to the repository, skipping
+// the service's checks, and expects it to succeed. Specs seeding many or
+// backdated complaints use it.
+func saveTestComplaint(ctx context.Context, repository repo.Repository, c testComplaint) *domain.Complaint {
+	complaint := newTestComplaint(c)
+	Expect(repository.Save(ctx, complaint)).To(Succeed())
+
+	return complaint
+}
@@ -0,0 +1,152 @@
+package bdd_test
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/larsartmann/complaints-mcp/internal/domain"
+	"github.com/larsartmann/complaints-mcp/internal/repo"
+	"github.com/larsartmann/complaints-mcp/internal/service"
+	"github.com/larsartmann/complaints-mcp/internal/tracing"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SQLite Repository BDD Tests", func() {
+	var (
+		tempDir          string
+		repository       *repo.SQLiteRepository
+		complaintService *service.ComplaintService
+		tracer           tracing.Tracer
+	)
+
+	BeforeEach(func() {
+		tempDir = GinkgoT().TempDir()
+		tracer = tracing.NewMockTracer("test")
+
+		var err error
+
+		repository, err = repo.NewSQLiteRepository(tempDir, tracer)
+		Expect(err).NotTo(HaveOccurred())
+
+		complaintService = service.NewComplaintService(repository, tracer)
+	})
+
+	AfterEach(func() {
+		Expect(repository.Close()).To(Succeed())
+		os.RemoveAll(tempDir)
+	})
+
+	Context("Basic persistence", func() {
+		It("should save and find a complaint by ID", func(ctx SpecContext) {
+			complaint := fileTestComplaint(ctx, complaintService, testComplaint{
+				Project: "sqlite-project", Task: "Schema migration docs missing", Severity: domain.SeverityHigh,
+			})
+
+			found, err := complaintService.GetComplaint(ctx, complaint.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found.ID).To(Equal(complaint.ID))
+			Expect(found.TaskDescription).To(Equal("Schema migration docs missing"))
+			Expect(found.ProjectID.String()).To(Equal("sqlite-project"))
+		})
+
+		It("should update resolution state in place", func(ctx SpecContext) {
+			complaint := fileTestComplaint(ctx, complaintService, testComplaint{})
+
+			_, err := complaintService.ResolveComplaint(ctx, complaint.ID, "maintainer")
+			Expect(err).NotTo(HaveOccurred())
+
+			unresolved, err := repository.FindUnresolved(ctx, 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(unresolved).To(BeEmpty())
+		})
+
+		It("should report a missing complaint on delete", func(ctx SpecContext) {
+			id, err := domain.NewComplaintID()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(repository.Delete(ctx, id)).NotTo(Succeed())
+		})
+	})
+
+	Context("Indexed queries", func() {
+		BeforeEach(func(ctx SpecContext) {
+			for i := range 3 {
+				fileTestComplaint(ctx, complaintService, testComplaint{
+					Agent:    fmt.Sprintf("Agent %d", i),
+					Session:  fmt.Sprintf("session-%d", i),
+					Project:  "project-a",
+					Task:     fmt.Sprintf("Task %d about 100%% coverage", i),
+					Severity: domain.SeverityHigh,
+				})
+			}
+
+			fileTestComplaint(ctx, complaintService, testComplaint{
+				Agent: "Agent 9", Session: "session-9", Project: "project-b",
+				Task: "Unrelated low priority task", Severity: domain.SeverityLow,
+			})
+		})
+
+		It("should filter by severity, project, agent and session", func(ctx SpecContext) {
+			high, err := repository.FindBySeverity(ctx, domain.SeverityHigh, 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(high).To(HaveLen(3))
+
+			byProject, err := repository.FindByProject(ctx, "project-b", 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(byProject).To(HaveLen(1))
+
+			byAgent, err := repository.FindByAgent(ctx, "Agent 1", 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(byAgent).To(HaveLen(1))
+
+			bySession, err := repository.FindBySession(ctx, "session-2", 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(bySession).To(HaveLen(1))
+		})
+
+		It("should match LIKE wildcards literally when searching", func(ctx SpecContext) {
+			results, err := complaintService.SearchComplaints(ctx, "100%", 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results).To(HaveLen(3))
+
+			results, err = complaintService.SearchComplaints(ctx, "_", 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results).To(BeEmpty())
+		})
+
+		It("should paginate FindAll and return oldest first", func(ctx SpecContext) {
+			page, err := complaintService.ListComplaints(ctx, 2, 0)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(page).To(HaveLen(2))
+			Expect(page[0].Timestamp.Before(page[1].Timestamp)).To(BeTrue())
+
+			rest, err := complaintService.ListComplaints(ctx, 10, 2)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rest).To(HaveLen(2))
+		})
+	})
+
+	Context("Large stores", func() {
+		It("should see complaints past the first thousand", func(ctx SpecContext) {
+			for i := range 1005 {
+				severity := domain.SeverityLow
+				if i == 0 {
+					severity = domain.SeverityCritical
+				}
+
+				saveTestComplaint(ctx, repository, testComplaint{
+					Task:     fmt.Sprintf("bulk task %d", i),
+					Severity: severity,
+					Age:      time.Duration(1005-i) * time.Second,
+				})
+			}
+
+			critical, err := repository.FindBySeverity(ctx, domain.SeverityCritical, 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(critical).To(HaveLen(1))
+			Expect(critical[0].TaskDescription).To(Equal("bulk task 0"))
+		})
+	})
+})
@@ -0,0 +1,155 @@
+package bdd_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/larsartmann/complaints-mcp/internal/domain"
+	apperrors "github.com/larsartmann/complaints-mcp/internal/errors"
+	"github.com/larsartmann/complaints-mcp/internal/repo"
+	"github.com/larsartmann/complaints-mcp/internal/tracing"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Storage Integrity BDD Tests", func() {
+	var (
+		tempDir       string
+		complaintsDir string
+		repository    *repo.FileRepository
+	)
+
+	// corrupt overwrites a complaint file in place, like a crash mid-write would.
+	corrupt := func(ctx context.Context, id domain.ComplaintID, data string) string {
+		path, err := repository.GetFilePath(ctx, id)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.WriteFile(path, []byte(data), 0o644)).To(Succeed())
+
+		return path
+	}
+
+	quarantined := func() []string {
+		matches, err := filepath.Glob(filepath.Join(complaintsDir, "corrupt", "*.json"))
+		Expect(err).NotTo(HaveOccurred())
+
+		return matches
+	}
+
+	BeforeEach(func() {
+		tempDir = GinkgoT().TempDir()
+		complaintsDir = filepath.Join(tempDir, "complaints")
+		repository = repo.NewFileRepository(tempDir, tracing.NewMockTracer("test"))
+	})
+
+	It("should leave only complete complaint files behind", func(ctx SpecContext) {
+		complaint := saveTestComplaint(ctx, repository, testComplaint{})
+
+		var (
+			wg        sync.WaitGroup
+			won       atomic.Int32
+			conflicts atomic.Int32
+		)
+
+		for i := range 20 {
+			wg.Add(1)
+
+			go func() {
+				defer GinkgoRecover()
+				defer wg.Done()
+
+				updated := complaint.Clone()
+				updated.ContextInfo = string(rune('a' + i))
+
+				err := repository.Update(ctx, updated)
+				if err == nil {
+					won.Add(1)
+
+					return
+				}
+
+				appErr, ok := apperrors.IsAppError(err)
+				Expect(ok).To(BeTrue())
+				Expect(appErr.Code).To(Equal(apperrors.ErrCodeConflict))
+				conflicts.Add(1)
+			}()
+		}
+
+		wg.Wait()
+
+		// Every writer read the same version, so only the first one may win
+		Expect(won.Load()).To(Equal(int32(1)))
+		Expect(conflicts.Load()).To(Equal(int32(19)))
+
+		found, err := repository.FindByID(ctx, complaint.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found.ID).To(Equal(complaint.ID))
+		Expect(found.Version).To(Equal(complaint.Version + 1))
+
+		temps, err := filepath.Glob(filepath.Join(complaintsDir, ".*.tmp"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(temps).To(BeEmpty(), "no temp files should remain")
+	})
+
+	It("should quarantine zero-byte and truncated files during repair", func(ctx SpecContext) {
+		healthy := saveTestComplaint(ctx, repository, testComplaint{})
+		empty := saveTestComplaint(ctx, repository, testComplaint{})
+		truncated := saveTestComplaint(ctx, repository, testComplaint{})
+
+		emptyPath := corrupt(ctx, empty.ID, "")
+		truncatedPath := corrupt(ctx, truncated.ID, `{"id":"`)
+
+		report, err := repository.Repair(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(report.Scanned).To(Equal(3))
+		Expect(report.Quarantined).To(HaveLen(2))
+		Expect(quarantined()).To(ConsistOf(report.Quarantined))
+
+		for _, path := range []string{emptyPath, truncatedPath} {
+			Expect(path).NotTo(BeAnExistingFile())
+		}
+
+		all, err := repository.FindAll(ctx, 10, 0)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(all).To(HaveLen(1))
+		Expect(all[0].ID).To(Equal(healthy.ID))
+	})
+
+	It("should quarantine corrupt files it encounters while reading", func(ctx SpecContext) {
+		saveTestComplaint(ctx, repository, testComplaint{})
+		broken := saveTestComplaint(ctx, repository, testComplaint{})
+		corrupt(ctx, broken.ID, "{not json")
+
+		all, err := repository.FindAll(ctx, 10, 0)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(all).To(HaveLen(1))
+		Expect(quarantined()).To(HaveLen(1))
+
+		_, err = repository.FindByID(ctx, broken.ID)
+		Expect(err).To(MatchError(ContainSubstring("complaint not found")))
+	})
+
+	It("should remove temp files abandoned by interrupted writes", func(ctx SpecContext) {
+		complaint := saveTestComplaint(ctx, repository, testComplaint{})
+
+		stale := filepath.Join(complaintsDir, "."+complaint.ID.String()+".json-123.tmp")
+		fresh := filepath.Join(complaintsDir, "."+complaint.ID.String()+".json-456.tmp")
+
+		for _, path := range []string{stale, fresh} {
+			Expect(os.WriteFile(path, []byte(`{"id":`), 0o644)).To(Succeed())
+		}
+
+		old := time.Now().Add(-time.Hour)
+		Expect(os.Chtimes(stale, old, old)).To(Succeed())
+
+		report, err := repository.Repair(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(report.StaleTemps).To(Equal(1))
+		Expect(report.Quarantined).To(BeEmpty())
+		Expect(stale).NotTo(BeAnExistingFile())
+		Expect(fresh).To(BeAnExistingFile(), "a write may still be in flight")
+	})
+})
@@ -0,0 +1,192 @@
+package bdd_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/larsartmann/complaints-mcp/internal/config"
+	"github.com/larsartmann/complaints-mcp/internal/domain"
+	apperrors "github.com/larsartmann/complaints-mcp/internal/errors"
+	"github.com/larsartmann/complaints-mcp/internal/repo"
+	"github.com/larsartmann/complaints-mcp/internal/service"
+	"github.com/larsartmann/complaints-mcp/internal/tracing"
+	"github.com/larsartmann/complaints-mcp/internal/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Storage Quota BDD Tests", func() {
+	var (
+		tempDir  string
+		tracer   tracing.Tracer
+		fileRepo *repo.FileRepository
+	)
+
+	// usage reports the bytes currently used by the underlying store.
+	usage := func(ctx context.Context) int64 {
+		used, err := fileRepo.StorageUsage(ctx)
+		Expect(err).NotTo(HaveOccurred())
+
+		return used
+	}
+
+	BeforeEach(func() {
+		tempDir = GinkgoT().TempDir()
+		tracer = tracing.NewMockTracer("test")
+		fileRepo = repo.NewFileRepository(tempDir, tracer)
+	})
+
+	It("should reject a save that would exceed the quota with a storage error", func(ctx SpecContext) {
+		saveTestComplaint(ctx, fileRepo, testComplaint{Age: time.Hour})
+
+		quotaRepo, err := repo.NewQuotaRepository(fileRepo, usage(ctx)+10, false)
+		Expect(err).NotTo(HaveOccurred())
+
+		rejected := newTestComplaint(testComplaint{})
+		err = quotaRepo.Save(ctx, rejected)
+		Expect(err).To(HaveOccurred())
+
+		appErr, ok := apperrors.IsAppError(err)
+		Expect(ok).To(BeTrue())
+		Expect(appErr.Code).To(Equal(apperrors.ErrCodeStorage))
+		Expect(appErr.Details).To(BeAssignableToTypeOf(apperrors.QuotaDetails{}))
+
+		_, err = fileRepo.FindByID(ctx, rejected.ID)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should surface the storage error through the service", func(ctx SpecContext) {
+		quotaRepo, err := repo.NewQuotaRepository(fileRepo, 1024, false)
+		Expect(err).NotTo(HaveOccurred())
+
+		complaintService := service.NewComplaintService(quotaRepo, tracer)
+
+		var lastErr error
+
+		for range 20 {
+			_, lastErr = complaintService.CreateComplaint(ctx,
+				"Runaway Agent", "loop-session", "Filing in a loop",
+				"", "", "", "", domain.SeverityLow, "quota-project", "")
+			if lastErr != nil {
+				break
+			}
+		}
+
+		appErr, ok := apperrors.IsAppError(lastErr)
+		Expect(ok).To(BeTrue())
+		Expect(appErr.Code).To(Equal(apperrors.ErrCodeStorage))
+		Expect(usage(ctx)).To(BeNumerically("<=", 1024))
+	})
+
+	It("should prune the oldest resolved complaints first when enabled", func(ctx SpecContext) {
+		oldest := saveTestComplaint(ctx, fileRepo, testComplaint{Age: 3 * time.Hour, Resolved: true})
+		open := saveTestComplaint(ctx, fileRepo, testComplaint{Age: 4 * time.Hour})
+		newer := saveTestComplaint(ctx, fileRepo, testComplaint{Age: 2 * time.Hour, Resolved: true})
+
+		quotaRepo, err := repo.NewQuotaRepository(fileRepo, usage(ctx)+10, true)
+		Expect(err).NotTo(HaveOccurred())
+
+		incoming := newTestComplaint(testComplaint{})
+		Expect(quotaRepo.Save(ctx, incoming)).To(Succeed())
+
+		_, err = fileRepo.FindByID(ctx, oldest.ID)
+		Expect(err).To(HaveOccurred())
+
+		for _, kept := range []*domain.Complaint{open, newer, incoming} {
+			_, err = fileRepo.FindByID(ctx, kept.ID)
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		stats, err := quotaRepo.GetStorageStats(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stats.PruneEnabled).To(BeTrue())
+		Expect(stats.PrunedComplaints).To(Equal(int64(1)))
+	})
+
+	It("should accept an update that does not grow the complaint at the limit", func(ctx SpecContext) {
+		complaint := saveTestComplaint(ctx, fileRepo, testComplaint{Age: time.Hour})
+
+		quotaRepo, err := repo.NewQuotaRepository(fileRepo, usage(ctx), false)
+		Expect(err).NotTo(HaveOccurred())
+
+		complaint.TaskDescription = strings.ToUpper(complaint.TaskDescription)
+		Expect(quotaRepo.Update(ctx, complaint)).To(Succeed())
+
+		complaint.TaskDescription += " and then some"
+		err = quotaRepo.Update(ctx, complaint)
+
+		appErr, ok := apperrors.IsAppError(err)
+		Expect(ok).To(BeTrue())
+		Expect(appErr.Code).To(Equal(apperrors.ErrCodeStorage))
+	})
+
+	It("should count rendered documents and remove them when pruning", func(ctx SpecContext) {
+		docsDir := filepath.Join(tempDir, "docs")
+		Expect(os.MkdirAll(docsDir, 0o755)).To(Succeed())
+
+		resolved := newTestComplaint(testComplaint{Age: time.Hour, Resolved: true})
+		resolved.DocsPath = filepath.Join(docsDir, "resolved.md")
+		Expect(os.WriteFile(resolved.DocsPath, make([]byte, 4096), 0o644)).To(Succeed())
+		Expect(fileRepo.Save(ctx, resolved)).To(Succeed())
+
+		quotaRepo, err := repo.NewQuotaRepository(fileRepo, usage(ctx)+4096, true)
+		Expect(err).NotTo(HaveOccurred())
+		quotaRepo.SetExtraPaths(docsDir)
+
+		stats, err := quotaRepo.GetStorageStats(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stats.UsedBytes).To(Equal(usage(ctx) + 4096))
+
+		Expect(quotaRepo.Save(ctx, newTestComplaint(testComplaint{}))).To(Succeed())
+
+		_, err = fileRepo.FindByID(ctx, resolved.ID)
+		Expect(err).To(HaveOccurred())
+		Expect(resolved.DocsPath).NotTo(BeAnExistingFile())
+	})
+
+	It("should still reject when only open complaints remain", func(ctx SpecContext) {
+		saveTestComplaint(ctx, fileRepo, testComplaint{Age: time.Hour})
+
+		quotaRepo, err := repo.NewQuotaRepository(fileRepo, usage(ctx)+10, true)
+		Expect(err).NotTo(HaveOccurred())
+
+		err = quotaRepo.Save(ctx, newTestComplaint(testComplaint{}))
+
+		appErr, ok := apperrors.IsAppError(err)
+		Expect(ok).To(BeTrue())
+		Expect(appErr.Code).To(Equal(apperrors.ErrCodeStorage))
+	})
+
+	It("should report usage through the service", func(ctx SpecContext) {
+		cfg := &config.Config{Storage: config.StorageConfig{
+			BaseDir:        tempDir,
+			MaxSize:        1024 * 1024,
+			CacheEnabled:   true,
+			CacheSize:      types.CacheSize(10),
+			EvictionPolicy: types.EvictionLRU,
+		}}
+
+		repository, err := repo.NewRepositoryFromConfig(cfg, tracer)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(repository).To(BeAssignableToTypeOf(&repo.QuotaRepository{}))
+
+		complaintService := service.NewComplaintService(repository, tracer)
+
+		fileTestComplaint(ctx, complaintService, testComplaint{})
+
+		stats, enabled, err := complaintService.GetStorageStats(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(enabled).To(BeTrue())
+		Expect(stats.UsedBytes).To(Equal(usage(ctx)))
+		Expect(stats.MaxBytes).To(Equal(int64(1024 * 1024)))
+		Expect(stats.UsagePercent).To(BeNumerically(">", 0))
+		Expect(complaintService.GetCacheStats().MaxCacheSize).To(Equal(int64(10)))
+
+		_, enabled, err = service.NewComplaintService(fileRepo, tracer).GetStorageStats(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(enabled).To(BeFalse())
+	})
+})
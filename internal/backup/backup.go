@@ -0,0 +1,322 @@
+// Package backup takes rotating snapshot archives of stored complaints and
+// their rendered documents, and restores them.
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	v2 "charm.land/log/v2"
+	"github.com/larsartmann/complaints-mcp/internal/repo"
+	"github.com/larsartmann/complaints-mcp/internal/tracing"
+)
+
+const (
+	// manifestName is the archive entry describing every other entry.
+	manifestName    = "manifest.json"
+	manifestVersion = 1
+
+	complaintsPrefix = "complaints/"
+	docsPrefix       = "docs/"
+
+	snapshotPrefix = "snapshot-"
+	snapshotSuffix = ".tar.gz"
+	snapshotLayout = "20060102-150405.000000000"
+
+	kindComplaint = "complaint"
+	kindDoc       = "doc"
+)
+
+// Dir returns where snapshots are kept for a storage base dir.
+func Dir(baseDir string) string {
+	return filepath.Join(baseDir, "backups")
+}
+
+// Manifest lists the contents of a snapshot with their checksums.
+type Manifest struct {
+	Version    int             `json:"version"`
+	CreatedAt  time.Time       `json:"created_at"`
+	Complaints int             `json:"complaints"`
+	Docs       int             `json:"docs"`
+	Entries    []ManifestEntry `json:"entries"`
+}
+
+// ManifestEntry describes one file in a snapshot.
+type ManifestEntry struct {
+	Name        string `json:"name"` // path inside the archive
+	Kind        string `json:"kind"` // "complaint" or "doc"
+	Size        int64  `json:"size"`
+	SHA256      string `json:"sha256"`
+	RestorePath string `json:"restore_path,omitempty"` // docs only: where the document lived
+}
+
+// Manager takes and rotates snapshots of a repository.
+type Manager struct {
+	repo     repo.Repository
+	dir      string
+	keep     int
+	interval time.Duration
+	tracer   tracing.Tracer
+}
+
+// NewManager creates a manager writing snapshots to dir and keeping the newest keep of them.
+func NewManager(
+	repository repo.Repository,
+	dir string,
+	keep int,
+	interval time.Duration,
+	tracer tracing.Tracer,
+) *Manager {
+	return &Manager{
+		repo:     repository,
+		dir:      dir,
+		keep:     max(keep, 1),
+		interval: interval,
+		tracer:   tracer,
+	}
+}
+
+// Run snapshots whenever the newest snapshot is older than the interval,
+// checking every interval until ctx is cancelled. Restarting the server
+// therefore does not rotate out older snapshots early.
+func (m *Manager) Run(ctx context.Context) {
+	logger := v2.FromContext(ctx)
+
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		if m.due() {
+			path, _, err := m.Snapshot(ctx)
+			if err != nil && ctx.Err() == nil {
+				logger.Warn("Backup snapshot failed", "error", err)
+			} else if err == nil {
+				logger.Info("Backup snapshot written", "path", path)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Snapshot writes a new snapshot, rotates old ones and returns the new path.
+//
+// Complaints are read through the repository, so each one is captured as a
+// complete, decodable record; files caught mid-write are skipped rather than
+// archived half-written. The archive itself only appears once fully written.
+func (m *Manager) Snapshot(ctx context.Context) (string, Manifest, error) {
+	ctx, span := m.tracer.Start(ctx, "BackupManager.Snapshot")
+	defer span.End()
+
+	now := time.Now().UTC()
+	manifest := Manifest{Version: manifestVersion, CreatedAt: now}
+
+	// Keep maintenance jobs in other processes out while the store is read
+	if locker, ok := m.repo.(repo.StoreLocker); ok {
+		unlock, err := locker.LockStore(ctx)
+		if err != nil {
+			return "", manifest, fmt.Errorf("failed to lock store: %w", err)
+		}
+		defer unlock()
+	}
+
+	complaints, err := m.repo.FindAll(ctx, math.MaxInt32, 0)
+	if err != nil {
+		return "", manifest, fmt.Errorf("failed to list complaints: %w", err)
+	}
+
+	if err := os.MkdirAll(m.dir, 0o755); err != nil {
+		return "", manifest, fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(m.dir, ".snapshot-*.tmp")
+	if err != nil {
+		return "", manifest, fmt.Errorf("failed to create snapshot: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // no-op once renamed
+
+	gz := gzip.NewWriter(tmp)
+	tw := tar.NewWriter(gz)
+
+	add := func(entry ManifestEntry, data []byte) error {
+		entry.Size = int64(len(data))
+		entry.SHA256 = checksum(data)
+		manifest.Entries = append(manifest.Entries, entry)
+
+		return writeEntry(tw, entry.Name, data, now)
+	}
+
+	for _, complaint := range complaints {
+		data, err := json.Marshal(complaint)
+		if err != nil {
+			_ = tmp.Close()
+
+			return "", manifest, fmt.Errorf("failed to marshal complaint: %w", err)
+		}
+
+		name := complaintsPrefix + complaint.ID.String() + ".json"
+		if err := add(ManifestEntry{Name: name, Kind: kindComplaint}, data); err != nil {
+			_ = tmp.Close()
+
+			return "", manifest, fmt.Errorf("failed to write snapshot: %w", err)
+		}
+
+		manifest.Complaints++
+
+		if complaint.DocsPath == "" {
+			continue
+		}
+
+		// Documents are replaced atomically by the docs exporter, so a read sees a whole file
+		doc, err := os.ReadFile(complaint.DocsPath)
+		if err != nil {
+			v2.FromContext(ctx).Debug("Skipping missing complaint document",
+				"path", complaint.DocsPath, "error", err)
+
+			continue
+		}
+
+		name = docsPrefix + complaint.ID.String() + "/" + filepath.Base(complaint.DocsPath)
+		entry := ManifestEntry{Name: name, Kind: kindDoc, RestorePath: complaint.DocsPath}
+
+		if err := add(entry, doc); err != nil {
+			_ = tmp.Close()
+
+			return "", manifest, fmt.Errorf("failed to write snapshot: %w", err)
+		}
+
+		manifest.Docs++
+	}
+
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err == nil {
+		err = writeEntry(tw, manifestName, manifestData, now)
+	}
+
+	if err == nil {
+		err = tw.Close()
+	}
+
+	if err == nil {
+		err = gz.Close()
+	}
+
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return "", manifest, fmt.Errorf("failed to write snapshot: %w", err)
+	}
+
+	path := filepath.Join(m.dir, snapshotPrefix+now.Format(snapshotLayout)+snapshotSuffix)
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", manifest, fmt.Errorf("failed to finalize snapshot: %w", err)
+	}
+
+	span.SetAttribute(ctx, "backup.complaints", manifest.Complaints)
+	span.SetAttribute(ctx, "backup.docs", manifest.Docs)
+
+	if err := m.rotate(); err != nil {
+		v2.FromContext(ctx).Warn("Failed to rotate backup snapshots", "error", err)
+	}
+
+	return path, manifest, nil
+}
+
+// Snapshots lists snapshot paths, oldest first.
+func (m *Manager) Snapshots() ([]string, error) {
+	entries, err := os.ReadDir(m.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	var snapshots []string
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.IsDir() && strings.HasPrefix(name, snapshotPrefix) &&
+			strings.HasSuffix(name, snapshotSuffix) {
+			snapshots = append(snapshots, filepath.Join(m.dir, name))
+		}
+	}
+
+	// The timestamp layout sorts lexically
+	slices.Sort(snapshots)
+
+	return snapshots, nil
+}
+
+// due reports whether the newest snapshot is older than the interval.
+func (m *Manager) due() bool {
+	snapshots, err := m.Snapshots()
+	if err != nil || len(snapshots) == 0 {
+		return true
+	}
+
+	info, err := os.Stat(snapshots[len(snapshots)-1])
+	if err != nil {
+		return true
+	}
+
+	return time.Since(info.ModTime()) >= m.interval
+}
+
+// rotate deletes all but the newest keep snapshots.
+func (m *Manager) rotate() error {
+	snapshots, err := m.Snapshots()
+	if err != nil {
+		return err
+	}
+
+	for len(snapshots) > m.keep {
+		if err := os.Remove(snapshots[0]); err != nil {
+			return err
+		}
+
+		snapshots = snapshots[1:]
+	}
+
+	return nil
+}
+
+func writeEntry(tw *tar.Writer, name string, data []byte, modTime time.Time) error {
+	err := tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0o644,
+		Size:    int64(len(data)),
+		ModTime: modTime,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = tw.Write(data)
+
+	return err
+}
+
+func checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,287 @@
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/larsartmann/complaints-mcp/internal/domain"
+	"github.com/larsartmann/complaints-mcp/internal/repo"
+)
+
+// maxSnapshotSize caps the uncompressed size of a snapshot, so a crafted
+// archive cannot exhaust memory while it is read and checked.
+const maxSnapshotSize = 1 << 30
+
+// RestoreResult reports what a restore wrote (or, on a dry run, would write).
+type RestoreResult struct {
+	Complaints int
+	Docs       int
+}
+
+// Restore validates a snapshot and writes its complaints to repository and
+// their documents to docsDir. Nothing is written unless every checksum
+// matches and every complaint passes domain.Complaint.Validate.
+//
+// The checksums live inside the archive, so they only catch corruption.
+// Document paths are therefore never taken from the snapshot: each document
+// is written to docsDir under its own file name, and its complaint is
+// pointed at it. Complaints whose document is not in the snapshot lose
+// their DocsPath and get a fresh document on their next export.
+//
+// A restored complaint that is still stored gets a Version above the
+// stored one, so clients holding the newer copy see a version conflict
+// rather than overwriting the restored state.
+func Restore(
+	ctx context.Context,
+	path string,
+	repository repo.Repository,
+	docsDir string,
+	dryRun bool,
+) (RestoreResult, error) {
+	var result RestoreResult
+
+	manifest, files, err := readSnapshot(path)
+	if err != nil {
+		return result, err
+	}
+
+	var (
+		complaints []*domain.Complaint
+		byID       = make(map[string]*domain.Complaint)
+		docs       []ManifestEntry
+	)
+
+	for _, entry := range manifest.Entries {
+		data := files[entry.Name]
+
+		switch entry.Kind {
+		case kindComplaint:
+			var complaint domain.Complaint
+			if err := json.Unmarshal(data, &complaint); err != nil {
+				return result, fmt.Errorf("invalid complaint %s: %w", entry.Name, err)
+			}
+
+			if err := complaint.Validate(); err != nil {
+				return result, fmt.Errorf("invalid complaint %s: %w", entry.Name, err)
+			}
+
+			if entry.Name != complaintsPrefix+complaint.ID.String()+".json" {
+				return result, fmt.Errorf("complaint %s does not match its ID %s",
+					entry.Name, complaint.ID.String())
+			}
+
+			complaints = append(complaints, &complaint)
+			byID[complaint.ID.String()] = &complaint
+		case kindDoc:
+			docs = append(docs, entry)
+		default:
+			return result, fmt.Errorf("unknown snapshot entry kind %q for %s", entry.Kind, entry.Name)
+		}
+	}
+
+	targets, err := docTargets(docs, byID, docsDir)
+	if err != nil {
+		return result, err
+	}
+
+	for _, complaint := range complaints {
+		complaint.DocsPath = targets[complaint.ID.String()]
+	}
+
+	result.Complaints = len(complaints)
+	result.Docs = len(docs)
+
+	if dryRun {
+		return result, nil
+	}
+
+	if locker, ok := repository.(repo.StoreLocker); ok {
+		unlock, err := locker.LockStore(ctx)
+		if err != nil {
+			return result, fmt.Errorf("failed to lock store: %w", err)
+		}
+		defer unlock()
+	}
+
+	for _, complaint := range complaints {
+		if current, err := repository.FindByID(ctx, complaint.ID); err == nil {
+			complaint.Version = max(complaint.Version, current.Version) + 1
+		}
+
+		if err := repository.Save(ctx, complaint); err != nil {
+			return result, fmt.Errorf("failed to restore complaint %s: %w", complaint.ID.String(), err)
+		}
+	}
+
+	if len(docs) > 0 {
+		if err := os.MkdirAll(docsDir, 0o755); err != nil {
+			return result, fmt.Errorf("failed to create docs directory: %w", err)
+		}
+	}
+
+	for _, entry := range docs {
+		id, _, _ := splitDocEntry(entry.Name)
+		target := targets[id]
+
+		if err := repo.WriteFileAtomic(target, files[entry.Name]); err != nil {
+			return result, fmt.Errorf("failed to restore document %s: %w", target, err)
+		}
+	}
+
+	return result, nil
+}
+
+// docTargets maps the complaint ID of every document entry to the path the
+// document is restored to: its file name in docsDir, suffixed with the short
+// complaint ID if another document already claimed the name, as the docs
+// exporter does.
+func docTargets(
+	docs []ManifestEntry,
+	complaints map[string]*domain.Complaint,
+	docsDir string,
+) (map[string]string, error) {
+	if len(docs) > 0 && !filepath.IsAbs(docsDir) {
+		return nil, fmt.Errorf("docs directory must be absolute: %q", docsDir)
+	}
+
+	targets := make(map[string]string, len(docs))
+	taken := make(map[string]bool, len(docs))
+
+	for _, entry := range docs {
+		id, name, ok := splitDocEntry(entry.Name)
+		if !ok {
+			return nil, fmt.Errorf("invalid document name: %s", entry.Name)
+		}
+
+		if _, ok := complaints[id]; !ok {
+			return nil, fmt.Errorf("document %s does not belong to a complaint in the snapshot", entry.Name)
+		}
+
+		if _, ok := targets[id]; ok {
+			return nil, fmt.Errorf("complaint %s has more than one document", id)
+		}
+
+		if taken[name] {
+			ext := filepath.Ext(name)
+			name = strings.TrimSuffix(name, ext) + "-" + id[:8] + ext
+		}
+
+		target := filepath.Join(docsDir, name)
+
+		rel, err := filepath.Rel(docsDir, target)
+		if err != nil || !filepath.IsLocal(rel) {
+			return nil, fmt.Errorf("document %s would be restored outside %s", entry.Name, docsDir)
+		}
+
+		targets[id] = target
+		taken[name] = true
+	}
+
+	return targets, nil
+}
+
+// splitDocEntry splits a document entry, docs/<complaint ID>/<file name>,
+// rejecting file names that are not a plain name in a directory.
+func splitDocEntry(entry string) (id, name string, ok bool) {
+	rest, ok := strings.CutPrefix(entry, docsPrefix)
+	if !ok {
+		return "", "", false
+	}
+
+	id, name, ok = strings.Cut(rest, "/")
+	if !ok || name == "" || strings.HasPrefix(name, ".") ||
+		strings.ContainsAny(name, `/\`) || name != filepath.Base(name) {
+		return "", "", false
+	}
+
+	return id, name, true
+}
+
+// readSnapshot loads a snapshot into memory and checks it against its manifest.
+func readSnapshot(path string) (Manifest, map[string][]byte, error) {
+	var manifest Manifest
+
+	f, err := os.Open(path)
+	if err != nil {
+		return manifest, nil, fmt.Errorf("failed to open snapshot: %w", err)
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return manifest, nil, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+
+	tr := tar.NewReader(gz)
+	files := make(map[string][]byte)
+
+	var total int64
+
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return manifest, nil, fmt.Errorf("failed to read snapshot: %w", err)
+		}
+
+		if header.Typeflag != tar.TypeReg || strings.Contains(header.Name, "..") {
+			return manifest, nil, fmt.Errorf("unexpected snapshot entry: %s", header.Name)
+		}
+
+		data, err := io.ReadAll(io.LimitReader(tr, maxSnapshotSize-total+1))
+		if err != nil {
+			return manifest, nil, fmt.Errorf("failed to read snapshot entry %s: %w", header.Name, err)
+		}
+
+		total += int64(len(data))
+		if total > maxSnapshotSize {
+			return manifest, nil, fmt.Errorf("snapshot is larger than %d bytes uncompressed", maxSnapshotSize)
+		}
+
+		files[header.Name] = data
+	}
+
+	manifestData, ok := files[manifestName]
+	if !ok {
+		return manifest, nil, errors.New("snapshot has no manifest")
+	}
+
+	delete(files, manifestName)
+
+	if err := json.Unmarshal(manifestData, &manifest); err != nil {
+		return manifest, nil, fmt.Errorf("invalid snapshot manifest: %w", err)
+	}
+
+	if manifest.Version != manifestVersion {
+		return manifest, nil, fmt.Errorf("unsupported snapshot version %d", manifest.Version)
+	}
+
+	if len(manifest.Entries) != len(files) {
+		return manifest, nil, fmt.Errorf("snapshot has %d entries, manifest lists %d",
+			len(files), len(manifest.Entries))
+	}
+
+	for _, entry := range manifest.Entries {
+		data, ok := files[entry.Name]
+		if !ok {
+			return manifest, nil, fmt.Errorf("snapshot is missing %s", entry.Name)
+		}
+
+		if int64(len(data)) != entry.Size || checksum(data) != entry.SHA256 {
+			return manifest, nil, fmt.Errorf("checksum mismatch for %s", entry.Name)
+		}
+	}
+
+	return manifest, files, nil
+}
@@ -8,18 +8,36 @@ import (
 	"path/filepath"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/adrg/xdg"
+	"github.com/larsartmann/complaints-mcp/internal/domain"
+	"github.com/larsartmann/complaints-mcp/internal/search"
 	"github.com/larsartmann/complaints-mcp/internal/types"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
+const (
+	// defaultRetentionInterval is how often the retention sweeper runs when unset.
+	defaultRetentionInterval = time.Hour
+	// defaultBackupInterval and defaultBackupKeep keep a week of daily snapshots.
+	defaultBackupInterval = 24 * time.Hour
+	defaultBackupKeep     = 7
+	// defaultLockTimeout is how long a write waits for a lock held by another process.
+	defaultLockTimeout = 5 * time.Second
+	// minQuota is the smallest storage.max_size accepted besides 0, no quota.
+	minQuota = 1024
+)
+
 // Config represents the application configuration.
 type Config struct {
-	Server  ServerConfig  `mapstructure:"server"`
-	Storage StorageConfig `mapstructure:"storage"`
-	Log     LogConfig     `mapstructure:"log"`
+	Server     ServerConfig     `mapstructure:"server"`
+	Storage    StorageConfig    `mapstructure:"storage"`
+	Complaints ComplaintsConfig `mapstructure:"complaints"`
+	Search     SearchConfig     `mapstructure:"search"`
+	Embeddings EmbeddingsConfig `mapstructure:"embeddings"`
+	Log        LogConfig        `mapstructure:"log"`
 }
 
 // ServerConfig represents server configuration.
@@ -40,12 +58,27 @@ func (s ServerConfig) Address() string {
 
 // StorageConfig represents storage configuration.
 type StorageConfig struct {
+	Backend    string `mapstructure:"backend"` // "file", "sqlite"
 	BaseDir    string `mapstructure:"base_dir"       validate:"required"`
 	GlobalDir  string `mapstructure:"global_dir"`
-	MaxSize    uint64 `mapstructure:"max_size"       validate:"min=1024"` // uint64: file sizes cannot be negative
-	Retention  uint   `mapstructure:"retention_days"`                     // 0 = infinite retention
+	Dual       bool   `mapstructure:"dual"`                                         // global_dir per project + <project>/.complaints-mcp
+	MaxSize    uint64 `mapstructure:"max_size"       validate:"omitempty,min=1024"` // 0 = no quota
+	QuotaPrune bool   `mapstructure:"quota_prune"`                                  // prune oldest resolved complaints instead of rejecting writes
+	Retention  uint   `mapstructure:"retention_days"`                               // 0 = infinite retention
 	AutoBackup bool   `mapstructure:"auto_backup"`
 
+	// LockTimeout bounds how long writes wait on processes sharing the store
+	LockTimeout time.Duration `mapstructure:"lock_timeout"`
+
+	// Backup snapshot configuration (only runs when auto_backup is set)
+	BackupInterval time.Duration `mapstructure:"backup_interval"` // minimum age of the newest snapshot
+	BackupKeep     uint          `mapstructure:"backup_keep"`     // snapshots kept after rotation
+
+	// Retention sweeper configuration (only runs when retention_days > 0)
+	RetentionMode         string        `mapstructure:"retention_mode"`          // "delete", "archive"
+	RetentionResolvedOnly bool          `mapstructure:"retention_resolved_only"` // spare open ones; age from resolution
+	RetentionInterval     time.Duration `mapstructure:"retention_interval"`      // time between sweeps
+
 	// Documentation storage configuration
 	DocsDir     string `mapstructure:"docs_dir"`
 	DocsEnabled bool   `mapstructure:"docs_enabled"`
@@ -59,6 +92,27 @@ type StorageConfig struct {
 	// Type-safe fields for internal use (populated in postProcessConfig)
 	CacheSize      types.CacheSize           `mapstructure:"-"` // derived from CacheMaxSize
 	EvictionPolicy types.CacheEvictionPolicy `mapstructure:"-"` // derived from CacheEviction
+	StorageBackend types.StorageBackend      `mapstructure:"-"` // derived from Backend
+	Docs           types.DocsConfig          `mapstructure:"-"` // derived from DocsDir, DocsFormat, DocsEnabled
+	PruneMode      types.RetentionMode       `mapstructure:"-"` // derived from RetentionMode
+}
+
+// ComplaintsConfig represents complaint classification configuration.
+type ComplaintsConfig struct {
+	Categories []string `mapstructure:"categories"` // controlled vocabulary for complaint categories
+}
+
+// SearchConfig represents search configuration.
+type SearchConfig struct {
+	FuzzyDistance int `mapstructure:"fuzzy_distance"` // typos tolerated per word in fuzzy searches
+}
+
+// EmbeddingsConfig represents the embedder used to find similar complaints.
+type EmbeddingsConfig struct {
+	Backend string        `mapstructure:"backend"` // hash (offline) or http
+	URL     string        `mapstructure:"url"`     // OpenAI-style embeddings endpoint of a local server
+	Model   string        `mapstructure:"model"`   // model the server embeds with
+	Timeout time.Duration `mapstructure:"timeout"` // per request to the server
 }
 
 // LogConfig represents logging configuration.
@@ -108,6 +162,11 @@ func Load(ctx context.Context, cmd *cobra.Command) (*Config, error) {
 		return nil, fmt.Errorf("failed to bind flags: %w", err)
 	}
 
+	err = bindFlagAliases(v, cmd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind flags: %w", err)
+	}
+
 	// Read configuration
 	err = v.ReadInConfig()
 	if err != nil {
@@ -150,6 +209,29 @@ func Load(ctx context.Context, cmd *cobra.Command) (*Config, error) {
 	return &cfg, nil
 }
 
+// flagAliases maps dash-style CLI flags onto their nested configuration keys.
+var flagAliases = map[string]string{
+	"cache-enabled":  "storage.cache_enabled",
+	"cache-max-size": "storage.cache_max_size",
+	"cache-eviction": "storage.cache_eviction",
+}
+
+// bindFlagAliases binds the flags in flagAliases that the command defines.
+func bindFlagAliases(v *viper.Viper, cmd *cobra.Command) error {
+	for flagName, key := range flagAliases {
+		flag := cmd.PersistentFlags().Lookup(flagName)
+		if flag == nil {
+			continue
+		}
+
+		if err := v.BindPFlag(key, flag); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func setDefaults(v *viper.Viper) {
 	// Server defaults
 	v.SetDefault("server.name", "complaints-mcp")
@@ -157,11 +239,20 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("server.port", 8080)
 
 	// Storage defaults using XDG
+	v.SetDefault("storage.backend", "file")
 	v.SetDefault("storage.base_dir", filepath.Join(xdg.DataHome, "complaints"))
 	v.SetDefault("storage.global_dir", filepath.Join(xdg.DataHome, "complaints"))
-	v.SetDefault("storage.max_size", 10485760)      // 10MB
+	v.SetDefault("storage.dual", false)
+	v.SetDefault("storage.max_size", uint64(0))     // 0 = no quota
+	v.SetDefault("storage.quota_prune", false)      // Reject writes over max_size by default
 	v.SetDefault("storage.retention_days", uint(0)) // 0 = infinite retention
 	v.SetDefault("storage.auto_backup", true)
+	v.SetDefault("storage.lock_timeout", defaultLockTimeout)
+	v.SetDefault("storage.backup_interval", defaultBackupInterval)
+	v.SetDefault("storage.backup_keep", defaultBackupKeep)
+	v.SetDefault("storage.retention_mode", "delete")
+	v.SetDefault("storage.retention_resolved_only", true) // Never prune open complaints unless asked
+	v.SetDefault("storage.retention_interval", defaultRetentionInterval)
 
 	// Documentation storage defaults
 	v.SetDefault("storage.docs_dir", "docs/complaints") // Relative to project root
@@ -173,6 +264,18 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("storage.cache_max_size", 1000) // Maximum complaints to cache
 	v.SetDefault("storage.cache_eviction", "lru")
 
+	// Complaint classification defaults
+	v.SetDefault("complaints.categories", domain.DefaultCategories)
+
+	// Search defaults
+	v.SetDefault("search.fuzzy_distance", search.DefaultFuzziness)
+
+	// Embedding defaults: offline hashing, or a local Ollama server when enabled
+	v.SetDefault("embeddings.backend", "hash")
+	v.SetDefault("embeddings.url", "http://localhost:11434/v1/embeddings")
+	v.SetDefault("embeddings.model", "nomic-embed-text")
+	v.SetDefault("embeddings.timeout", 30*time.Second)
+
 	// Log defaults
 	v.SetDefault("log.level", "info")
 	v.SetDefault("log.format", "text") // text, json, logfmt
@@ -217,6 +320,9 @@ func postProcessConfig(cfg *Config) error {
 		}
 	}
 
+	// Categories are compared against normalized complaint input
+	cfg.Complaints.Categories = domain.NormalizeLabels(cfg.Complaints.Categories)
+
 	return nil
 }
 
@@ -247,12 +353,63 @@ func validateConfig(cfg *Config) error {
 		return errors.New("storage.base_dir is required")
 	}
 
-	if cfg.Storage.MaxSize <= 0 {
-		return errors.New("storage.max_size must be positive")
+	if cfg.Storage.MaxSize != 0 && cfg.Storage.MaxSize < minQuota {
+		return fmt.Errorf("storage.max_size must be 0 (no quota) or at least %d bytes", minQuota)
 	}
 
 	// Storage configuration validation (0 = infinite retention is valid)
 	// No validation needed for Retention as 0 is allowed for infinite retention
+	if err := validateEnum(
+		cfg.Storage.Backend,
+		"storage backend",
+		[]string{"file", "sqlite"},
+	); err != nil {
+		return err
+	}
+
+	storageBackend, err := types.NewStorageBackend(cfg.Storage.Backend)
+	if err != nil {
+		return fmt.Errorf("invalid storage backend: %w", err)
+	}
+
+	cfg.Storage.StorageBackend = storageBackend
+
+	if cfg.Storage.Dual && storageBackend != types.StorageBackendFile {
+		return errors.New("storage.dual requires the file storage backend")
+	}
+
+	// Retention configuration validation
+	if err := validateEnum(
+		cfg.Storage.RetentionMode,
+		"retention mode",
+		[]string{"delete", "archive"},
+	); err != nil {
+		return err
+	}
+
+	pruneMode, err := types.NewRetentionMode(cfg.Storage.RetentionMode)
+	if err != nil {
+		return fmt.Errorf("invalid retention mode: %w", err)
+	}
+
+	cfg.Storage.PruneMode = pruneMode
+
+	if cfg.Storage.RetentionInterval <= 0 {
+		cfg.Storage.RetentionInterval = defaultRetentionInterval
+	}
+
+	if cfg.Storage.LockTimeout <= 0 {
+		cfg.Storage.LockTimeout = defaultLockTimeout
+	}
+
+	// Backup configuration validation
+	if cfg.Storage.BackupInterval <= 0 {
+		cfg.Storage.BackupInterval = defaultBackupInterval
+	}
+
+	if cfg.Storage.BackupKeep == 0 {
+		cfg.Storage.BackupKeep = defaultBackupKeep
+	}
 
 	// Cache configuration validation
 	if err := validateEnum(
@@ -281,6 +438,42 @@ func validateConfig(cfg *Config) error {
 
 	cfg.Storage.EvictionPolicy = evictionPolicy
 
+	if cfg.Search.FuzzyDistance < 0 || cfg.Search.FuzzyDistance > search.MaxFuzziness {
+		return fmt.Errorf("search.fuzzy_distance must be between 0 and %d", search.MaxFuzziness)
+	}
+
+	if err := validateEnum(cfg.Embeddings.Backend, "embeddings backend", []string{"hash", "http"}); err != nil {
+		return err
+	}
+
+	if cfg.Embeddings.Backend == "http" && (cfg.Embeddings.URL == "" || cfg.Embeddings.Model == "") {
+		return errors.New("embeddings.url and embeddings.model are required for the http backend")
+	}
+
+	// Docs export configuration validation
+	if err := validateEnum(
+		cfg.Storage.DocsFormat,
+		"docs format",
+		[]string{"markdown", "html", "text"},
+	); err != nil {
+		return err
+	}
+
+	cfg.Storage.Docs = types.DocsConfig{
+		Dir:     cfg.Storage.DocsDir,
+		Format:  types.DocsFormat(cfg.Storage.DocsFormat),
+		Enabled: cfg.Storage.DocsEnabled,
+	}
+	if cfg.Storage.Docs.Format == "" {
+		cfg.Storage.Docs.Format = types.DocsFormatMarkdown
+	}
+
+	if cfg.Storage.DocsEnabled {
+		if err := cfg.Storage.Docs.Validate(); err != nil {
+			return fmt.Errorf("invalid docs configuration: %w", err)
+		}
+	}
+
 	// Log level validation
 	if err := validateEnum(
 		cfg.Log.Level,
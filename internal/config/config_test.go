@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/larsartmann/complaints-mcp/internal/config"
+	"github.com/larsartmann/complaints-mcp/internal/types"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/require"
 )
@@ -22,6 +23,26 @@ func TestConfig_Load(t *testing.T) {
 	cfg, err := config.Load(ctx, cmd)
 	require.NoError(t, err)
 	require.NotNil(t, cfg)
+	require.Equal(t, 2, cfg.Search.FuzzyDistance)
+	require.Zero(t, cfg.Storage.MaxSize, "no storage quota by default")
+}
+
+func TestConfig_CacheFlagsBindToStorage(t *testing.T) {
+	ctx := t.Context()
+
+	cmd := &cobra.Command{}
+	cmd.PersistentFlags().String("config", "", "config file")
+	cmd.PersistentFlags().Bool("cache-enabled", true, "enable cache")
+	cmd.PersistentFlags().Int("cache-max-size", 1000, "cache size")
+	cmd.PersistentFlags().String("cache-eviction", "lru", "eviction policy")
+
+	require.NoError(t, cmd.PersistentFlags().Set("cache-max-size", "25"))
+	require.NoError(t, cmd.PersistentFlags().Set("cache-eviction", "fifo"))
+
+	cfg, err := config.Load(ctx, cmd)
+	require.NoError(t, err)
+	require.Equal(t, types.CacheSize(25), cfg.Storage.CacheSize)
+	require.Equal(t, types.EvictionFIFO, cfg.Storage.EvictionPolicy)
 }
 
 func TestConfig_ServerConfig(t *testing.T) {
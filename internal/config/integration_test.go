@@ -144,6 +144,86 @@ func TestCacheEvictionPolicyValidation(t *testing.T) {
 	}
 }
 
+func TestDualStorageRequiresFileBackend(t *testing.T) {
+	tests := []struct {
+		name    string
+		backend string
+		wantErr bool
+	}{
+		{
+			name:    "file backend",
+			backend: "file",
+			wantErr: false,
+		},
+		{
+			name:    "sqlite backend",
+			backend: "sqlite",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				Server: ServerConfig{
+					Name: "test",
+					Port: uint16(8080),
+				},
+				Storage: StorageConfig{
+					Backend:      tt.backend,
+					BaseDir:      "/tmp",
+					GlobalDir:    "/tmp",
+					Dual:         true,
+					MaxSize:      uint64(1048576),
+					CacheMaxSize: uint32(100),
+				},
+			}
+
+			err := validateConfig(cfg)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestStorageMaxSizeZeroDisablesQuota(t *testing.T) {
+	tests := []struct {
+		name    string
+		maxSize uint64
+		wantErr bool
+	}{
+		{name: "no quota", maxSize: 0, wantErr: false},
+		{name: "below minimum", maxSize: 512, wantErr: true},
+		{name: "minimum", maxSize: 1024, wantErr: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				Server: ServerConfig{
+					Name: "test",
+					Port: uint16(8080),
+				},
+				Storage: StorageConfig{
+					BaseDir:      "/tmp",
+					MaxSize:      tt.maxSize,
+					CacheMaxSize: uint32(100),
+				},
+			}
+
+			err := validateConfig(cfg)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
 func TestConfigIntegration(t *testing.T) {
 	// Test the full configuration loading process
 	v := viper.New()
@@ -5,6 +5,7 @@ import (
 
 	"github.com/larsartmann/complaints-mcp/internal/domain"
 	"github.com/larsartmann/complaints-mcp/internal/repo"
+	"github.com/larsartmann/complaints-mcp/internal/service"
 )
 
 // ComplaintDTO represents a type-safe transfer object for complaint data.
@@ -18,18 +19,62 @@ type ComplaintDTO struct {
 	ConfusedBy      string     `json:"confused_by,omitempty"`
 	FutureWishes    string     `json:"future_wishes,omitempty"`
 	Severity        string     `json:"severity"`
+	Tags            []string   `json:"tags,omitempty"`
+	Categories      []string   `json:"categories,omitempty"`
 	Timestamp       time.Time  `json:"timestamp"`
 	ProjectID       string     `json:"project_id,omitempty"`
 	Resolved        bool       `json:"resolved"`
+	State           string     `json:"state"`
 	ResolvedAt      *time.Time `json:"resolved_at,omitempty"`
 	ResolvedBy      string     `json:"resolved_by,omitempty"`
 	FilePath        string     `json:"file_path,omitempty"`
 	DocsPath        string     `json:"docs_path,omitempty"`
+	Version         uint64     `json:"version"`
+
+	// Code the complaint is about
+	References []domain.CodeRef `json:"references,omitempty"`
+
+	// Lifecycle moves, read from the history, and discussion thread, oldest first
+	Transitions []domain.StateTransition `json:"transitions,omitempty"`
+	Comments    []domain.Comment         `json:"comments,omitempty"`
+
+	// Likely duplicates found when filed, and links between duplicates
+	SimilarTo      []domain.Similar `json:"similar_to,omitempty"`
+	DuplicateOf    string           `json:"duplicate_of,omitempty"`
+	Duplicates     []string         `json:"duplicates,omitempty"`
+	DuplicateCount int              `json:"duplicate_count,omitempty"` // how many more times this was reported
+
+	// How often the problem was hit, and the priority that gives it
+	Occurrences       []domain.Occurrence `json:"occurrences,omitempty"`
+	OccurrenceCount   int                 `json:"occurrence_count"`
+	EffectivePriority float64             `json:"effective_priority"`
+}
+
+// LikelyDuplicateDTO is an open complaint that a new complaint resembles.
+type LikelyDuplicateDTO struct {
+	ComplaintID     string  `json:"complaint_id"`
+	Score           float64 `json:"score"`
+	AgentName       string  `json:"agent_name"`
+	TaskDescription string  `json:"task_description"`
+	DuplicateCount  int     `json:"duplicate_count,omitempty"`
+	OccurrenceCount int     `json:"occurrence_count"`
+}
+
+// ToLikelyDuplicateDTO converts a complaint and its similarity score to a DTO.
+func ToLikelyDuplicateDTO(c *domain.Complaint, score float64) LikelyDuplicateDTO {
+	return LikelyDuplicateDTO{
+		ComplaintID:     c.ID.String(),
+		Score:           score,
+		AgentName:       c.AgentID.String(),
+		TaskDescription: c.TaskDescription,
+		DuplicateCount:  len(c.Duplicates),
+		OccurrenceCount: c.OccurrenceCount(),
+	}
 }
 
 // ToDTO converts a domain Complaint to a type-safe DTO (standalone function).
 func ToDTO(c *domain.Complaint) ComplaintDTO {
-	return ToDTOWithPaths(c, "", "")
+	return ToDTOWithPaths(c, "", c.DocsPath)
 }
 
 // ToDTOWithPaths converts a domain Complaint to a type-safe DTO with optional file paths.
@@ -44,13 +89,99 @@ func ToDTOWithPaths(c *domain.Complaint, filePath, docsPath string) ComplaintDTO
 		ConfusedBy:      c.ConfusedBy,
 		FutureWishes:    c.FutureWishes,
 		Severity:        string(c.Severity),
+		Tags:            c.Tags,
+		Categories:      c.Categories,
 		Timestamp:       c.Timestamp,
 		ProjectID:       c.ProjectID.String(),
 		Resolved:        c.IsResolved(),
+		State:           string(c.ResolutionState),
 		ResolvedAt:      c.ResolvedAt,
 		ResolvedBy:      c.ResolvedBy,
 		FilePath:        filePath,
 		DocsPath:        docsPath,
+		Version:         c.Version,
+		References:      c.References,
+		Transitions:     c.Transitions(),
+		Comments:        c.Comments,
+		SimilarTo:       c.SimilarTo,
+		DuplicateOf:     c.DuplicateOf.String(),
+		Duplicates:      complaintIDStrings(c.Duplicates),
+		DuplicateCount:  len(c.Duplicates),
+
+		Occurrences:       c.Occurrences,
+		OccurrenceCount:   c.OccurrenceCount(),
+		EffectivePriority: c.EffectivePriority(),
+	}
+}
+
+func complaintIDStrings(ids []domain.ComplaintID) []string {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	strs := make([]string, len(ids))
+	for i, id := range ids {
+		strs[i] = id.String()
+	}
+
+	return strs
+}
+
+// ChangedFileDTO is a commit that changed a file a complaint mentions.
+type ChangedFileDTO struct {
+	Path      string    `json:"path"`
+	Commit    string    `json:"commit"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+// PossiblyAddressedDTO is an open complaint whose files changed after it was filed.
+type PossiblyAddressedDTO struct {
+	Complaint    ComplaintDTO     `json:"complaint"`
+	ChangedFiles []ChangedFileDTO `json:"changed_files"`
+}
+
+// ToPossiblyAddressedDTO converts a possibly addressed complaint to a DTO.
+func ToPossiblyAddressedDTO(found service.PossiblyAddressed) PossiblyAddressedDTO {
+	changed := make([]ChangedFileDTO, 0, len(found.Changes))
+	for _, change := range found.Changes {
+		changed = append(changed, ChangedFileDTO{Path: change.Path, Commit: change.Commit, ChangedAt: change.At})
+	}
+
+	return PossiblyAddressedDTO{
+		Complaint:    ToDTO(found.Complaint),
+		ChangedFiles: changed,
+	}
+}
+
+// SimilarComplaintDTO is a complaint and how similar it is to what it was compared with.
+type SimilarComplaintDTO struct {
+	Complaint ComplaintDTO `json:"complaint"`
+	Score     float64      `json:"score"` // cosine similarity, up to 1
+}
+
+// ToSimilarComplaintDTO converts a similar complaint to a DTO.
+func ToSimilarComplaintDTO(similar service.SimilarComplaint) SimilarComplaintDTO {
+	return SimilarComplaintDTO{
+		Complaint: ToDTO(similar.Complaint),
+		Score:     similar.Score,
+	}
+}
+
+// SearchHitDTO is how well a complaint matched a search, and where.
+type SearchHitDTO struct {
+	ComplaintID string  `json:"complaint_id"`
+	Score       float64 `json:"score"`
+	Field       string  `json:"field,omitempty"`
+	Snippet     string  `json:"snippet,omitempty"` // matched words are wrapped in **
+}
+
+// ToSearchHitDTO converts a ranked search hit to a DTO.
+func ToSearchHitDTO(hit repo.SearchHit) SearchHitDTO {
+	return SearchHitDTO{
+		ComplaintID: hit.Complaint.ID.String(),
+		Score:       hit.Score,
+		Field:       hit.Field,
+		Snippet:     hit.Snippet,
 	}
 }
 
@@ -68,6 +199,10 @@ type FileComplaintRequest struct {
 	Severity        string `json:"severity"         validate:"required,oneof=low medium high critical"`
 	ProjectID       string `json:"project_id"       validate:"omitempty,min=1,max=100"`
 	WorkingDir      string `json:"working_dir"      validate:"omitempty,max=500"`
+
+	Tags       []string         `json:"tags,omitempty"       validate:"omitempty,max=20,dive,max=50"`
+	Categories []string         `json:"categories,omitempty" validate:"omitempty,max=20,dive,max=50"`
+	References []domain.CodeRef `json:"references,omitempty" validate:"omitempty,max=20"`
 }
 
 // ListComplaintsRequest represents the input for listing complaints.
@@ -75,12 +210,14 @@ type ListComplaintsRequest struct {
 	Limit    int    `json:"limit"              validate:"gte=1,lte=100"`
 	Severity string `json:"severity"           validate:"omitempty,oneof=low medium high critical"`
 	Resolved *bool  `json:"resolved,omitempty"`
+	State    string `json:"state,omitempty"    validate:"omitempty,oneof=open acknowledged in_progress resolved wont_fix duplicate reopened"`
 }
 
 // ResolveComplaintRequest represents the input for resolving a complaint.
 type ResolveComplaintRequest struct {
-	ComplaintID string `json:"complaint_id" validate:"required,uuid4"`
-	ResolvedBy  string `json:"resolved_by"  validate:"required,min=1,max=100"`
+	ComplaintID     string `json:"complaint_id"               validate:"required,uuid4"`
+	ResolvedBy      string `json:"resolved_by"                validate:"required,min=1,max=100"`
+	ExpectedVersion uint64 `json:"expected_version,omitempty"`
 }
 
 // SearchComplaintsRequest represents the input for searching complaints.
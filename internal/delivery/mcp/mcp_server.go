@@ -6,6 +6,7 @@ import (
 
 	"github.com/larsartmann/complaints-mcp/internal/config"
 	"github.com/larsartmann/complaints-mcp/internal/domain"
+	"github.com/larsartmann/complaints-mcp/internal/query"
 	"github.com/larsartmann/complaints-mcp/internal/repo"
 	"github.com/larsartmann/complaints-mcp/internal/service"
 	"github.com/larsartmann/complaints-mcp/internal/tracing"
@@ -77,6 +78,102 @@ func (m *MCPServer) Shutdown(ctx context.Context) error {
 	return nil
 }
 
+// Schemas shared by the lifecycle tools.
+var (
+	lifecycleStates = []string{
+		"open", "acknowledged", "in_progress", "resolved", "wont_fix", "duplicate", "reopened",
+	}
+	complaintIDSchema = map[string]any{
+		"type":        "string",
+		"description": "Unique identifier of the complaint",
+		"pattern":     "^[a-f0-9]{8}-[a-f0-9]{4}-[a-f0-9]{4}-[a-f0-9]{12}$",
+	}
+	actorSchema = map[string]any{
+		"type":        "string",
+		"description": "Who is making the change (agent name, user ID, etc.)",
+		"minLength":   1,
+		"maxLength":   100,
+	}
+	expectedVersionSchema = map[string]any{
+		"type":        "integer",
+		"description": "Only apply the change if the complaint is still at this version (from a previous read)",
+		"minimum":     1,
+	}
+	matchSchema = map[string]any{
+		"type":        "string",
+		"description": "Whether complaints need any (default) or all of the given tags and categories",
+		"enum":        []string{"any", "all"},
+	}
+)
+
+// searchQuerySchema describes the query syntax parsed by query.Parse.
+var searchQuerySchema = map[string]any{
+	"type": "string",
+	"description": "Words, \"quoted phrases\" and -excluded words, plus filters such as " +
+		"severity:high,critical project:name agent:claude* session:id resolved:false " +
+		"state:open tag:x category:docs after:2026-09-01 before:2026-10-01; prefix a filter with - to negate it",
+	"minLength": 1,
+	"maxLength": 500,
+}
+
+// referencesSchema describes the code a complaint is about.
+var referencesSchema = map[string]any{
+	"type":        "array",
+	"description": "Code the complaint is about, checked against the detected repository",
+	"maxItems":    20,
+	"items": map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"path": map[string]any{
+				"type":        "string",
+				"description": "File path, relative to the repository root or absolute inside it",
+			},
+			"start_line": map[string]any{"type": "integer", "description": "First line, 1-based", "minimum": 1},
+			"end_line":   map[string]any{"type": "integer", "description": "Last line, inclusive", "minimum": 1},
+			"symbol": map[string]any{
+				"type":        "string",
+				"description": "Function, type or other identifier, e.g. Exporter.Export",
+				"maxLength":   200,
+			},
+			"commit": map[string]any{
+				"type":        "string",
+				"description": "Commit SHA the lines refer to",
+				"pattern":     "^[0-9a-fA-F]{7,40}$",
+			},
+		},
+		"required": []string{"path"},
+	},
+}
+
+// tagsSchema describes a list of free-form tags.
+func tagsSchema(description string) map[string]any {
+	return map[string]any{
+		"type":        "array",
+		"description": description,
+		"items":       map[string]any{"type": "string", "maxLength": 50},
+		"maxItems":    20,
+	}
+}
+
+// idPatternSchema describes an agent, project or session name pattern.
+func idPatternSchema(description string) map[string]any {
+	return map[string]any{
+		"type":        "string",
+		"description": description + "; * matches any run of characters, case is ignored",
+		"maxLength":   200,
+	}
+}
+
+// categoriesSchema describes a list of categories from the configured vocabulary.
+func (m *MCPServer) categoriesSchema(description string) map[string]any {
+	return map[string]any{
+		"type":        "array",
+		"description": description,
+		"items":       map[string]any{"type": "string", "enum": m.service.Categories()},
+		"maxItems":    20,
+	}
+}
+
 // registerTools registers all available MCP tools.
 func (m *MCPServer) registerTools() error {
 	// File complaint tool
@@ -133,6 +230,9 @@ func (m *MCPServer) registerTools() error {
 					"description": "Name of the project (auto-detected if not provided)",
 					"maxLength":   100,
 				},
+				"tags":       tagsSchema("Free-form tags, e.g. the library or command involved"),
+				"categories": m.categoriesSchema("Areas the complaint is about"),
+				"references": referencesSchema,
 			},
 			"required": []string{"agent_name", "task_description", "severity"},
 		},
@@ -156,9 +256,47 @@ func (m *MCPServer) registerTools() error {
 					"description": "Filter by severity level",
 					"enum":        []string{"low", "medium", "high", "critical"},
 				},
+				"severities": map[string]any{
+					"type":        "array",
+					"description": "Filter by any of these severity levels",
+					"items": map[string]any{
+						"type": "string",
+						"enum": []string{"low", "medium", "high", "critical"},
+					},
+				},
 				"resolved": map[string]any{
 					"type":        "boolean",
-					"description": "Filter by resolved status",
+					"description": "List closed (resolved, wont_fix, duplicate) complaints instead of open ones",
+				},
+				"state": map[string]any{
+					"type":        "string",
+					"description": "Filter by lifecycle state (overrides resolved)",
+					"enum":        lifecycleStates,
+				},
+				"from": map[string]any{
+					"type":        "string",
+					"description": "Only list complaints filed at or after this date (midnight UTC) or RFC 3339 time",
+				},
+				"to": map[string]any{
+					"type":        "string",
+					"description": "Only list complaints filed before this date (midnight UTC) or RFC 3339 time",
+				},
+				"project":    idPatternSchema("Only list complaints from this project"),
+				"agent":      idPatternSchema("Only list complaints filed by this agent"),
+				"session":    idPatternSchema("Only list complaints filed in this session"),
+				"tags":       tagsSchema("Only list complaints with these tags"),
+				"categories": m.categoriesSchema("Only list complaints in these categories"),
+				"match":      matchSchema,
+				"sort_by": map[string]any{
+					"type": "string",
+					"description": "Field to order the results by: filing time (default), severity, " +
+						"occurrences, or effective priority (severity weighted by occurrences)",
+					"enum": []string{"timestamp", "severity", "occurrence", "priority"},
+				},
+				"sort_direction": map[string]any{
+					"type":        "string",
+					"description": "Order descending, newest or highest first (default), or ascending",
+					"enum":        []string{"desc", "asc"},
 				},
 			},
 		},
@@ -180,32 +318,292 @@ func (m *MCPServer) registerTools() error {
 					"type":        "string",
 					"description": "Identifier of who resolved the complaint (agent name, user ID, etc.)",
 				},
+				"expected_version": map[string]any{
+					"type":        "integer",
+					"description": "Only resolve if the complaint is still at this version (from a previous read)",
+					"minimum":     1,
+				},
 			},
 			"required": []string{"complaint_id", "resolved_by"},
 		},
 	}
 
+	// Acknowledge complaint tool
+	acknowledgeComplaintTool := &mcp.Tool{
+		Name:        "acknowledge_complaint",
+		Description: "Mark a complaint as seen, or as being worked on",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"complaint_id": complaintIDSchema,
+				"actor":        actorSchema,
+				"reason": map[string]any{
+					"type":        "string",
+					"description": "Optional note for whoever reads the complaint next",
+					"maxLength":   500,
+				},
+				"in_progress": map[string]any{
+					"type":        "boolean",
+					"description": "Mark the complaint as actively being worked on rather than just seen",
+				},
+				"expected_version": expectedVersionSchema,
+			},
+			"required": []string{"complaint_id", "actor"},
+		},
+	}
+
+	// Reopen complaint tool
+	reopenComplaintTool := &mcp.Tool{
+		Name:        "reopen_complaint",
+		Description: "Reopen a resolved, wont_fix or duplicate complaint",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"complaint_id": complaintIDSchema,
+				"actor":        actorSchema,
+				"reason": map[string]any{
+					"type":        "string",
+					"description": "Why the complaint needs attention again",
+					"minLength":   1,
+					"maxLength":   500,
+				},
+				"expected_version": expectedVersionSchema,
+			},
+			"required": []string{"complaint_id", "actor", "reason"},
+		},
+	}
+
+	// Close complaint tool
+	closeComplaintTool := &mcp.Tool{
+		Name:        "close_complaint",
+		Description: "Close a complaint as resolved, wont_fix or duplicate",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"complaint_id": complaintIDSchema,
+				"actor":        actorSchema,
+				"state": map[string]any{
+					"type":        "string",
+					"description": "How the complaint was closed",
+					"enum":        []string{"resolved", "wont_fix", "duplicate"},
+				},
+				"reason": map[string]any{
+					"type":        "string",
+					"description": "Why the complaint was closed (required for wont_fix and duplicate)",
+					"maxLength":   500,
+				},
+				"expected_version": expectedVersionSchema,
+			},
+			"required": []string{"complaint_id", "actor", "state"},
+		},
+	}
+
 	// Search complaints tool
 	searchComplaintsTool := &mcp.Tool{
 		Name:        "search_complaints",
-		Description: "Search complaints by content",
+		Description: "Search complaints by content, best match first, with a highlighted snippet of each",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"query": searchQuerySchema,
+				"limit": map[string]any{
+					"type":        "integer",
+					"description": "Maximum number of results",
+					"minimum":     1,
+					"maximum":     100,
+				},
+				"fuzzy": map[string]any{
+					"type":        "boolean",
+					"description": "Also match words with typos, such as cobar for cobra; exact matches still rank first",
+				},
+				"tags":       tagsSchema("Only return complaints with these tags"),
+				"categories": m.categoriesSchema("Only return complaints in these categories"),
+				"match":      matchSchema,
+			},
+			"required": []string{"query"},
+		},
+	}
+
+	// Find similar complaints tool
+	findSimilarComplaintsTool := &mcp.Tool{
+		Name: "find_similar_complaints",
+		Description: "Find the complaints nearest to a complaint or to free text by embedding similarity, " +
+			"most similar first, to cluster complaints that word the same gap differently",
 		InputSchema: map[string]any{
 			"type": "object",
 			"properties": map[string]any{
-				"query": map[string]any{
+				"complaint_id": complaintIDSchema,
+				"text": map[string]any{
 					"type":        "string",
-					"description": "Search query text",
+					"description": "Free text to compare against, instead of a complaint_id",
 					"minLength":   1,
+					"maxLength":   2000,
+				},
+				"limit": map[string]any{
+					"type":        "integer",
+					"description": "Maximum number of complaints to return",
+					"minimum":     1,
+					"maximum":     100,
+				},
+			},
+		},
+	}
+
+	// Tag complaint tool
+	tagComplaintTool := &mcp.Tool{
+		Name:        "tag_complaint",
+		Description: "Add or remove free-form tags on a complaint",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"complaint_id":     complaintIDSchema,
+				"actor":            actorSchema,
+				"add":              tagsSchema("Tags to add"),
+				"remove":           tagsSchema("Tags to remove"),
+				"expected_version": expectedVersionSchema,
+			},
+			"required": []string{"complaint_id", "actor"},
+		},
+	}
+
+	// Categorize complaint tool
+	categorizeComplaintTool := &mcp.Tool{
+		Name:        "categorize_complaint",
+		Description: "Replace the categories of a complaint",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"complaint_id":     complaintIDSchema,
+				"actor":            actorSchema,
+				"categories":       m.categoriesSchema("The complaint's categories; an empty list clears them"),
+				"expected_version": expectedVersionSchema,
+			},
+			"required": []string{"complaint_id", "actor", "categories"},
+		},
+	}
+
+	// Mark duplicate tool
+	markDuplicateTool := &mcp.Tool{
+		Name:        "mark_duplicate",
+		Description: "Close a complaint as a duplicate of another complaint in the same project and link them",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"complaint_id": complaintIDSchema,
+				"duplicate_of": map[string]any{
+					"type":        "string",
+					"description": "Unique identifier of the complaint this one duplicates",
+					"pattern":     "^[a-f0-9]{8}-[a-f0-9]{4}-[a-f0-9]{4}-[a-f0-9]{12}$",
+				},
+				"actor": actorSchema,
+				"reason": map[string]any{
+					"type":        "string",
+					"description": "Why the complaints are the same (defaults to \"duplicate of <id>\")",
 					"maxLength":   500,
 				},
+				"expected_version": expectedVersionSchema,
+			},
+			"required": []string{"complaint_id", "duplicate_of", "actor"},
+		},
+	}
+
+	// Confirm complaint tool
+	confirmComplaintTool := &mcp.Tool{
+		Name:        "confirm_complaint",
+		Description: "Report that you hit the problem an existing complaint describes, instead of filing a duplicate",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"complaint_id": complaintIDSchema,
+				"agent_name": map[string]any{
+					"type":        "string",
+					"description": "Name of the AI agent confirming the complaint",
+					"minLength":   1,
+					"maxLength":   100,
+				},
+				"session_name": map[string]any{
+					"type":        "string",
+					"description": "Name of the current session",
+					"minLength":   1,
+					"maxLength":   100,
+				},
+				"expected_version": expectedVersionSchema,
+			},
+			"required": []string{"complaint_id", "agent_name", "session_name"},
+		},
+	}
+
+	// Add comment tool
+	addCommentTool := &mcp.Tool{
+		Name:        "add_comment",
+		Description: "Add a comment to a complaint, e.g. context found after filing or a clarifying question",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"complaint_id": complaintIDSchema,
+				"author": map[string]any{
+					"type":        "string",
+					"description": "Who is commenting (agent name, user ID, etc.)",
+					"minLength":   1,
+					"maxLength":   100,
+				},
+				"author_type": map[string]any{
+					"type":        "string",
+					"description": "Whether the author is an agent or a human (default agent)",
+					"enum":        []string{"agent", "human"},
+				},
+				"body": map[string]any{
+					"type":        "string",
+					"description": "Comment text",
+					"minLength":   1,
+					"maxLength":   5000,
+				},
+				"expected_version": expectedVersionSchema,
+			},
+			"required": []string{"complaint_id", "author", "body"},
+		},
+	}
+
+	// List comments tool
+	listCommentsTool := &mcp.Tool{
+		Name:        "list_comments",
+		Description: "List the comments on a complaint, oldest first",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"complaint_id": complaintIDSchema,
+			},
+			"required": []string{"complaint_id"},
+		},
+	}
+
+	// Get complaint history tool
+	getComplaintHistoryTool := &mcp.Tool{
+		Name:        "get_complaint_history",
+		Description: "Get the audit trail of a complaint: who changed what, when, and through which tool",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"complaint_id": complaintIDSchema,
+			},
+			"required": []string{"complaint_id"},
+		},
+	}
+
+	// List possibly addressed tool
+	listPossiblyAddressedTool := &mcp.Tool{
+		Name:        "list_possibly_addressed",
+		Description: "List open complaints whose referenced or mentioned files changed after they were filed",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
 				"limit": map[string]any{
 					"type":        "integer",
-					"description": "Maximum number of results",
+					"description": "Maximum number of complaints to return",
 					"minimum":     1,
 					"maximum":     100,
 				},
 			},
-			"required": []string{"query"},
 		},
 	}
 
@@ -220,35 +618,160 @@ func (m *MCPServer) registerTools() error {
 		},
 	}
 
+	// Get storage stats tool
+	getStorageStatsTool := &mcp.Tool{
+		Name:        "get_storage_stats",
+		Description: "Get storage usage against the configured storage.max_size quota",
+		InputSchema: map[string]any{
+			"type":       "object",
+			"properties": map[string]any{},
+			"required":   []string{},
+		},
+	}
+
 	// Register tools with handlers
 	mcp.AddTool(m.server, fileComplaintTool, m.handleFileComplaint)
 	mcp.AddTool(m.server, listComplaintsTool, m.handleListComplaints)
 	mcp.AddTool(m.server, resolveComplaintTool, m.handleResolveComplaint)
+	mcp.AddTool(m.server, acknowledgeComplaintTool, m.handleAcknowledgeComplaint)
+	mcp.AddTool(m.server, reopenComplaintTool, m.handleReopenComplaint)
+	mcp.AddTool(m.server, closeComplaintTool, m.handleCloseComplaint)
 	mcp.AddTool(m.server, searchComplaintsTool, m.handleSearchComplaints)
+	mcp.AddTool(m.server, findSimilarComplaintsTool, m.handleFindSimilarComplaints)
+	mcp.AddTool(m.server, tagComplaintTool, m.handleTagComplaint)
+	mcp.AddTool(m.server, categorizeComplaintTool, m.handleCategorizeComplaint)
+	mcp.AddTool(m.server, markDuplicateTool, m.handleMarkDuplicate)
+	mcp.AddTool(m.server, confirmComplaintTool, m.handleConfirmComplaint)
+	mcp.AddTool(m.server, addCommentTool, m.handleAddComment)
+	mcp.AddTool(m.server, listCommentsTool, m.handleListComments)
+	mcp.AddTool(m.server, getComplaintHistoryTool, m.handleGetComplaintHistory)
+	mcp.AddTool(m.server, listPossiblyAddressedTool, m.handleListPossiblyAddressed)
 	mcp.AddTool(m.server, getCacheStatsTool, m.handleGetCacheStats)
+	mcp.AddTool(m.server, getStorageStatsTool, m.handleGetStorageStats)
 
 	return nil
 }
 
 // Input types for tool handlers (use FileComplaintRequest from dto.go).
 type ListComplaintsInput struct {
-	Limit    int    `json:"limit"`
-	Severity string `json:"severity"`
-	Resolved bool   `json:"resolved"`
+	Limit      int      `json:"limit"`
+	Severity   string   `json:"severity"`
+	Severities []string `json:"severities,omitempty"`
+	Resolved   bool     `json:"resolved"`
+	State      string   `json:"state,omitempty"`
+	From       string   `json:"from,omitempty"`
+	To         string   `json:"to,omitempty"`
+	Project    string   `json:"project,omitempty"`
+	Agent      string   `json:"agent,omitempty"`
+	Session    string   `json:"session,omitempty"`
+
+	Tags       []string `json:"tags,omitempty"`
+	Categories []string `json:"categories,omitempty"`
+	Match      string   `json:"match,omitempty"`
+
+	SortBy        string `json:"sort_by,omitempty"`
+	SortDirection string `json:"sort_direction,omitempty"`
 }
 
 type ResolveComplaintInput struct {
-	ComplaintID string `json:"complaint_id"`
-	ResolvedBy  string `json:"resolved_by"`
+	ComplaintID     string `json:"complaint_id"`
+	ResolvedBy      string `json:"resolved_by"`
+	ExpectedVersion uint64 `json:"expected_version,omitempty"`
+}
+
+type AcknowledgeComplaintInput struct {
+	ComplaintID     string `json:"complaint_id"`
+	Actor           string `json:"actor"`
+	Reason          string `json:"reason,omitempty"`
+	InProgress      bool   `json:"in_progress,omitempty"`
+	ExpectedVersion uint64 `json:"expected_version,omitempty"`
+}
+
+type ReopenComplaintInput struct {
+	ComplaintID     string `json:"complaint_id"`
+	Actor           string `json:"actor"`
+	Reason          string `json:"reason"`
+	ExpectedVersion uint64 `json:"expected_version,omitempty"`
+}
+
+type CloseComplaintInput struct {
+	ComplaintID     string `json:"complaint_id"`
+	Actor           string `json:"actor"`
+	State           string `json:"state"`
+	Reason          string `json:"reason,omitempty"`
+	ExpectedVersion uint64 `json:"expected_version,omitempty"`
+}
+
+type TagComplaintInput struct {
+	ComplaintID     string   `json:"complaint_id"`
+	Actor           string   `json:"actor"`
+	Add             []string `json:"add,omitempty"`
+	Remove          []string `json:"remove,omitempty"`
+	ExpectedVersion uint64   `json:"expected_version,omitempty"`
+}
+
+type CategorizeComplaintInput struct {
+	ComplaintID     string   `json:"complaint_id"`
+	Actor           string   `json:"actor"`
+	Categories      []string `json:"categories"`
+	ExpectedVersion uint64   `json:"expected_version,omitempty"`
+}
+
+type MarkDuplicateInput struct {
+	ComplaintID     string `json:"complaint_id"`
+	DuplicateOf     string `json:"duplicate_of"`
+	Actor           string `json:"actor"`
+	Reason          string `json:"reason,omitempty"`
+	ExpectedVersion uint64 `json:"expected_version,omitempty"`
+}
+
+type ConfirmComplaintInput struct {
+	ComplaintID     string `json:"complaint_id"`
+	AgentName       string `json:"agent_name"`
+	SessionName     string `json:"session_name"`
+	ExpectedVersion uint64 `json:"expected_version,omitempty"`
 }
 
 type SearchComplaintsInput struct {
 	Query string `json:"query"`
 	Limit int    `json:"limit"`
+	Fuzzy bool   `json:"fuzzy,omitempty"`
+
+	Tags       []string `json:"tags,omitempty"`
+	Categories []string `json:"categories,omitempty"`
+	Match      string   `json:"match,omitempty"`
+}
+
+type FindSimilarComplaintsInput struct {
+	ComplaintID string `json:"complaint_id,omitempty"`
+	Text        string `json:"text,omitempty"`
+	Limit       int    `json:"limit"`
+}
+
+type AddCommentInput struct {
+	ComplaintID     string `json:"complaint_id"`
+	Author          string `json:"author"`
+	AuthorType      string `json:"author_type,omitempty"`
+	Body            string `json:"body"`
+	ExpectedVersion uint64 `json:"expected_version,omitempty"`
+}
+
+type ListCommentsInput struct {
+	ComplaintID string `json:"complaint_id"`
+}
+
+type GetComplaintHistoryInput struct {
+	ComplaintID string `json:"complaint_id"`
+}
+
+type ListPossiblyAddressedInput struct {
+	Limit int `json:"limit"`
 }
 
 type GetCacheStatsInput struct{}
 
+type GetStorageStatsInput struct{}
+
 // defaultLimit returns the input limit or a default of 50 if zero.
 func defaultLimit(inputLimit int) int {
 	if inputLimit == 0 {
@@ -263,6 +786,8 @@ type FileComplaintOutput struct {
 	Success   bool         `json:"success"`
 	Message   string       `json:"message"`
 	Complaint ComplaintDTO `json:"complaint"` // ✅ Type-safe instead of string ID
+
+	LikelyDuplicates []LikelyDuplicateDTO `json:"likely_duplicates,omitempty"` // open complaints it resembles
 }
 
 type ListComplaintsOutput struct {
@@ -275,17 +800,60 @@ type ResolveComplaintOutput struct {
 	Complaint ComplaintDTO `json:"complaint"` // ✅ Type-safe instead of string ID
 }
 
+type UpdateComplaintOutput struct {
+	Success   bool         `json:"success"`
+	Message   string       `json:"message"`
+	Complaint ComplaintDTO `json:"complaint"`
+}
+
 type SearchComplaintsOutput struct {
 	Complaints []ComplaintDTO `json:"complaints"` // ✅ Type-safe instead of []map[string]any
+	Hits       []SearchHitDTO `json:"hits"`       // scores and snippets, in the order of Complaints
 	Query      string         `json:"query"`
 }
 
+type AddCommentOutput struct {
+	Success     bool           `json:"success"`
+	Message     string         `json:"message"`
+	ComplaintID string         `json:"complaint_id"`
+	Version     uint64         `json:"version"` // the complaint's, to pass as the next expected_version
+	Comment     domain.Comment `json:"comment"`
+}
+
+type ListCommentsOutput struct {
+	ComplaintID string           `json:"complaint_id"`
+	Comments    []domain.Comment `json:"comments"`
+	Count       int              `json:"count"`
+}
+
+type GetComplaintHistoryOutput struct {
+	ComplaintID string         `json:"complaint_id"`
+	Events      []domain.Event `json:"events"`
+	Count       int            `json:"count"`
+}
+
+type FindSimilarComplaintsOutput struct {
+	Complaints []SimilarComplaintDTO `json:"complaints"`
+	Count      int                   `json:"count"`
+}
+
+type ListPossiblyAddressedOutput struct {
+	Complaints []PossiblyAddressedDTO `json:"complaints"`
+	Count      int                    `json:"count"`
+}
+
 type GetCacheStatsOutput struct {
 	CacheEnabled bool            `json:"cache_enabled"`
 	Stats        repo.CacheStats `json:"stats"`
 	Message      string          `json:"message"`
 }
 
+type GetStorageStatsOutput struct {
+	QuotaEnabled bool              `json:"quota_enabled"`
+	Stats        repo.StorageStats `json:"stats"`
+	Message      string            `json:"message"`
+}
+
 // handleFileComplaint handles the file_complaint tool.
 func (m *MCPServer) handleFileComplaint(
 	ctx context.Context,
@@ -295,6 +863,8 @@ func (m *MCPServer) handleFileComplaint(
 	ctx, span := m.tracer.Start(ctx, "handleFileComplaint")
 	defer span.End()
 
+	ctx = service.WithTool(ctx, "file_complaint")
+
 	logger := m.logger.With("component", "mcp-server", "tool", "file_complaint")
 	logger.Info("Handling file complaint request")
 
@@ -316,6 +886,9 @@ func (m *MCPServer) handleFileComplaint(
 		domainSeverity,
 		input.ProjectID,
 		input.WorkingDir,
+		service.WithTags(input.Tags...),
+		service.WithCategories(input.Categories...),
+		service.WithReferences(input.References...),
 	)
 	if err != nil {
 		logger.Error("Failed to create complaint", "error", err)
@@ -344,6 +917,24 @@ func (m *MCPServer) handleFileComplaint(
 		Complaint: ToDTOWithPaths(complaint, filePath, docsPath),
 	}
 
+	for _, similar := range complaint.SimilarTo {
+		original, err := m.service.GetComplaint(ctx, similar.ID)
+		if err != nil {
+			logger.Warn("Failed to load likely duplicate", "error", err, "complaint_id", similar.ID.String())
+
+			continue
+		}
+
+		output.LikelyDuplicates = append(output.LikelyDuplicates, ToLikelyDuplicateDTO(original, similar.Score))
+	}
+
+	if len(output.LikelyDuplicates) > 0 {
+		output.Message = fmt.Sprintf(
+			"Complaint filed successfully; it resembles %d open complaint(s), consider mark_duplicate",
+			len(output.LikelyDuplicates),
+		)
+	}
+
 	return nil, output, nil
 }
 
@@ -362,28 +953,12 @@ func (m *MCPServer) handleListComplaints(
 	// Set defaults
 	limit := defaultLimit(input.Limit)
 
-	var severityFilter domain.Severity
-
-	if input.Severity != "" {
-		var err error
-
-		severityFilter, err = domain.ParseSeverity(input.Severity)
-		if err != nil {
-			return nil, ListComplaintsOutput{}, fmt.Errorf("invalid severity filter: %w", err)
-		}
-	}
-
-	var (
-		complaints []*domain.Complaint
-		err        error
-	)
-
-	if severityFilter != "" {
-		complaints, err = m.service.Repository().FindBySeverity(ctx, severityFilter, limit)
-	} else {
-		complaints, err = m.service.ListComplaints(ctx, limit, 0)
+	q, err := listQuery(input)
+	if err != nil {
+		return nil, ListComplaintsOutput{}, err
 	}
 
+	complaints, err := m.service.FindComplaints(ctx, q, limit)
 	if err != nil {
 		logger.Error("Failed to list complaints", "error", err)
 
@@ -394,19 +969,10 @@ func (m *MCPServer) handleListComplaints(
 	var results []ComplaintDTO
 
 	for _, complaint := range complaints {
-		// Apply resolved filter if set
-		if input.Resolved && !complaint.IsResolved() {
-			continue
-		}
-
-		if !input.Resolved && complaint.IsResolved() {
-			continue
-		}
-
 		results = append(results, ToDTO(complaint))
 	}
 
-	logger.Info("Complaints listed successfully", "count", len(results))
+	logger.Info("Complaints listed successfully", "count", len(results), "query", q.String(), "sort", q.Sort.String())
 
 	output := ListComplaintsOutput{
 		Complaints: results,
@@ -415,6 +981,128 @@ func (m *MCPServer) handleListComplaints(
 	return nil, output, nil
 }
 
+// listQuery translates the list_complaints filters and sort into a query.
+func listQuery(input ListComplaintsInput) (query.Query, error) {
+	var q query.Query
+
+	severities := input.Severities
+	if input.Severity != "" {
+		severities = append([]string{input.Severity}, severities...)
+	}
+
+	if len(severities) > 0 {
+		filter := make(query.SeverityFilter, 0, len(severities))
+
+		for _, value := range severities {
+			severity, err := domain.ParseSeverity(value)
+			if err != nil {
+				return query.Query{}, fmt.Errorf("invalid severity filter: %w", err)
+			}
+
+			filter = append(filter, severity)
+		}
+
+		q.Filters = append(q.Filters, filter)
+	}
+
+	if input.State != "" {
+		state, err := domain.ParseResolutionState(input.State)
+		if err != nil {
+			return query.Query{}, fmt.Errorf("invalid state filter: %w", err)
+		}
+
+		q.Filters = append(q.Filters, query.StateFilter{state})
+	} else {
+		q.Filters = append(q.Filters, query.ResolvedFilter(input.Resolved))
+	}
+
+	for _, id := range []query.IDFilter{
+		{Field: domain.ComplaintFieldProjectID, Pattern: input.Project},
+		{Field: domain.ComplaintFieldAgentID, Pattern: input.Agent},
+		{Field: domain.ComplaintFieldSessionID, Pattern: input.Session},
+	} {
+		if id.Pattern != "" {
+			q.Filters = append(q.Filters, id)
+		}
+	}
+
+	if input.From != "" {
+		from, err := query.ParseTime(input.From)
+		if err != nil {
+			return query.Query{}, fmt.Errorf("invalid from: %w", err)
+		}
+
+		q.Filters = append(q.Filters, query.AfterFilter(from))
+	}
+
+	if input.To != "" {
+		to, err := query.ParseTime(input.To)
+		if err != nil {
+			return query.Query{}, fmt.Errorf("invalid to: %w", err)
+		}
+
+		q.Filters = append(q.Filters, query.BeforeFilter(to))
+	}
+
+	labelFilter, err := parseLabelFilter(input.Tags, input.Categories, input.Match)
+	if err != nil {
+		return query.Query{}, err
+	}
+
+	q.Filters = append(q.Filters, labelQueryFilters(labelFilter)...)
+
+	q.Sort, err = query.ParseSort(input.SortBy, input.SortDirection)
+	if err != nil {
+		return query.Query{}, err
+	}
+
+	return q, nil
+}
+
+// labelQueryFilters turns a tag and category filter into query filters:
+// with match any, one filter each for the tags and the categories; with
+// match all, one per tag and per category.
+func labelQueryFilters(f domain.LabelFilter) []query.Filter {
+	var filters []query.Filter
+
+	if f.Mode == domain.MatchAll {
+		for _, tag := range f.Tags {
+			filters = append(filters, query.TagFilter{tag})
+		}
+
+		for _, category := range f.Categories {
+			filters = append(filters, query.CategoryFilter{category})
+		}
+
+		return filters
+	}
+
+	if len(f.Tags) > 0 {
+		filters = append(filters, query.TagFilter(f.Tags))
+	}
+
+	if len(f.Categories) > 0 {
+		filters = append(filters, query.CategoryFilter(f.Categories))
+	}
+
+	return filters
+}
+
+// parseLabelFilter builds the tag and category filter shared by
+// list_complaints and search_complaints.
+func parseLabelFilter(tags, categories []string, match string) (domain.LabelFilter, error) {
+	mode, err := domain.ParseMatchMode(match)
+	if err != nil {
+		return domain.LabelFilter{}, fmt.Errorf("invalid match mode: %w", err)
+	}
+
+	return domain.LabelFilter{
+		Tags:       domain.NormalizeLabels(tags),
+		Categories: domain.NormalizeLabels(categories),
+		Mode:       mode,
+	}, nil
+}
+
 // handleResolveComplaint handles the resolve_complaint tool.
 func (m *MCPServer) handleResolveComplaint(
 	ctx context.Context,
@@ -424,6 +1112,8 @@ func (m *MCPServer) handleResolveComplaint(
 	ctx, span := m.tracer.Start(ctx, "handleResolveComplaint")
 	defer span.End()
 
+	ctx = service.WithTool(ctx, "resolve_complaint")
+
 	logger := m.logger.With("component", "mcp-server", "tool", "resolve_complaint")
 	logger.Info("Handling resolve complaint request")
 
@@ -434,7 +1124,12 @@ func (m *MCPServer) handleResolveComplaint(
 		return nil, ResolveComplaintOutput{}, fmt.Errorf("invalid complaint ID: %w", err)
 	}
 
-	complaint, err := m.service.ResolveComplaint(ctx, complaintID, input.ResolvedBy)
+	complaint, err := m.service.ResolveComplaintAtVersion(
+		ctx,
+		complaintID,
+		input.ResolvedBy,
+		input.ExpectedVersion,
+	)
 	if err != nil {
 		logger.Error(
 			"Failed to resolve complaint",
@@ -455,6 +1150,8 @@ func (m *MCPServer) handleResolveComplaint(
 		input.ComplaintID,
 		"resolved_by",
 		input.ResolvedBy,
+		"version",
+		complaint.Version,
 	)
 
 	output := ResolveComplaintOutput{
@@ -466,6 +1163,97 @@ func (m *MCPServer) handleResolveComplaint(
 	return nil, output, nil
 }
 
+// handleAcknowledgeComplaint handles the acknowledge_complaint tool.
+func (m *MCPServer) handleAcknowledgeComplaint(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input AcknowledgeComplaintInput,
+) (*mcp.CallToolResult, UpdateComplaintOutput, error) {
+	ctx, span := m.tracer.Start(ctx, "handleAcknowledgeComplaint")
+	defer span.End()
+
+	to := domain.ResolutionStateAcknowledged
+	if input.InProgress {
+		to = domain.ResolutionStateInProgress
+	}
+
+	return m.transitionComplaint(ctx, "acknowledge_complaint",
+		input.ComplaintID, to, input.Actor, input.Reason, input.ExpectedVersion)
+}
+
+// handleReopenComplaint handles the reopen_complaint tool.
+func (m *MCPServer) handleReopenComplaint(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input ReopenComplaintInput,
+) (*mcp.CallToolResult, UpdateComplaintOutput, error) {
+	ctx, span := m.tracer.Start(ctx, "handleReopenComplaint")
+	defer span.End()
+
+	return m.transitionComplaint(ctx, "reopen_complaint",
+		input.ComplaintID, domain.ResolutionStateReopened, input.Actor, input.Reason, input.ExpectedVersion)
+}
+
+// handleCloseComplaint handles the close_complaint tool.
+func (m *MCPServer) handleCloseComplaint(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input CloseComplaintInput,
+) (*mcp.CallToolResult, UpdateComplaintOutput, error) {
+	ctx, span := m.tracer.Start(ctx, "handleCloseComplaint")
+	defer span.End()
+
+	to, err := domain.ParseResolutionState(input.State)
+	if err != nil || !to.IsClosed() {
+		return nil, UpdateComplaintOutput{}, fmt.Errorf(
+			"invalid state %q: must be resolved, wont_fix or duplicate", input.State)
+	}
+
+	return m.transitionComplaint(ctx, "close_complaint",
+		input.ComplaintID, to, input.Actor, input.Reason, input.ExpectedVersion)
+}
+
+// transitionComplaint moves a complaint to another lifecycle state on behalf
+// of one of the lifecycle tools.
+func (m *MCPServer) transitionComplaint(
+	ctx context.Context,
+	tool, rawID string,
+	to domain.ResolutionState,
+	actor, reason string,
+	expectedVersion uint64,
+) (*mcp.CallToolResult, UpdateComplaintOutput, error) {
+	ctx = service.WithTool(ctx, tool)
+
+	logger := m.logger.With("component", "mcp-server", "tool", tool)
+	logger.Info("Handling complaint transition request", "state", to)
+
+	complaintID, err := domain.ParseComplaintID(rawID)
+	if err != nil {
+		logger.Error("Invalid complaint ID", "error", err, "complaint_id", rawID)
+
+		return nil, UpdateComplaintOutput{}, fmt.Errorf("invalid complaint ID: %w", err)
+	}
+
+	complaint, err := m.service.TransitionComplaint(ctx, complaintID, to, actor, reason, expectedVersion)
+	if err != nil {
+		logger.Error("Failed to transition complaint",
+			"error", err, "complaint_id", rawID, "state", to, "actor", actor)
+
+		return nil, UpdateComplaintOutput{}, err
+	}
+
+	logger.Info("Complaint transitioned successfully",
+		"complaint_id", rawID, "state", to, "actor", actor, "version", complaint.Version)
+
+	output := UpdateComplaintOutput{
+		Success:   true,
+		Message:   fmt.Sprintf("Complaint is now %s", to),
+		Complaint: ToDTO(complaint),
+	}
+
+	return nil, output, nil
+}
+
 // handleSearchComplaints handles the search_complaints tool.
 func (m *MCPServer) handleSearchComplaints(
 	ctx context.Context,
@@ -480,7 +1268,13 @@ func (m *MCPServer) handleSearchComplaints(
 
 	limit := defaultLimit(input.Limit)
 
-	complaints, err := m.service.SearchComplaints(ctx, input.Query, limit)
+	labelFilter, err := parseLabelFilter(input.Tags, input.Categories, input.Match)
+	if err != nil {
+		return nil, SearchComplaintsOutput{}, err
+	}
+
+	found, err := m.service.SearchComplaintsRanked(ctx, input.Query, limit, input.Fuzzy,
+		labelQueryFilters(labelFilter)...)
 	if err != nil {
 		logger.Error("Failed to search complaints", "error", err)
 
@@ -488,15 +1282,21 @@ func (m *MCPServer) handleSearchComplaints(
 	}
 
 	// Convert to response format
-	var results []ComplaintDTO
-	for _, complaint := range complaints {
-		results = append(results, ToDTO(complaint))
+	var (
+		results []ComplaintDTO
+		hits    []SearchHitDTO
+	)
+
+	for _, hit := range found {
+		results = append(results, ToDTO(hit.Complaint))
+		hits = append(hits, ToSearchHitDTO(hit))
 	}
 
 	logger.Info("Complaints searched successfully", "query", input.Query, "count", len(results))
 
 	output := SearchComplaintsOutput{
 		Complaints: results,
+		Hits:       hits,
 		Query:      input.Query,
 	}
 
@@ -539,3 +1339,420 @@ func (m *MCPServer) handleGetCacheStats(
 
 	return nil, output, nil
 }
+
+// handleTagComplaint handles the tag_complaint tool.
+func (m *MCPServer) handleTagComplaint(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input TagComplaintInput,
+) (*mcp.CallToolResult, UpdateComplaintOutput, error) {
+	ctx, span := m.tracer.Start(ctx, "handleTagComplaint")
+	defer span.End()
+
+	ctx = service.WithTool(ctx, "tag_complaint")
+
+	logger := m.logger.With("component", "mcp-server", "tool", "tag_complaint")
+	logger.Info("Handling tag complaint request")
+
+	complaintID, err := domain.ParseComplaintID(input.ComplaintID)
+	if err != nil {
+		logger.Error("Invalid complaint ID", "error", err, "complaint_id", input.ComplaintID)
+
+		return nil, UpdateComplaintOutput{}, fmt.Errorf("invalid complaint ID: %w", err)
+	}
+
+	complaint, err := m.service.TagComplaint(
+		ctx, complaintID, input.Actor, input.Add, input.Remove, input.ExpectedVersion)
+	if err != nil {
+		logger.Error("Failed to tag complaint", "error", err, "complaint_id", input.ComplaintID)
+
+		return nil, UpdateComplaintOutput{}, err
+	}
+
+	logger.Info("Complaint tagged successfully",
+		"complaint_id", input.ComplaintID, "tags", complaint.Tags, "version", complaint.Version)
+
+	output := UpdateComplaintOutput{
+		Success:   true,
+		Message:   "Complaint tags updated successfully",
+		Complaint: ToDTO(complaint),
+	}
+
+	return nil, output, nil
+}
+
+// handleCategorizeComplaint handles the categorize_complaint tool.
+func (m *MCPServer) handleCategorizeComplaint(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input CategorizeComplaintInput,
+) (*mcp.CallToolResult, UpdateComplaintOutput, error) {
+	ctx, span := m.tracer.Start(ctx, "handleCategorizeComplaint")
+	defer span.End()
+
+	ctx = service.WithTool(ctx, "categorize_complaint")
+
+	logger := m.logger.With("component", "mcp-server", "tool", "categorize_complaint")
+	logger.Info("Handling categorize complaint request")
+
+	complaintID, err := domain.ParseComplaintID(input.ComplaintID)
+	if err != nil {
+		logger.Error("Invalid complaint ID", "error", err, "complaint_id", input.ComplaintID)
+
+		return nil, UpdateComplaintOutput{}, fmt.Errorf("invalid complaint ID: %w", err)
+	}
+
+	complaint, err := m.service.CategorizeComplaint(
+		ctx, complaintID, input.Actor, input.Categories, input.ExpectedVersion)
+	if err != nil {
+		logger.Error("Failed to categorize complaint", "error", err, "complaint_id", input.ComplaintID)
+
+		return nil, UpdateComplaintOutput{}, err
+	}
+
+	logger.Info("Complaint categorized successfully",
+		"complaint_id", input.ComplaintID, "categories", complaint.Categories, "version", complaint.Version)
+
+	output := UpdateComplaintOutput{
+		Success:   true,
+		Message:   "Complaint categories updated successfully",
+		Complaint: ToDTO(complaint),
+	}
+
+	return nil, output, nil
+}
+
+// handleMarkDuplicate handles the mark_duplicate tool.
+func (m *MCPServer) handleMarkDuplicate(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input MarkDuplicateInput,
+) (*mcp.CallToolResult, UpdateComplaintOutput, error) {
+	ctx, span := m.tracer.Start(ctx, "handleMarkDuplicate")
+	defer span.End()
+
+	ctx = service.WithTool(ctx, "mark_duplicate")
+
+	logger := m.logger.With("component", "mcp-server", "tool", "mark_duplicate")
+	logger.Info("Handling mark duplicate request")
+
+	complaintID, err := domain.ParseComplaintID(input.ComplaintID)
+	if err != nil {
+		logger.Error("Invalid complaint ID", "error", err, "complaint_id", input.ComplaintID)
+
+		return nil, UpdateComplaintOutput{}, fmt.Errorf("invalid complaint ID: %w", err)
+	}
+
+	canonicalID, err := domain.ParseComplaintID(input.DuplicateOf)
+	if err != nil {
+		logger.Error("Invalid duplicate_of ID", "error", err, "duplicate_of", input.DuplicateOf)
+
+		return nil, UpdateComplaintOutput{}, fmt.Errorf("invalid duplicate_of ID: %w", err)
+	}
+
+	complaint, err := m.service.MarkDuplicate(
+		ctx, complaintID, canonicalID, input.Actor, input.Reason, input.ExpectedVersion)
+	if err != nil {
+		logger.Error("Failed to mark complaint as duplicate",
+			"error", err, "complaint_id", input.ComplaintID, "duplicate_of", input.DuplicateOf)
+
+		return nil, UpdateComplaintOutput{}, err
+	}
+
+	logger.Info("Complaint marked as duplicate successfully",
+		"complaint_id", input.ComplaintID, "duplicate_of", complaint.DuplicateOf.String(), "version", complaint.Version)
+
+	output := UpdateComplaintOutput{
+		Success:   true,
+		Message:   "Complaint is now a duplicate of " + complaint.DuplicateOf.String(),
+		Complaint: ToDTO(complaint),
+	}
+
+	return nil, output, nil
+}
+
+// handleConfirmComplaint handles the confirm_complaint tool.
+func (m *MCPServer) handleConfirmComplaint(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input ConfirmComplaintInput,
+) (*mcp.CallToolResult, UpdateComplaintOutput, error) {
+	ctx, span := m.tracer.Start(ctx, "handleConfirmComplaint")
+	defer span.End()
+
+	ctx = service.WithTool(ctx, "confirm_complaint")
+
+	logger := m.logger.With("component", "mcp-server", "tool", "confirm_complaint")
+	logger.Info("Handling confirm complaint request")
+
+	complaintID, err := domain.ParseComplaintID(input.ComplaintID)
+	if err != nil {
+		logger.Error("Invalid complaint ID", "error", err, "complaint_id", input.ComplaintID)
+
+		return nil, UpdateComplaintOutput{}, fmt.Errorf("invalid complaint ID: %w", err)
+	}
+
+	complaint, err := m.service.ConfirmComplaint(
+		ctx, complaintID, input.AgentName, input.SessionName, input.ExpectedVersion)
+	if err != nil {
+		logger.Error("Failed to confirm complaint", "error", err, "complaint_id", input.ComplaintID)
+
+		return nil, UpdateComplaintOutput{}, err
+	}
+
+	logger.Info("Complaint confirmed successfully",
+		"complaint_id", complaint.ID.String(), "occurrences", complaint.OccurrenceCount(), "version", complaint.Version)
+
+	output := UpdateComplaintOutput{
+		Success:   true,
+		Message:   fmt.Sprintf("Occurrence recorded; this problem was hit %d time(s)", complaint.OccurrenceCount()),
+		Complaint: ToDTO(complaint),
+	}
+
+	return nil, output, nil
+}
+
+// handleAddComment handles the add_comment tool.
+func (m *MCPServer) handleAddComment(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input AddCommentInput,
+) (*mcp.CallToolResult, AddCommentOutput, error) {
+	ctx, span := m.tracer.Start(ctx, "handleAddComment")
+	defer span.End()
+
+	ctx = service.WithTool(ctx, "add_comment")
+
+	logger := m.logger.With("component", "mcp-server", "tool", "add_comment")
+	logger.Info("Handling add comment request")
+
+	complaintID, err := domain.ParseComplaintID(input.ComplaintID)
+	if err != nil {
+		logger.Error("Invalid complaint ID", "error", err, "complaint_id", input.ComplaintID)
+
+		return nil, AddCommentOutput{}, fmt.Errorf("invalid complaint ID: %w", err)
+	}
+
+	authorType, err := domain.ParseCommentAuthorType(input.AuthorType)
+	if err != nil {
+		return nil, AddCommentOutput{}, fmt.Errorf("invalid author type: %w", err)
+	}
+
+	comment, complaint, err := m.service.AddComment(
+		ctx, complaintID, input.Author, authorType, input.Body, input.ExpectedVersion)
+	if err != nil {
+		logger.Error("Failed to add comment",
+			"error", err, "complaint_id", input.ComplaintID, "author", input.Author)
+
+		return nil, AddCommentOutput{}, err
+	}
+
+	logger.Info("Comment added successfully",
+		"complaint_id", input.ComplaintID, "comment_id", comment.ID, "author", input.Author,
+		"version", complaint.Version)
+
+	output := AddCommentOutput{
+		Success:     true,
+		Message:     "Comment added successfully",
+		ComplaintID: input.ComplaintID,
+		Version:     complaint.Version,
+		Comment:     comment,
+	}
+
+	return nil, output, nil
+}
+
+// handleListComments handles the list_comments tool.
+func (m *MCPServer) handleListComments(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input ListCommentsInput,
+) (*mcp.CallToolResult, ListCommentsOutput, error) {
+	ctx, span := m.tracer.Start(ctx, "handleListComments")
+	defer span.End()
+
+	logger := m.logger.With("component", "mcp-server", "tool", "list_comments")
+	logger.Info("Handling list comments request")
+
+	complaintID, err := domain.ParseComplaintID(input.ComplaintID)
+	if err != nil {
+		logger.Error("Invalid complaint ID", "error", err, "complaint_id", input.ComplaintID)
+
+		return nil, ListCommentsOutput{}, fmt.Errorf("invalid complaint ID: %w", err)
+	}
+
+	comments, err := m.service.ListComments(ctx, complaintID)
+	if err != nil {
+		logger.Error("Failed to list comments", "error", err, "complaint_id", input.ComplaintID)
+
+		return nil, ListCommentsOutput{}, err
+	}
+
+	logger.Info("Comments listed successfully",
+		"complaint_id", input.ComplaintID, "count", len(comments))
+
+	output := ListCommentsOutput{
+		ComplaintID: input.ComplaintID,
+		Comments:    comments,
+		Count:       len(comments),
+	}
+
+	return nil, output, nil
+}
+
+// handleGetComplaintHistory handles the get_complaint_history tool.
+func (m *MCPServer) handleGetComplaintHistory(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input GetComplaintHistoryInput,
+) (*mcp.CallToolResult, GetComplaintHistoryOutput, error) {
+	ctx, span := m.tracer.Start(ctx, "handleGetComplaintHistory")
+	defer span.End()
+
+	logger := m.logger.With("component", "mcp-server", "tool", "get_complaint_history")
+	logger.Info("Handling get complaint history request")
+
+	complaintID, err := domain.ParseComplaintID(input.ComplaintID)
+	if err != nil {
+		logger.Error("Invalid complaint ID", "error", err, "complaint_id", input.ComplaintID)
+
+		return nil, GetComplaintHistoryOutput{}, fmt.Errorf("invalid complaint ID: %w", err)
+	}
+
+	events, err := m.service.GetComplaintHistory(ctx, complaintID)
+	if err != nil {
+		logger.Error("Failed to get complaint history", "error", err, "complaint_id", input.ComplaintID)
+
+		return nil, GetComplaintHistoryOutput{}, err
+	}
+
+	logger.Info("Complaint history retrieved successfully",
+		"complaint_id", input.ComplaintID, "count", len(events))
+
+	output := GetComplaintHistoryOutput{
+		ComplaintID: input.ComplaintID,
+		Events:      events,
+		Count:       len(events),
+	}
+
+	return nil, output, nil
+}
+
+// handleFindSimilarComplaints handles the find_similar_complaints tool.
+func (m *MCPServer) handleFindSimilarComplaints(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input FindSimilarComplaintsInput,
+) (*mcp.CallToolResult, FindSimilarComplaintsOutput, error) {
+	ctx, span := m.tracer.Start(ctx, "handleFindSimilarComplaints")
+	defer span.End()
+
+	logger := m.logger.With("component", "mcp-server", "tool", "find_similar_complaints")
+	logger.Info("Handling find similar complaints request")
+
+	var complaintID domain.ComplaintID
+
+	if input.ComplaintID != "" {
+		id, err := domain.ParseComplaintID(input.ComplaintID)
+		if err != nil {
+			logger.Error("Invalid complaint ID", "error", err, "complaint_id", input.ComplaintID)
+
+			return nil, FindSimilarComplaintsOutput{}, fmt.Errorf("invalid complaint ID: %w", err)
+		}
+
+		complaintID = id
+	}
+
+	found, err := m.service.FindSimilarComplaints(ctx, complaintID, input.Text, defaultLimit(input.Limit))
+	if err != nil {
+		logger.Error("Failed to find similar complaints", "error", err, "complaint_id", input.ComplaintID)
+
+		return nil, FindSimilarComplaintsOutput{}, err
+	}
+
+	complaints := make([]SimilarComplaintDTO, 0, len(found))
+	for _, similar := range found {
+		complaints = append(complaints, ToSimilarComplaintDTO(similar))
+	}
+
+	logger.Info("Similar complaints found successfully", "count", len(complaints))
+
+	output := FindSimilarComplaintsOutput{
+		Complaints: complaints,
+		Count:      len(complaints),
+	}
+
+	return nil, output, nil
+}
+
+// handleListPossiblyAddressed handles the list_possibly_addressed tool.
+func (m *MCPServer) handleListPossiblyAddressed(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input ListPossiblyAddressedInput,
+) (*mcp.CallToolResult, ListPossiblyAddressedOutput, error) {
+	ctx, span := m.tracer.Start(ctx, "handleListPossiblyAddressed")
+	defer span.End()
+
+	logger := m.logger.With("component", "mcp-server", "tool", "list_possibly_addressed")
+	logger.Info("Handling list possibly addressed request")
+
+	found, err := m.service.FindPossiblyAddressed(ctx, defaultLimit(input.Limit))
+	if err != nil {
+		logger.Error("Failed to find possibly addressed complaints", "error", err)
+
+		return nil, ListPossiblyAddressedOutput{}, err
+	}
+
+	complaints := make([]PossiblyAddressedDTO, 0, len(found))
+	for _, complaint := range found {
+		complaints = append(complaints, ToPossiblyAddressedDTO(complaint))
+	}
+
+	logger.Info("Possibly addressed complaints listed successfully", "count", len(complaints))
+
+	output := ListPossiblyAddressedOutput{
+		Complaints: complaints,
+		Count:      len(complaints),
+	}
+
+	return nil, output, nil
+}
+
+// handleGetStorageStats handles the get_storage_stats tool.
+func (m *MCPServer) handleGetStorageStats(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input GetStorageStatsInput,
+) (*mcp.CallToolResult, GetStorageStatsOutput, error) {
+	ctx, span := m.tracer.Start(ctx, "handleGetStorageStats")
+	defer span.End()
+
+	logger := m.logger.With("component", "mcp-server", "tool", "get_storage_stats")
+	logger.Info("Handling get storage stats request")
+
+	stats, quotaEnabled, err := m.service.GetStorageStats(ctx)
+	if err != nil {
+		logger.Error("Failed to get storage stats", "error", err)
+
+		return nil, GetStorageStatsOutput{}, err
+	}
+
+	message := "Storage statistics retrieved successfully"
+	if !quotaEnabled {
+		message = "Storage quota disabled"
+	}
+
+	output := GetStorageStatsOutput{
+		QuotaEnabled: quotaEnabled,
+		Stats:        stats,
+		Message:      message,
+	}
+
+	logger.Info("Storage stats retrieved successfully",
+		"quota_enabled", quotaEnabled,
+		"used_bytes", stats.UsedBytes,
+		"max_bytes", stats.MaxBytes)
+
+	return nil, output, nil
+}
@@ -0,0 +1,139 @@
+// Package docs renders complaints into human-readable documents (Markdown, HTML or text).
+package docs
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/larsartmann/complaints-mcp/internal/domain"
+	"github.com/larsartmann/complaints-mcp/internal/repo"
+	"github.com/larsartmann/complaints-mcp/internal/types"
+)
+
+//go:embed templates/*.tmpl
+var builtinTemplates embed.FS
+
+// Exporter renders complaints to documents under a project's docs directory.
+type Exporter struct {
+	config       types.DocsConfig
+	fallbackRoot string
+}
+
+// NewExporter creates an exporter for the given docs configuration.
+// fallbackRoot is used when a complaint was filed without a detectable project root.
+func NewExporter(cfg types.DocsConfig, fallbackRoot string) (*Exporter, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Fail fast if the built-in fallback is missing for this format
+	if _, err := builtinTemplate(cfg.Format); err != nil {
+		return nil, err
+	}
+
+	return &Exporter{
+		config:       cfg,
+		fallbackRoot: fallbackRoot,
+	}, nil
+}
+
+// Format returns the document format this exporter produces.
+func (e *Exporter) Format() types.DocsFormat {
+	return e.config.Format
+}
+
+// Render writes the document for a complaint to w, using the template
+// discovered for projectRoot (see loadTemplate).
+func (e *Exporter) Render(w io.Writer, complaint *domain.Complaint, projectRoot string) error {
+	tmpl, err := loadTemplate(e.config.Format, projectRoot)
+	if err != nil {
+		return err
+	}
+
+	err = tmpl.Execute(w, NewDocument(complaint, complaint.DocsPath, e.config.Format))
+	if err != nil {
+		return fmt.Errorf("failed to render complaint document: %w", err)
+	}
+
+	return nil
+}
+
+// Write writes a document prepared by Prepare to path.
+func (e *Exporter) Write(path string, content []byte) error {
+	if err := repo.WriteFileAtomic(path, content); err != nil {
+		return fmt.Errorf("failed to write complaint document: %w", err)
+	}
+
+	return nil
+}
+
+// Prepare renders a complaint document without writing it, returning its path and
+// content, so the complaint can be saved with the path before the document exists.
+// Complaints that were exported before are re-rendered in place at complaint.DocsPath.
+func (e *Exporter) Prepare(complaint *domain.Complaint, projectRoot string) (string, []byte, error) {
+	path := complaint.DocsPath
+	if path == "" {
+		var err error
+
+		path, err = e.newPath(complaint, projectRoot)
+		if err != nil {
+			return "", nil, err
+		}
+	} else if projectRoot == "" {
+		projectRoot = e.rootOf(path)
+	}
+
+	// Render against a copy so the template sees the path being written
+	rendered := *complaint
+	rendered.DocsPath = path
+
+	var buf bytes.Buffer
+	if err := e.Render(&buf, &rendered, projectRoot); err != nil {
+		return "", nil, err
+	}
+
+	return path, buf.Bytes(), nil
+}
+
+// newPath picks a fresh document path, disambiguating same-second filings in one session.
+func (e *Exporter) newPath(complaint *domain.Complaint, projectRoot string) (string, error) {
+	root := projectRoot
+	if root == "" {
+		root = e.fallbackRoot
+	}
+
+	if root == "" {
+		return "", errors.New("no project root or fallback directory for complaint documents")
+	}
+
+	dir := filepath.Join(root, e.config.Dir)
+	name := types.GenerateFilename(complaint.Timestamp, complaint.SessionID.String(), e.config.Format)
+	path := filepath.Join(dir, name)
+
+	if _, err := os.Stat(path); err == nil {
+		ext := e.config.Format.FileExtension()
+		shortID := complaint.ID.String()[:8]
+		path = filepath.Join(dir, strings.TrimSuffix(name, ext)+"-"+shortID+ext)
+	}
+
+	return path, nil
+}
+
+// rootOf recovers the project root from a previously exported document path,
+// so re-renders pick up the same project templates.
+func (e *Exporter) rootOf(docsPath string) string {
+	dir := filepath.Dir(docsPath)
+	suffix := string(filepath.Separator) + filepath.Clean(e.config.Dir)
+
+	if !strings.HasSuffix(dir, suffix) {
+		return ""
+	}
+
+	return strings.TrimSuffix(dir, suffix)
+}
@@ -0,0 +1,190 @@
+package docs
+
+import (
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/adrg/xdg"
+	delivery "github.com/larsartmann/complaints-mcp/internal/delivery/mcp"
+	"github.com/larsartmann/complaints-mcp/internal/domain"
+	"github.com/larsartmann/complaints-mcp/internal/types"
+)
+
+const (
+	createdLayout = "2006-01-02 15:04:05"
+
+	// projectTemplateDir holds per-project templates, relative to the project root.
+	projectTemplateDir = ".complaints-mcp/templates"
+	// userTemplateDir holds per-user templates, relative to the XDG config dirs.
+	userTemplateDir = "complaints-mcp/templates"
+)
+
+// Document is the data model handed to complaint templates.
+//
+// It embeds delivery.ComplaintDTO, so every field the MCP tools return is
+// addressable directly: {{.ID}}, {{.AgentName}}, {{.SessionName}},
+// {{.ProjectID}}, {{.TaskDescription}}, {{.ContextInfo}}, {{.MissingInfo}},
+// {{.ConfusedBy}}, {{.FutureWishes}}, {{.Severity}}, {{.Tags}},
+// {{.Categories}}, {{.References}}, {{.Timestamp}}, {{.Resolved}}, {{.State}}, {{.ResolvedAt}},
+// {{.ResolvedBy}}, {{.DocsPath}}, {{.Transitions}}, {{.Comments}},
+// {{.SimilarTo}}, {{.DuplicateOf}}, {{.Duplicates}}, {{.DuplicateCount}},
+// {{.Occurrences}}, {{.OccurrenceCount}} and {{.EffectivePriority}}.
+//
+// Templates may also call formatTime, which renders a time.Time or
+// *time.Time as "2006-01-02 15:04:05" and a nil pointer as "", and join,
+// which is strings.Join, e.g. {{join .Tags ", "}}. Code references are also
+// available as {{.Links}}, ready to render with {{range .Links}}.
+type Document struct {
+	delivery.ComplaintDTO
+
+	Created string     // Timestamp formatted with formatTime
+	Status  string     // resolution state, e.g. "open" or "resolved"
+	Format  string     // docs format being rendered: markdown, html or text
+	Links   []CodeLink // References, in order, as links from the rendered document
+}
+
+// CodeLink is a code reference as rendered in a document.
+type CodeLink struct {
+	Label string // e.g. "internal/docs/exporter.go:40-52 (Exporter.Export)"
+	URL   string // relative to the document, with a #L40-L52 line anchor; empty if unknown
+}
+
+// NewDocument builds the template data model for a complaint rendered to docsPath.
+func NewDocument(c *domain.Complaint, docsPath string, format types.DocsFormat) Document {
+	return Document{
+		ComplaintDTO: delivery.ToDTOWithPaths(c, "", docsPath),
+		Created:      c.Timestamp.Format(createdLayout),
+		Status:       string(c.ResolutionState),
+		Format:       format.String(),
+		Links:        codeLinks(c.References, c.ProjectRoot, docsPath),
+	}
+}
+
+// codeLinks links refs from the document at docsPath. Links are relative so
+// they work both in a checkout and when the docs are browsed on a code host;
+// without a project root or docs path only the labels are known.
+func codeLinks(refs []domain.CodeRef, projectRoot, docsPath string) []CodeLink {
+	links := make([]CodeLink, 0, len(refs))
+
+	for _, ref := range refs {
+		link := CodeLink{Label: ref.String()}
+
+		if projectRoot != "" && docsPath != "" {
+			target := filepath.Join(projectRoot, filepath.FromSlash(ref.Path))
+			if rel, err := filepath.Rel(filepath.Dir(docsPath), target); err == nil {
+				link.URL = filepath.ToSlash(rel) + lineAnchor(ref)
+			}
+		}
+
+		links = append(links, link)
+	}
+
+	return links
+}
+
+// lineAnchor is the #L10 or #L10-L20 fragment code hosts use for line ranges.
+func lineAnchor(ref domain.CodeRef) string {
+	switch {
+	case ref.StartLine == 0:
+		return ""
+	case ref.EndLine > ref.StartLine:
+		return fmt.Sprintf("#L%d-L%d", ref.StartLine, ref.EndLine)
+	default:
+		return fmt.Sprintf("#L%d", ref.StartLine)
+	}
+}
+
+// renderer is satisfied by both text/template and html/template templates.
+type renderer interface {
+	Execute(w io.Writer, data any) error
+}
+
+var templateFuncs = texttemplate.FuncMap{
+	"formatTime": formatTime,
+	"join":       strings.Join,
+}
+
+func formatTime(t any) string {
+	switch v := t.(type) {
+	case time.Time:
+		return v.Format(createdLayout)
+	case *time.Time:
+		if v == nil {
+			return ""
+		}
+
+		return v.Format(createdLayout)
+	default:
+		return fmt.Sprint(t)
+	}
+}
+
+// templateName is the file name looked up for a format, e.g. complaint.md.tmpl.
+func templateName(format types.DocsFormat) string {
+	return "complaint" + format.FileExtension() + ".tmpl"
+}
+
+// loadTemplate resolves the template for a format, preferring the project's
+// .complaints-mcp/templates, then the XDG config dirs, then the built-in one.
+func loadTemplate(format types.DocsFormat, projectRoot string) (renderer, error) {
+	name := templateName(format)
+
+	if projectRoot != "" {
+		path := filepath.Join(projectRoot, projectTemplateDir, name)
+
+		tmpl, err := parseTemplateFile(format, path)
+		if !errors.Is(err, fs.ErrNotExist) {
+			return tmpl, err
+		}
+	}
+
+	if path, err := xdg.SearchConfigFile(filepath.Join(userTemplateDir, name)); err == nil {
+		return parseTemplateFile(format, path)
+	}
+
+	return builtinTemplate(format)
+}
+
+// parseTemplateFile parses a user-supplied template; a missing file reports fs.ErrNotExist.
+func parseTemplateFile(format types.DocsFormat, path string) (renderer, error) {
+	source, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl, err := parseTemplate(format, path, string(source))
+	if err != nil {
+		return nil, fmt.Errorf("invalid complaint template %s: %w", path, err)
+	}
+
+	return tmpl, nil
+}
+
+// builtinTemplate parses the embedded template for a format.
+func builtinTemplate(format types.DocsFormat) (renderer, error) {
+	name := "templates/" + templateName(format)
+
+	source, err := builtinTemplates.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("no built-in template for docs format %s: %w", format, err)
+	}
+
+	return parseTemplate(format, name, string(source))
+}
+
+// parseTemplate uses html/template for HTML so complaint text is escaped.
+func parseTemplate(format types.DocsFormat, name, source string) (renderer, error) {
+	if format == types.DocsFormatHTML {
+		return htmltemplate.New(name).Funcs(templateFuncs).Parse(source)
+	}
+
+	return texttemplate.New(name).Funcs(templateFuncs).Parse(source)
+}
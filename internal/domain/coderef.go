@@ -0,0 +1,180 @@
+package domain
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const (
+	// maxReferences bounds the code references on a complaint.
+	maxReferences = 20
+	// maxSymbolLength bounds a referenced symbol name, in bytes.
+	maxSymbolLength = 200
+)
+
+// commitPattern matches an abbreviated or full hex commit SHA.
+var commitPattern = regexp.MustCompile(`^[0-9a-f]{7,40}$`)
+
+// CodeRef points at the code a complaint is about. Path is slash-separated
+// and relative to the repository root; StartLine and EndLine are 1-based and
+// inclusive, and zero when the whole file is meant.
+type CodeRef struct {
+	Path      string `json:"path"`
+	StartLine int    `json:"start_line,omitempty"`
+	EndLine   int    `json:"end_line,omitempty"`
+	Symbol    string `json:"symbol,omitempty"` // function, type or other identifier, e.g. "Exporter.Export"
+	Commit    string `json:"commit,omitempty"` // commit the lines refer to
+}
+
+// Validate checks the reference's format; whether the path exists is up to
+// the caller, which knows the repository root.
+func (r CodeRef) Validate() error {
+	if r.Path == "" {
+		return ValidationError{Field: "path", Message: "path cannot be empty"}
+	}
+
+	if strings.Contains(r.Path, `\`) || path.IsAbs(r.Path) || path.Clean(r.Path) != r.Path ||
+		r.Path == ".." || strings.HasPrefix(r.Path, "../") {
+		return ValidationError{
+			Field:   "path",
+			Message: "path must be clean, slash-separated and inside the repository: " + r.Path,
+		}
+	}
+
+	if r.StartLine < 0 || r.EndLine < 0 {
+		return ValidationError{Field: "start_line", Message: "line numbers cannot be negative"}
+	}
+
+	if r.EndLine != 0 && r.StartLine == 0 {
+		return ValidationError{Field: "end_line", Message: "end_line requires start_line"}
+	}
+
+	if r.EndLine != 0 && r.EndLine < r.StartLine {
+		return ValidationError{Field: "end_line", Message: "end_line cannot be before start_line"}
+	}
+
+	if len(r.Symbol) > maxSymbolLength {
+		return ValidationError{
+			Field:   "symbol",
+			Message: fmt.Sprintf("symbol is too long (max %d bytes)", maxSymbolLength),
+		}
+	}
+
+	if r.Commit != "" && !commitPattern.MatchString(r.Commit) {
+		return ValidationError{Field: "commit", Message: "invalid commit SHA: " + r.Commit}
+	}
+
+	return nil
+}
+
+// LastLine returns the final line of the referenced range, or 0 for a whole file.
+func (r CodeRef) LastLine() int {
+	if r.EndLine != 0 {
+		return r.EndLine
+	}
+
+	return r.StartLine
+}
+
+// String formats the reference as path:start-end (symbol) @commit, leaving
+// out the parts that are not set.
+func (r CodeRef) String() string {
+	var b strings.Builder
+
+	b.WriteString(r.Path)
+
+	if r.StartLine != 0 {
+		b.WriteString(":" + strconv.Itoa(r.StartLine))
+
+		if r.EndLine > r.StartLine {
+			b.WriteString("-" + strconv.Itoa(r.EndLine))
+		}
+	}
+
+	if r.Symbol != "" {
+		b.WriteString(" (" + r.Symbol + ")")
+	}
+
+	if r.Commit != "" {
+		b.WriteString(" @" + r.Commit[:min(len(r.Commit), 12)])
+	}
+
+	return b.String()
+}
+
+// MentionedPaths lists the repository paths a complaint is about: those of
+// its references, then path-like words in ContextInfo and MissingInfo such
+// as "docs/setup.md" or "main.go:42", without repeats. The words are only
+// candidates; whether such a file exists is up to the caller.
+func (c *Complaint) MentionedPaths() []string {
+	var paths []string
+
+	add := func(candidate string) {
+		if !slices.Contains(paths, candidate) {
+			paths = append(paths, candidate)
+		}
+	}
+
+	for _, ref := range c.References {
+		add(ref.Path)
+	}
+
+	for _, text := range []string{c.ContextInfo, c.MissingInfo} {
+		for _, word := range strings.FieldsFunc(text, isPathSeparator) {
+			if candidate, ok := pathCandidate(word); ok {
+				add(candidate)
+			}
+		}
+	}
+
+	return paths
+}
+
+// isPathSeparator splits prose into words, treating quotes and brackets
+// around a path as separators.
+func isPathSeparator(r rune) bool {
+	return unicode.IsSpace(r) || strings.ContainsRune("`'\"()[]{}<>,;", r)
+}
+
+// pathCandidate turns a word such as "./docs/setup.md:42." into a
+// repository path, if it looks like one.
+func pathCandidate(word string) (string, bool) {
+	if strings.Contains(word, "://") {
+		return "", false
+	}
+
+	word, _, _ = strings.Cut(word, ":")
+	word = strings.TrimPrefix(strings.TrimRight(word, ".!?"), "./")
+
+	if !strings.ContainsAny(word, "./") || (CodeRef{Path: word}).Validate() != nil {
+		return "", false
+	}
+
+	return word, true
+}
+
+// validateReferences checks every reference and rejects repeats.
+func validateReferences(refs []CodeRef) error {
+	if len(refs) > maxReferences {
+		return fmt.Errorf("too many references: %d (max %d)", len(refs), maxReferences)
+	}
+
+	for i, ref := range refs {
+		if err := ref.Validate(); err != nil {
+			return fmt.Errorf("invalid reference %d: %w", i+1, err)
+		}
+
+		for _, earlier := range refs[:i] {
+			if earlier == ref {
+				return fmt.Errorf("duplicate reference: %s", ref)
+			}
+		}
+	}
+
+	return nil
+}
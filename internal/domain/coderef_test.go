@@ -0,0 +1,109 @@
+package domain
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestCodeRef_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		ref     CodeRef
+		wantErr bool
+	}{
+		{
+			name: "whole file",
+			ref:  CodeRef{Path: "internal/docs/exporter.go"},
+		},
+		{
+			name: "line range with symbol and commit",
+			ref: CodeRef{
+				Path: "internal/docs/exporter.go", StartLine: 40, EndLine: 52,
+				Symbol: "Exporter.Export", Commit: "a91fe57",
+			},
+		},
+		{
+			name:    "empty path",
+			ref:     CodeRef{StartLine: 1},
+			wantErr: true,
+		},
+		{
+			name:    "absolute path",
+			ref:     CodeRef{Path: "/etc/passwd"},
+			wantErr: true,
+		},
+		{
+			name:    "path escaping the repository",
+			ref:     CodeRef{Path: "../other/main.go"},
+			wantErr: true,
+		},
+		{
+			name:    "unclean path",
+			ref:     CodeRef{Path: "internal/../go.mod"},
+			wantErr: true,
+		},
+		{
+			name:    "end line without start line",
+			ref:     CodeRef{Path: "go.mod", EndLine: 3},
+			wantErr: true,
+		},
+		{
+			name:    "end line before start line",
+			ref:     CodeRef{Path: "go.mod", StartLine: 5, EndLine: 3},
+			wantErr: true,
+		},
+		{
+			name:    "short commit",
+			ref:     CodeRef{Path: "go.mod", Commit: "a91f"},
+			wantErr: true,
+		},
+		{
+			name:    "non-hex commit",
+			ref:     CodeRef{Path: "go.mod", Commit: "main"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.ref.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCodeRef_String(t *testing.T) {
+	tests := []struct {
+		ref      CodeRef
+		expected string
+	}{
+		{CodeRef{Path: "go.mod"}, "go.mod"},
+		{CodeRef{Path: "go.mod", StartLine: 3}, "go.mod:3"},
+		{CodeRef{Path: "go.mod", StartLine: 3, EndLine: 3}, "go.mod:3"},
+		{
+			CodeRef{Path: "main.go", StartLine: 3, EndLine: 9, Symbol: "main", Commit: "0123456789abcdef0123"},
+			"main.go:3-9 (main) @0123456789ab",
+		},
+	}
+
+	for _, tt := range tests {
+		if got := tt.ref.String(); got != tt.expected {
+			t.Errorf("String() = %q, want %q", got, tt.expected)
+		}
+	}
+}
+
+func TestComplaint_MentionedPaths(t *testing.T) {
+	complaint := &Complaint{
+		References:  []CodeRef{{Path: "internal/docs/exporter.go", StartLine: 3}},
+		ContextInfo: "Followed `./docs/setup.md` and main.go:42, see https://example.com/a.md.",
+		MissingInfo: "Nothing in docs/setup.md or internal/docs/exporter.go about ../secrets.env",
+	}
+
+	expected := []string{"internal/docs/exporter.go", "docs/setup.md", "main.go"}
+	if got := complaint.MentionedPaths(); !slices.Equal(got, expected) {
+		t.Errorf("MentionedPaths() = %v, want %v", got, expected)
+	}
+}
@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// maxCommentLength bounds a comment body, in bytes.
+const maxCommentLength = 5000
+
+// CommentAuthorType tells comments left by agents apart from those left by people.
+type CommentAuthorType string
+
+const (
+	CommentAuthorAgent CommentAuthorType = "agent"
+	CommentAuthorHuman CommentAuthorType = "human"
+)
+
+// ParseCommentAuthorType safely converts string to CommentAuthorType with validation.
+// An empty string means an agent, as agents are the usual callers.
+func ParseCommentAuthorType(s string) (CommentAuthorType, error) {
+	switch CommentAuthorType(s) {
+	case "", CommentAuthorAgent:
+		return CommentAuthorAgent, nil
+	case CommentAuthorHuman:
+		return CommentAuthorHuman, nil
+	default:
+		return "", ValidationError{Field: "author_type", Message: "invalid author type: " + s}
+	}
+}
+
+// Comment is one message in the discussion thread of a complaint.
+type Comment struct {
+	ID         string            `json:"id"`
+	Author     string            `json:"author"`
+	AuthorType CommentAuthorType `json:"author_type"`
+	Body       string            `json:"body"`
+	CreatedAt  time.Time         `json:"created_at"`
+}
+
+// NewComment creates a comment by author, validating its body.
+func NewComment(author string, authorType CommentAuthorType, body string) (Comment, error) {
+	if author == "" {
+		return Comment{}, errors.New("comment author cannot be empty")
+	}
+
+	if strings.TrimSpace(body) == "" {
+		return Comment{}, errors.New("comment body cannot be empty")
+	}
+
+	if len(body) > maxCommentLength {
+		return Comment{}, fmt.Errorf("comment body exceeds %d bytes", maxCommentLength)
+	}
+
+	id, err := uuid.NewV4()
+	if err != nil {
+		return Comment{}, fmt.Errorf("failed to generate comment ID: %w", err)
+	}
+
+	return Comment{
+		ID:         id.String(),
+		Author:     author,
+		AuthorType: authorType,
+		Body:       body,
+		CreatedAt:  time.Now(),
+	}, nil
+}
+
+// AddComment appends comment to the complaint's thread.
+func (c *Complaint) AddComment(comment Comment) {
+	c.Comments = append(c.Comments, comment)
+}
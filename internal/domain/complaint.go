@@ -83,8 +83,13 @@ func IsValidComplaintID(s string) bool {
 type ResolutionState string
 
 const (
-	ResolutionStateOpen     ResolutionState = "open"
-	ResolutionStateResolved ResolutionState = "resolved"
+	ResolutionStateOpen         ResolutionState = "open"
+	ResolutionStateAcknowledged ResolutionState = "acknowledged"
+	ResolutionStateInProgress   ResolutionState = "in_progress"
+	ResolutionStateResolved     ResolutionState = "resolved"
+	ResolutionStateWontFix      ResolutionState = "wont_fix"
+	ResolutionStateDuplicate    ResolutionState = "duplicate"
+	ResolutionStateReopened     ResolutionState = "reopened"
 )
 
 // IsResolved returns true if the complaint is resolved.
@@ -114,10 +119,22 @@ type Complaint struct {
 	ConfusedBy      string          `json:"confused_by"`
 	FutureWishes    string          `json:"future_wishes"`
 	Severity        Severity        `json:"severity"`
+	Tags            []string        `json:"tags,omitempty"`       // free-form, normalized labels
+	Categories      []string        `json:"categories,omitempty"` // from the configured category vocabulary
+	References      []CodeRef       `json:"references,omitempty"` // code the complaint is about
 	Timestamp       time.Time       `json:"timestamp"`
 	ResolutionState ResolutionState `json:"resolution_state"`
 	ResolvedAt      *time.Time      `json:"resolved_at,omitempty"`
 	ResolvedBy      string          `json:"resolved_by,omitempty"`
+	DocsPath        string          `json:"docs_path,omitempty"`    // rendered human-readable document, if exported
+	ProjectRoot     string          `json:"project_root,omitempty"` // detected repository root the complaint was filed from
+	Version         uint64          `json:"version"`                // incremented by every successful update
+	History         []Event         `json:"history,omitempty"`      // every recorded change, oldest first
+	Comments        []Comment       `json:"comments,omitempty"`     // discussion thread, oldest first
+	SimilarTo       []Similar       `json:"similar_to,omitempty"`   // likely duplicates found when it was filed
+	DuplicateOf     ComplaintID     `json:"duplicate_of,omitzero"`  // set when closed as a duplicate
+	Duplicates      []ComplaintID   `json:"duplicates,omitempty"`   // complaints closed as duplicates of this one
+	Occurrences     []Occurrence    `json:"occurrences,omitempty"`  // "me too" confirmations, oldest first
 }
 
 // Validate checks if all fields are valid.
@@ -157,6 +174,27 @@ func (c *Complaint) Validate() error {
 		return errors.New("task description is required")
 	}
 
+	if err := validateLabels("tags", c.Tags); err != nil {
+		return err
+	}
+
+	if err := validateLabels("categories", c.Categories); err != nil {
+		return err
+	}
+
+	if err := validateReferences(c.References); err != nil {
+		return err
+	}
+
+	if c.DuplicateOf == c.ID {
+		return errors.New("a complaint cannot be a duplicate of itself")
+	}
+
+	// Complaints stored before the lifecycle existed may have no state
+	if c.ResolutionState != "" && !c.ResolutionState.IsValid() {
+		return fmt.Errorf("invalid resolution state: %s", c.ResolutionState)
+	}
+
 	return nil
 }
 
@@ -171,26 +209,65 @@ func (c *Complaint) Resolve(resolvedBy string) error {
 		return errors.New("resolver name cannot be empty")
 	}
 
-	// Idempotent: if already resolved, just update resolvedBy if different
+	// Idempotent: resolving again keeps the original resolver on record
 	if c.ResolutionState.IsResolved() {
-		if c.ResolvedBy != resolvedBy {
-			c.ResolvedBy = resolvedBy
-
-			return nil
-		}
-
-		return nil // Already resolved with same resolver
+		return nil
 	}
 
-	now := time.Now()
-	c.ResolvedAt = &now
-	c.ResolvedBy = resolvedBy
-	c.ResolutionState = ResolutionStateResolved
-
-	return nil
+	return c.Transition(ResolutionStateResolved, resolvedBy, "")
 }
 
 // IsResolved returns true if the complaint is resolved.
 func (c *Complaint) IsResolved() bool {
 	return c.ResolutionState.IsResolved()
 }
+
+// IsClosed returns true if nobody is expected to act on the complaint any more.
+func (c *Complaint) IsClosed() bool {
+	return c.ResolutionState.IsClosed()
+}
+
+// Clone returns a copy of the complaint that can be modified without
+// affecting the original, e.g. one shared by a cache.
+func (c *Complaint) Clone() *Complaint {
+	clone := *c
+
+	if c.ResolvedAt != nil {
+		resolvedAt := *c.ResolvedAt
+		clone.ResolvedAt = &resolvedAt
+	}
+
+	if c.Tags != nil {
+		clone.Tags = append([]string(nil), c.Tags...)
+	}
+
+	if c.Categories != nil {
+		clone.Categories = append([]string(nil), c.Categories...)
+	}
+
+	if c.References != nil {
+		clone.References = append([]CodeRef(nil), c.References...)
+	}
+
+	if c.History != nil {
+		clone.History = append([]Event(nil), c.History...)
+	}
+
+	if c.Comments != nil {
+		clone.Comments = append([]Comment(nil), c.Comments...)
+	}
+
+	if c.SimilarTo != nil {
+		clone.SimilarTo = append([]Similar(nil), c.SimilarTo...)
+	}
+
+	if c.Duplicates != nil {
+		clone.Duplicates = append([]ComplaintID(nil), c.Duplicates...)
+	}
+
+	if c.Occurrences != nil {
+		clone.Occurrences = append([]Occurrence(nil), c.Occurrences...)
+	}
+
+	return &clone
+}
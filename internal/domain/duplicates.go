@@ -0,0 +1,102 @@
+package domain
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// LikelyDuplicateScore is the Similarity from which an open complaint in
+// the same project is reported as a likely duplicate of a new one.
+const LikelyDuplicateScore = 0.5
+
+// stopWords are left out of shingles so that "the README is missing" and
+// "README missing" read the same.
+var stopWords = []string{
+	"a", "an", "and", "are", "be", "for", "in", "is", "it", "of", "on", "or", "the", "this", "that", "to", "with",
+}
+
+// Similar is another complaint and how similar it is to this one.
+type Similar struct {
+	ID    ComplaintID `json:"id"`
+	Score float64     `json:"score"` // Similarity, from 0 to 1
+}
+
+// Similarity scores how alike two complaints' task description, missing
+// information and confusion are, from 0 (no words in common) to 1 (the same
+// words in the same order). It is the Jaccard index of their word shingles.
+func Similarity(a, b *Complaint) float64 {
+	left, right := shingles(a), shingles(b)
+	if len(left) == 0 || len(right) == 0 {
+		return 0
+	}
+
+	shared := 0
+
+	for shingle := range left {
+		if _, ok := right[shingle]; ok {
+			shared++
+		}
+	}
+
+	return float64(shared) / float64(len(left)+len(right)-shared)
+}
+
+// shingles returns the normalized words and word pairs of the text that
+// describes a complaint's problem.
+func shingles(c *Complaint) map[string]struct{} {
+	set := make(map[string]struct{})
+
+	for _, text := range []string{c.TaskDescription, c.MissingInfo, c.ConfusedBy} {
+		words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+		words = slices.DeleteFunc(words, func(word string) bool {
+			return slices.Contains(stopWords, word)
+		})
+
+		for i, word := range words {
+			set[word] = struct{}{}
+
+			if i > 0 {
+				set[words[i-1]+" "+word] = struct{}{}
+			}
+		}
+	}
+
+	return set
+}
+
+// MarkDuplicateOf closes the complaint as a duplicate of canonical, which
+// must be another complaint in the same project. Marking a complaint that
+// is already a duplicate of canonical again changes nothing.
+func (c *Complaint) MarkDuplicateOf(canonical *Complaint, actor, reason string) error {
+	if canonical.ID == c.ID {
+		return errors.New("a complaint cannot be a duplicate of itself")
+	}
+
+	if canonical.ProjectID != c.ProjectID {
+		return errors.New("duplicates must belong to the same project")
+	}
+
+	if c.DuplicateOf == canonical.ID && c.ResolutionState == ResolutionStateDuplicate {
+		return nil
+	}
+
+	if err := c.Transition(ResolutionStateDuplicate, actor, reason); err != nil {
+		return err
+	}
+
+	c.DuplicateOf = canonical.ID
+
+	return nil
+}
+
+// LinkDuplicate records that the complaint with id was filed about the same
+// problem as this one.
+func (c *Complaint) LinkDuplicate(id ComplaintID) {
+	if !slices.Contains(c.Duplicates, id) {
+		c.Duplicates = append(c.Duplicates, id)
+	}
+}
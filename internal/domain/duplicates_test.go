@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"testing"
+)
+
+func TestSimilarity(t *testing.T) {
+	readme := &Complaint{
+		TaskDescription: "Setting up the project",
+		MissingInfo:     "The README is missing an installation section",
+	}
+
+	tests := []struct {
+		name string
+		b    *Complaint
+		min  float64
+		max  float64
+	}{
+		{
+			name: "same words, different filler",
+			b: &Complaint{
+				TaskDescription: "Setting up project",
+				MissingInfo:     "README missing the installation section!",
+			},
+			min: 1,
+			max: 1,
+		},
+		{
+			name: "same problem, extra detail",
+			b: &Complaint{
+				TaskDescription: "Setting up the project",
+				MissingInfo:     "README has no installation section",
+			},
+			min: LikelyDuplicateScore,
+			max: 1,
+		},
+		{
+			name: "different problem",
+			b: &Complaint{
+				TaskDescription: "Running the test suite",
+				ConfusedBy:      "Flaky database fixtures",
+			},
+			min: 0,
+			max: 0,
+		},
+		{
+			name: "no text",
+			b:    &Complaint{},
+			min:  0,
+			max:  0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score := Similarity(readme, tt.b)
+			if score < tt.min || score > tt.max {
+				t.Errorf("Similarity() = %v, want between %v and %v", score, tt.min, tt.max)
+			}
+
+			if reverse := Similarity(tt.b, readme); reverse != score {
+				t.Errorf("Similarity() is not symmetric: %v != %v", score, reverse)
+			}
+		})
+	}
+}
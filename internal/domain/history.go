@@ -0,0 +1,117 @@
+package domain
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Event actions recorded in a complaint's history.
+const (
+	EventFiled           = "filed"
+	EventResolved        = "resolved"
+	EventStateChanged    = "state_changed"
+	EventCommented       = "commented"
+	EventTagged          = "tagged"
+	EventCategorized     = "categorized"
+	EventMarkedDuplicate = "marked_duplicate"
+	EventDuplicateLinked = "duplicate_linked"
+	EventConfirmed       = "confirmed"
+)
+
+// resolutionStateField is the audited field lifecycle moves change.
+const resolutionStateField = "resolution_state"
+
+// Event records one change to a complaint. Events are only ever appended to
+// a complaint's history, never edited or removed.
+type Event struct {
+	At      time.Time     `json:"at"`
+	Actor   string        `json:"actor"`
+	Tool    string        `json:"tool,omitempty"` // MCP tool or CLI command that made the change
+	Action  string        `json:"action"`
+	Reason  string        `json:"reason,omitempty"`
+	Changes []FieldChange `json:"changes,omitempty"`
+}
+
+// FieldChange is the old and new value of one complaint field.
+type FieldChange struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+// auditedFields lists the complaint fields whose changes are recorded, by
+// their JSON names. Bookkeeping such as the version, docs path and the
+// history itself is left out.
+var auditedFields = []struct {
+	name  string
+	value func(*Complaint) string
+}{
+	{"agent_id", func(c *Complaint) string { return c.AgentID.String() }},
+	{"session_id", func(c *Complaint) string { return c.SessionID.String() }},
+	{"project_id", func(c *Complaint) string { return c.ProjectID.String() }},
+	{"task_description", func(c *Complaint) string { return c.TaskDescription }},
+	{"context_info", func(c *Complaint) string { return c.ContextInfo }},
+	{"missing_info", func(c *Complaint) string { return c.MissingInfo }},
+	{"confused_by", func(c *Complaint) string { return c.ConfusedBy }},
+	{"future_wishes", func(c *Complaint) string { return c.FutureWishes }},
+	{"severity", func(c *Complaint) string { return string(c.Severity) }},
+	{"tags", func(c *Complaint) string { return strings.Join(c.Tags, ",") }},
+	{"categories", func(c *Complaint) string { return strings.Join(c.Categories, ",") }},
+	{"references", func(c *Complaint) string { return formatReferences(c.References) }},
+	{resolutionStateField, func(c *Complaint) string { return string(c.ResolutionState) }},
+	{"resolved_by", func(c *Complaint) string { return c.ResolvedBy }},
+	{"resolved_at", func(c *Complaint) string { return formatOptionalTime(c.ResolvedAt) }},
+	{"comments", func(c *Complaint) string { return strconv.Itoa(len(c.Comments)) }},
+	{"duplicate_of", func(c *Complaint) string { return c.DuplicateOf.String() }},
+	{"duplicates", func(c *Complaint) string { return strconv.Itoa(len(c.Duplicates)) }},
+	{"occurrences", func(c *Complaint) string { return strconv.Itoa(len(c.Occurrences)) }},
+}
+
+// Diff lists the audited fields that differ between before and after.
+func Diff(before, after *Complaint) []FieldChange {
+	var changes []FieldChange
+
+	for _, field := range auditedFields {
+		old, updated := field.value(before), field.value(after)
+		if old != updated {
+			changes = append(changes, FieldChange{Field: field.name, Old: old, New: updated})
+		}
+	}
+
+	return changes
+}
+
+// Record appends event to the history with the changes made since before,
+// and reports whether anything changed. Nothing is recorded for no-op changes.
+func (c *Complaint) Record(before *Complaint, event Event) bool {
+	event.Changes = Diff(before, c)
+	if len(event.Changes) == 0 {
+		return false
+	}
+
+	if event.At.IsZero() {
+		event.At = time.Now()
+	}
+
+	c.History = append(c.History, event)
+
+	return true
+}
+
+func formatReferences(refs []CodeRef) string {
+	formatted := make([]string, len(refs))
+	for i, ref := range refs {
+		formatted[i] = ref.String()
+	}
+
+	return strings.Join(formatted, ", ")
+}
+
+func formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+
+	return t.UTC().Format(time.RFC3339Nano)
+}
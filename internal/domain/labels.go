@@ -0,0 +1,129 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+const (
+	// maxLabels bounds the tags, and separately the categories, on a complaint.
+	maxLabels = 20
+	// maxLabelLength bounds a single tag or category, in bytes.
+	maxLabelLength = 50
+)
+
+// labelPattern is the normalized form of tags and categories: lowercase
+// letters, digits, dots, dashes and underscores, starting with a letter or digit.
+var labelPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+
+// DefaultCategories is the category vocabulary used when none is configured.
+var DefaultCategories = []string{"docs", "build", "api", "tests", "environment"}
+
+// MatchMode decides whether a label filter needs any or all of its labels.
+type MatchMode string
+
+const (
+	MatchAny MatchMode = "any"
+	MatchAll MatchMode = "all"
+)
+
+// ParseMatchMode safely converts string to MatchMode with validation.
+// An empty string means MatchAny.
+func ParseMatchMode(s string) (MatchMode, error) {
+	switch MatchMode(s) {
+	case "", MatchAny:
+		return MatchAny, nil
+	case MatchAll:
+		return MatchAll, nil
+	default:
+		return "", ValidationError{Field: "match", Message: "invalid match mode: " + s}
+	}
+}
+
+// NormalizeLabels trims and lowercases labels, dropping empty and repeated
+// ones while keeping the first occurrence's position.
+func NormalizeLabels(labels []string) []string {
+	var normalized []string
+
+	for _, label := range labels {
+		label = strings.ToLower(strings.TrimSpace(label))
+		if label == "" || slices.Contains(normalized, label) {
+			continue
+		}
+
+		normalized = append(normalized, label)
+	}
+
+	return normalized
+}
+
+// validateLabels checks that labels are normalized, unique and within bounds.
+func validateLabels(field string, labels []string) error {
+	if len(labels) > maxLabels {
+		return fmt.Errorf("too many %s: %d (max %d)", field, len(labels), maxLabels)
+	}
+
+	for i, label := range labels {
+		if len(label) > maxLabelLength || !labelPattern.MatchString(label) {
+			return fmt.Errorf("invalid %s entry: %q", field, label)
+		}
+
+		if slices.Contains(labels[:i], label) {
+			return fmt.Errorf("duplicate %s entry: %q", field, label)
+		}
+	}
+
+	return nil
+}
+
+// ValidateCategories checks the complaint's categories against vocabulary.
+// It is separate from Validate so that complaints stay readable and
+// updatable after a category is dropped from the configured vocabulary.
+func (c *Complaint) ValidateCategories(vocabulary []string) error {
+	for _, category := range c.Categories {
+		if !slices.Contains(vocabulary, category) {
+			return fmt.Errorf("unknown category %q (allowed: %v)", category, vocabulary)
+		}
+	}
+
+	return nil
+}
+
+// LabelFilter selects complaints by their tags and categories. Empty lists
+// match every complaint; Mode applies to tags and categories alike.
+type LabelFilter struct {
+	Tags       []string
+	Categories []string
+	Mode       MatchMode
+}
+
+// IsEmpty returns true if the filter matches every complaint.
+func (f LabelFilter) IsEmpty() bool {
+	return len(f.Tags) == 0 && len(f.Categories) == 0
+}
+
+// Matches returns true if the complaint carries the filter's tags and categories.
+func (f LabelFilter) Matches(c *Complaint) bool {
+	return matchLabels(c.Tags, f.Tags, f.Mode) && matchLabels(c.Categories, f.Categories, f.Mode)
+}
+
+func matchLabels(have, want []string, mode MatchMode) bool {
+	if len(want) == 0 {
+		return true
+	}
+
+	for _, label := range want {
+		found := slices.Contains(have, label)
+		if found && mode != MatchAll {
+			return true
+		}
+
+		if !found && mode == MatchAll {
+			return false
+		}
+	}
+
+	return mode == MatchAll
+}
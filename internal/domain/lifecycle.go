@@ -0,0 +1,163 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrInvalidTransition is returned when a complaint cannot move to the requested state.
+var ErrInvalidTransition = errors.New("invalid state transition")
+
+// StateTransition is one move through the complaint lifecycle, as recorded
+// in the complaint's history.
+type StateTransition struct {
+	From   ResolutionState `json:"from"`
+	To     ResolutionState `json:"to"`
+	Actor  string          `json:"actor"`
+	Reason string          `json:"reason,omitempty"`
+	At     time.Time       `json:"at"`
+}
+
+// transitions lists the states each state may move to. Closed states can
+// only be reopened.
+var transitions = map[ResolutionState][]ResolutionState{
+	ResolutionStateOpen: {
+		ResolutionStateAcknowledged, ResolutionStateInProgress,
+		ResolutionStateResolved, ResolutionStateWontFix, ResolutionStateDuplicate,
+	},
+	ResolutionStateReopened: {
+		ResolutionStateAcknowledged, ResolutionStateInProgress,
+		ResolutionStateResolved, ResolutionStateWontFix, ResolutionStateDuplicate,
+	},
+	ResolutionStateAcknowledged: {
+		ResolutionStateInProgress,
+		ResolutionStateResolved, ResolutionStateWontFix, ResolutionStateDuplicate,
+	},
+	ResolutionStateInProgress: {
+		ResolutionStateAcknowledged,
+		ResolutionStateResolved, ResolutionStateWontFix, ResolutionStateDuplicate,
+	},
+	ResolutionStateResolved:  {ResolutionStateReopened},
+	ResolutionStateWontFix:   {ResolutionStateReopened},
+	ResolutionStateDuplicate: {ResolutionStateReopened},
+}
+
+// ParseResolutionState safely converts string to domain ResolutionState with validation.
+func ParseResolutionState(s string) (ResolutionState, error) {
+	state := ResolutionState(s)
+	if !state.IsValid() {
+		return "", ValidationError{Field: "state", Message: "invalid state: " + s}
+	}
+
+	return state, nil
+}
+
+// IsValid returns true if r is a known lifecycle state.
+func (r ResolutionState) IsValid() bool {
+	_, ok := transitions[r]
+
+	return ok
+}
+
+// IsClosed returns true for states nobody is expected to act on any more.
+func (r ResolutionState) IsClosed() bool {
+	switch r {
+	case ResolutionStateResolved, ResolutionStateWontFix, ResolutionStateDuplicate:
+		return true
+	default:
+		return false
+	}
+}
+
+// CanTransitionTo returns true if a complaint in state r may move to state to.
+// Complaints stored before the lifecycle existed have no state and count as open.
+func (r ResolutionState) CanTransitionTo(to ResolutionState) bool {
+	if r == "" {
+		r = ResolutionStateOpen
+	}
+
+	for _, next := range transitions[r] {
+		if next == to {
+			return true
+		}
+	}
+
+	return false
+}
+
+// needsReason returns true for moves that should explain themselves to whoever
+// reads the complaint next.
+func (r ResolutionState) needsReason() bool {
+	switch r {
+	case ResolutionStateWontFix, ResolutionStateDuplicate, ResolutionStateReopened:
+		return true
+	default:
+		return false
+	}
+}
+
+// Transition moves the complaint to state to on behalf of actor. Closing sets
+// ResolvedAt and ResolvedBy; reopening clears them. The move is recorded by
+// Record, with the event that explains it.
+func (c *Complaint) Transition(to ResolutionState, actor, reason string) error {
+	if actor == "" {
+		return errors.New("actor cannot be empty")
+	}
+
+	if to.needsReason() && reason == "" {
+		return fmt.Errorf("a reason is required to move a complaint to %s", to)
+	}
+
+	from := stateOrOpen(c.ResolutionState)
+	if !from.CanTransitionTo(to) {
+		return fmt.Errorf("%w: %s -> %s", ErrInvalidTransition, from, to)
+	}
+
+	now := time.Now()
+
+	if to.IsClosed() {
+		c.ResolvedAt = &now
+		c.ResolvedBy = actor
+	} else {
+		c.ResolvedAt = nil
+		c.ResolvedBy = ""
+	}
+
+	c.ResolutionState = to
+
+	return nil
+}
+
+// Transitions lists the recorded lifecycle moves, oldest first: the history
+// events that changed the resolution state.
+func (c *Complaint) Transitions() []StateTransition {
+	var moves []StateTransition
+
+	for _, event := range c.History {
+		for _, change := range event.Changes {
+			if change.Field != resolutionStateField {
+				continue
+			}
+
+			moves = append(moves, StateTransition{
+				From:   stateOrOpen(ResolutionState(change.Old)),
+				To:     stateOrOpen(ResolutionState(change.New)),
+				Actor:  event.Actor,
+				Reason: event.Reason,
+				At:     event.At,
+			})
+		}
+	}
+
+	return moves
+}
+
+// stateOrOpen counts complaints stored before the lifecycle existed as open.
+func stateOrOpen(state ResolutionState) ResolutionState {
+	if state == "" {
+		return ResolutionStateOpen
+	}
+
+	return state
+}
@@ -0,0 +1,175 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestResolutionState_CanTransitionTo(t *testing.T) {
+	tests := []struct {
+		name     string
+		from     ResolutionState
+		to       ResolutionState
+		expected bool
+	}{
+		{
+			name:     "open to acknowledged",
+			from:     ResolutionStateOpen,
+			to:       ResolutionStateAcknowledged,
+			expected: true,
+		},
+		{
+			name:     "acknowledged to in progress",
+			from:     ResolutionStateAcknowledged,
+			to:       ResolutionStateInProgress,
+			expected: true,
+		},
+		{
+			name:     "in progress to resolved",
+			from:     ResolutionStateInProgress,
+			to:       ResolutionStateResolved,
+			expected: true,
+		},
+		{
+			name:     "open to wont fix",
+			from:     ResolutionStateOpen,
+			to:       ResolutionStateWontFix,
+			expected: true,
+		},
+		{
+			name:     "resolved to reopened",
+			from:     ResolutionStateResolved,
+			to:       ResolutionStateReopened,
+			expected: true,
+		},
+		{
+			name:     "reopened to in progress",
+			from:     ResolutionStateReopened,
+			to:       ResolutionStateInProgress,
+			expected: true,
+		},
+		{
+			name:     "legacy empty state counts as open",
+			from:     "",
+			to:       ResolutionStateAcknowledged,
+			expected: true,
+		},
+		{
+			name:     "open cannot be reopened",
+			from:     ResolutionStateOpen,
+			to:       ResolutionStateReopened,
+			expected: false,
+		},
+		{
+			name:     "acknowledged cannot go back to open",
+			from:     ResolutionStateAcknowledged,
+			to:       ResolutionStateOpen,
+			expected: false,
+		},
+		{
+			name:     "resolved cannot become wont fix",
+			from:     ResolutionStateResolved,
+			to:       ResolutionStateWontFix,
+			expected: false,
+		},
+		{
+			name:     "duplicate cannot be acknowledged",
+			from:     ResolutionStateDuplicate,
+			to:       ResolutionStateAcknowledged,
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.from.CanTransitionTo(tt.to); got != tt.expected {
+				t.Errorf("%q.CanTransitionTo(%q) = %v, want %v", tt.from, tt.to, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseResolutionState(t *testing.T) {
+	state, err := ParseResolutionState("in_progress")
+	if err != nil {
+		t.Fatalf("ParseResolutionState(in_progress) unexpected error: %v", err)
+	}
+
+	if state != ResolutionStateInProgress {
+		t.Errorf("ParseResolutionState(in_progress) = %q, want %q", state, ResolutionStateInProgress)
+	}
+
+	for _, input := range []string{"", "closed", "Resolved"} {
+		if _, err := ParseResolutionState(input); err == nil {
+			t.Errorf("ParseResolutionState(%q) expected error, got nil", input)
+		}
+	}
+}
+
+func TestComplaint_Transition(t *testing.T) {
+	complaint := &Complaint{ResolutionState: ResolutionStateOpen}
+
+	if err := complaint.Transition(ResolutionStateWontFix, "maintainer", ""); err == nil {
+		t.Error("closing as wont_fix without a reason should fail")
+	}
+
+	if err := complaint.Transition(ResolutionStateAcknowledged, "", "seen"); err == nil {
+		t.Error("a transition without an actor should fail")
+	}
+
+	if err := complaint.Transition(ResolutionStateWontFix, "maintainer", "working as intended"); err != nil {
+		t.Fatalf("Transition(wont_fix) unexpected error: %v", err)
+	}
+
+	if !complaint.IsClosed() || complaint.IsResolved() {
+		t.Errorf("wont_fix should be closed but not resolved, got %q", complaint.ResolutionState)
+	}
+
+	if complaint.ResolvedAt == nil || complaint.ResolvedBy != "maintainer" {
+		t.Error("closing should record when and by whom")
+	}
+
+	err := complaint.Transition(ResolutionStateInProgress, "maintainer", "")
+	if !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("Transition(in_progress) from wont_fix = %v, want ErrInvalidTransition", err)
+	}
+
+	if err := complaint.Transition(ResolutionStateReopened, "agent", "still happens"); err != nil {
+		t.Fatalf("Transition(reopened) unexpected error: %v", err)
+	}
+
+	if complaint.ResolvedAt != nil || complaint.ResolvedBy != "" {
+		t.Error("reopening should clear the resolution")
+	}
+}
+
+func TestComplaint_Transitions(t *testing.T) {
+	complaint := &Complaint{}
+
+	move := func(to ResolutionState, actor, reason string) {
+		before := complaint.Clone()
+		if err := complaint.Transition(to, actor, reason); err != nil {
+			t.Fatalf("Transition(%s) unexpected error: %v", to, err)
+		}
+
+		complaint.Record(before, Event{Actor: actor, Action: EventStateChanged, Reason: reason})
+	}
+
+	move(ResolutionStateWontFix, "maintainer", "working as intended")
+	move(ResolutionStateReopened, "agent", "still happens")
+
+	moves := complaint.Transitions()
+	if len(moves) != 2 {
+		t.Fatalf("expected 2 recorded transitions, got %d", len(moves))
+	}
+
+	if first := moves[0]; first.From != ResolutionStateOpen || first.To != ResolutionStateWontFix {
+		t.Errorf("a complaint without a state should move from open, got %+v", first)
+	}
+
+	last := moves[1]
+	if last.From != ResolutionStateWontFix || last.To != ResolutionStateReopened ||
+		last.Actor != "agent" || last.Reason != "still happens" {
+		t.Errorf("unexpected transition record: %+v", last)
+	}
+}
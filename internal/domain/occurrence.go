@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+// Occurrence records that an agent hit the problem a complaint describes
+// again, instead of filing a duplicate.
+type Occurrence struct {
+	AgentID   AgentID   `json:"agent_id"`
+	SessionID SessionID `json:"session_id"`
+	At        time.Time `json:"at"`
+}
+
+// Confirm records an occurrence of the complaint by an agent's session and
+// reports whether it was new. The filing session and sessions that already
+// confirmed the complaint are only counted once.
+func (c *Complaint) Confirm(agentID AgentID, sessionID SessionID, at time.Time) bool {
+	if agentID == c.AgentID && sessionID == c.SessionID {
+		return false
+	}
+
+	for _, occurrence := range c.Occurrences {
+		if occurrence.AgentID == agentID && occurrence.SessionID == sessionID {
+			return false
+		}
+	}
+
+	c.Occurrences = append(c.Occurrences, Occurrence{AgentID: agentID, SessionID: sessionID, At: at})
+
+	return true
+}
+
+// OccurrenceCount is how many times the problem was reported: the filing
+// itself, every confirmation and every complaint closed as its duplicate.
+func (c *Complaint) OccurrenceCount() int {
+	return 1 + len(c.Occurrences) + len(c.Duplicates)
+}
+
+// EffectivePriority weighs the severity by how often the problem was hit:
+// it doubles the severity weight for two occurrences, triples it for four,
+// and so on.
+func (c *Complaint) EffectivePriority() float64 {
+	return float64(c.Severity.Weight()) * (1 + math.Log2(float64(c.OccurrenceCount())))
+}
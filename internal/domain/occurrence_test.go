@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestComplaint_Confirm(t *testing.T) {
+	complaint := &Complaint{
+		AgentID:   MustParseAgentID("filer"),
+		SessionID: MustParseSessionID("session-1"),
+		Severity:  SeverityMedium,
+	}
+
+	if complaint.Confirm(complaint.AgentID, complaint.SessionID, time.Now()) {
+		t.Error("Confirm() counted the filing session again")
+	}
+
+	if !complaint.Confirm(MustParseAgentID("other"), MustParseSessionID("session-2"), time.Now()) {
+		t.Error("Confirm() ignored a new session")
+	}
+
+	if complaint.Confirm(MustParseAgentID("other"), MustParseSessionID("session-2"), time.Now()) {
+		t.Error("Confirm() counted the same session twice")
+	}
+
+	if !complaint.Confirm(MustParseAgentID("other"), MustParseSessionID("session-3"), time.Now()) {
+		t.Error("Confirm() ignored another session of the same agent")
+	}
+
+	if got := complaint.OccurrenceCount(); got != 3 {
+		t.Errorf("OccurrenceCount() = %d, want 3", got)
+	}
+}
+
+func TestComplaint_EffectivePriority(t *testing.T) {
+	tests := []struct {
+		name        string
+		severity    Severity
+		occurrences int
+		expected    float64
+	}{
+		{name: "single low", severity: SeverityLow, occurrences: 1, expected: 1},
+		{name: "single critical", severity: SeverityCritical, occurrences: 1, expected: 4},
+		{name: "medium hit twice", severity: SeverityMedium, occurrences: 2, expected: 4},
+		{name: "medium hit four times", severity: SeverityMedium, occurrences: 4, expected: 6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			complaint := &Complaint{Severity: tt.severity, Occurrences: make([]Occurrence, tt.occurrences-1)}
+			if got := complaint.EffectivePriority(); got != tt.expected {
+				t.Errorf("EffectivePriority() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
@@ -39,3 +39,19 @@ func MustParseSeverity(s string) Severity {
 
 	return severity
 }
+
+// Weight ranks severities from 1 (low) to 4 (critical); unknown severities weigh 0.
+func (s Severity) Weight() int {
+	switch s {
+	case SeverityLow:
+		return 1
+	case SeverityMedium:
+		return 2
+	case SeverityHigh:
+		return 3
+	case SeverityCritical:
+		return 4
+	default:
+		return 0
+	}
+}
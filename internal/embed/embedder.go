@@ -0,0 +1,73 @@
+// Package embed turns complaint text into vectors, so that complaints can be
+// compared by what they are about rather than by the exact words they use.
+package embed
+
+import (
+	"context"
+	"math"
+	"strings"
+
+	"github.com/larsartmann/complaints-mcp/internal/domain"
+)
+
+// Embedder turns texts into vectors of a fixed dimension.
+type Embedder interface {
+	// Name identifies the embedder and its model. Vectors of embedders with
+	// different names are not comparable.
+	Name() string
+	// Embed returns one vector per text, in order.
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// Text returns the part of a complaint that is embedded: what the agent was
+// doing, and what it was missing, confused by and wished for.
+func Text(c *domain.Complaint) string {
+	parts := make([]string, 0, 5)
+
+	for _, part := range []string{c.TaskDescription, c.ContextInfo, c.MissingInfo, c.ConfusedBy, c.FutureWishes} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+
+	return strings.Join(parts, "\n")
+}
+
+// Cosine returns the cosine similarity of two vectors, from -1 to 1. Vectors
+// of different lengths, and zero vectors, have a similarity of 0.
+func Cosine(a, b []float32) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+
+	var dot, normA, normB float64
+
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+
+	return dot / math.Sqrt(normA*normB)
+}
+
+// normalize scales a vector to unit length in place.
+func normalize(vector []float32) {
+	var norm float64
+	for _, x := range vector {
+		norm += float64(x) * float64(x)
+	}
+
+	if norm == 0 {
+		return
+	}
+
+	scale := float32(1 / math.Sqrt(norm))
+	for i := range vector {
+		vector[i] *= scale
+	}
+}
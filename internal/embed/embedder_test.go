@@ -0,0 +1,112 @@
+package embed
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHashEmbedder_SimilarWordingScoresHigher(t *testing.T) {
+	embedder := NewHashEmbedder(DefaultDimensions)
+
+	vectors, err := embedder.Embed(t.Context(), []string{
+		"Environment variables are not documented",
+		"No docs for the env vars the server reads",
+		"Table-driven tests have no example",
+	})
+	if err != nil {
+		t.Fatalf("Embed() error = %v", err)
+	}
+
+	related := Cosine(vectors[0], vectors[1])
+	unrelated := Cosine(vectors[0], vectors[2])
+
+	if related <= unrelated {
+		t.Errorf("related texts scored %v, not above unrelated texts at %v", related, unrelated)
+	}
+
+	if self := Cosine(vectors[0], vectors[0]); self < 0.999 {
+		t.Errorf("a text scored %v against itself, want 1", self)
+	}
+}
+
+func TestCosine(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []float32
+		want float64
+	}{
+		{name: "same direction", a: []float32{1, 2}, b: []float32{2, 4}, want: 1},
+		{name: "orthogonal", a: []float32{1, 0}, b: []float32{0, 1}, want: 0},
+		{name: "opposite", a: []float32{1, 0}, b: []float32{-1, 0}, want: -1},
+		{name: "zero vector", a: []float32{0, 0}, b: []float32{1, 0}, want: 0},
+		{name: "different lengths", a: []float32{1}, b: []float32{1, 0}, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Cosine(tt.a, tt.b); got < tt.want-1e-6 || got > tt.want+1e-6 {
+				t.Errorf("Cosine() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHTTPEmbedder_Embed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req embeddingRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+
+		if req.Model != "nomic-embed-text" || len(req.Input) != 2 {
+			t.Errorf("request = %+v", req)
+		}
+
+		// Out of order, as servers may answer
+		_, _ = w.Write([]byte(`{"data": [
+			{"index": 1, "embedding": [0, 1]},
+			{"index": 0, "embedding": [1, 0]}
+		]}`))
+	}))
+	defer server.Close()
+
+	embedder := NewHTTPEmbedder(server.URL, "nomic-embed-text", time.Second)
+
+	vectors, err := embedder.Embed(t.Context(), []string{"first", "second"})
+	if err != nil {
+		t.Fatalf("Embed() error = %v", err)
+	}
+
+	if vectors[0][0] != 1 || vectors[1][1] != 1 {
+		t.Errorf("vectors = %v, want them in input order", vectors)
+	}
+
+	if !strings.HasPrefix(embedder.Name(), "http-nomic-embed-text-") {
+		t.Errorf("Name() = %q", embedder.Name())
+	}
+
+	elsewhere := NewHTTPEmbedder(server.URL+"/other", "nomic-embed-text", time.Second)
+	if elsewhere.Name() == embedder.Name() {
+		t.Errorf("Name() = %q for both servers, want them told apart", embedder.Name())
+	}
+}
+
+func TestHTTPEmbedder_ServerError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "model not found", http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	_, err := NewHTTPEmbedder(server.URL, "missing", time.Second).Embed(t.Context(), []string{"text"})
+	if err == nil {
+		t.Fatal("Embed() succeeded against a failing server")
+	}
+
+	if want := "embedding server returned 404 Not Found: model not found"; err.Error() != want {
+		t.Errorf("Embed() error = %q, want %q", err, want)
+	}
+}
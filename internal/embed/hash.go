@@ -0,0 +1,92 @@
+package embed
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math"
+
+	"github.com/larsartmann/complaints-mcp/internal/search"
+)
+
+// DefaultDimensions is the vector size of the hashing embedder.
+const DefaultDimensions = 512
+
+// trigramWeight discounts a shared letter trigram against a shared word.
+const trigramWeight = 0.5
+
+// HashEmbedder embeds text offline, without a model. Each stemmed word, pair
+// of adjacent words and letter trigram of a word is hashed into one of a
+// fixed number of dimensions, weighted by the log of how often it occurs.
+// Trigrams let "config" meet "configuration" and "env" meet "environment".
+type HashEmbedder struct {
+	dimensions int
+}
+
+// NewHashEmbedder creates a hashing embedder with vectors of the given size.
+func NewHashEmbedder(dimensions int) *HashEmbedder {
+	return &HashEmbedder{dimensions: dimensions}
+}
+
+// Name identifies the hashing scheme and vector size.
+func (e *HashEmbedder) Name() string {
+	return fmt.Sprintf("hash-v1-%d", e.dimensions)
+}
+
+// Embed returns the hashed vector of each text. It never fails.
+func (e *HashEmbedder) Embed(_ context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i] = e.embed(text)
+	}
+
+	return vectors, nil
+}
+
+// feature is a hashed word, word pair or trigram of a text.
+type feature struct {
+	count  int
+	weight float64
+}
+
+func (e *HashEmbedder) embed(text string) []float32 {
+	features := make(map[string]*feature)
+
+	add := func(key string, weight float64) {
+		if f, ok := features[key]; ok {
+			f.count++
+
+			return
+		}
+
+		features[key] = &feature{count: 1, weight: weight}
+	}
+
+	terms := search.Terms(text)
+
+	for i, term := range terms {
+		add("w:"+term, 1)
+
+		if i > 0 {
+			add("p:"+terms[i-1]+" "+term, 1)
+		}
+
+		letters := []rune("^" + term + "$")
+		for j := 0; j+3 <= len(letters); j++ {
+			add("t:"+string(letters[j:j+3]), trigramWeight)
+		}
+	}
+
+	vector := make([]float32, e.dimensions)
+
+	for key, f := range features {
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(key))
+
+		vector[h.Sum32()%uint32(e.dimensions)] += float32(f.weight * (1 + math.Log(float64(f.count))))
+	}
+
+	normalize(vector)
+
+	return vector
+}
@@ -0,0 +1,111 @@
+package embed
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// maxErrorBody bounds how much of an error response is quoted in the error.
+const maxErrorBody = 512
+
+// HTTPEmbedder embeds text with a local embedding server that speaks the
+// OpenAI embeddings API, as Ollama, llama.cpp and LM Studio do: the texts
+// are POSTed as {"model": ..., "input": [...]} and the vectors read back
+// from "data".
+type HTTPEmbedder struct {
+	url    string
+	model  string
+	client *http.Client
+}
+
+// NewHTTPEmbedder creates an embedder for the embeddings endpoint at url,
+// such as http://localhost:11434/v1/embeddings, using model.
+func NewHTTPEmbedder(url, model string, timeout time.Duration) *HTTPEmbedder {
+	return &HTTPEmbedder{url: url, model: model, client: &http.Client{Timeout: timeout}}
+}
+
+// Name identifies the server's model. It carries a fingerprint of the URL
+// as well, as another server may serve a different model under the same
+// name; the URL itself is left out, as it may hold credentials.
+func (e *HTTPEmbedder) Name() string {
+	sum := sha256.Sum256([]byte(e.url + "\x00" + e.model))
+
+	return "http-" + e.model + "-" + hex.EncodeToString(sum[:6])
+}
+
+type embeddingRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+type embeddingResponse struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+}
+
+// Embed asks the server for the vectors of texts in one request.
+func (e *HTTPEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	if len(texts) == 0 {
+		return nil, nil
+	}
+
+	body, err := json.Marshal(embeddingRequest{Model: e.model, Input: texts})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode embedding request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create embedding request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("embedding server request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+
+		return nil, fmt.Errorf("embedding server returned %s: %s", resp.Status, strings.TrimSpace(string(message)))
+	}
+
+	var decoded embeddingResponse
+	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+		return nil, fmt.Errorf("failed to decode embedding response: %w", err)
+	}
+
+	if len(decoded.Data) != len(texts) {
+		return nil, fmt.Errorf("embedding server returned %d vectors for %d texts", len(decoded.Data), len(texts))
+	}
+
+	vectors := make([][]float32, len(texts))
+
+	for _, data := range decoded.Data {
+		if data.Index < 0 || data.Index >= len(texts) || vectors[data.Index] != nil {
+			return nil, fmt.Errorf("embedding server returned an unexpected index %d", data.Index)
+		}
+
+		if len(data.Embedding) == 0 {
+			return nil, errors.New("embedding server returned an empty vector")
+		}
+
+		vectors[data.Index] = data.Embedding
+	}
+
+	return vectors, nil
+}
@@ -0,0 +1,247 @@
+package embed
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/larsartmann/complaints-mcp/internal/domain"
+)
+
+const (
+	// FileName is the file vectors are persisted in, next to the complaints.
+	FileName = "embeddings.json"
+	// batchSize bounds the texts embedded in one call.
+	batchSize = 64
+)
+
+// Store keeps a vector per complaint, with a hash of the text it was
+// embedded from, so that only new and changed complaints are embedded again.
+// With a path, vectors are persisted as JSON and read back on first use. A
+// file written by another embedder, or one that cannot be parsed, is
+// started over.
+type Store struct {
+	embedder Embedder
+	path     string
+
+	mu      sync.Mutex
+	loaded  bool
+	dirty   bool                    // vectors differ from the file
+	vectors map[string]storedVector // by complaint ID
+}
+
+type storedVector struct {
+	Hash   string    `json:"hash"`
+	Vector []float32 `json:"vector"`
+}
+
+type storeFile struct {
+	Embedder string                  `json:"embedder"`
+	Vectors  map[string]storedVector `json:"vectors"`
+}
+
+// NewStore creates a store of the embedder's vectors, persisted at path. An
+// empty path keeps them in memory only.
+func NewStore(embedder Embedder, path string) *Store {
+	return &Store{embedder: embedder, path: path}
+}
+
+// Embed returns the vector of a free text.
+func (s *Store) Embed(ctx context.Context, text string) ([]float32, error) {
+	vectors, err := s.embedder.Embed(ctx, []string{text})
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed text: %w", err)
+	}
+
+	if len(vectors) != 1 {
+		return nil, fmt.Errorf("embedder returned %d vectors for 1 text", len(vectors))
+	}
+
+	return vectors[0], nil
+}
+
+// Vectors returns the vector of each complaint, in order, embedding those
+// that are new or whose text changed. complaints should be every complaint
+// there is: the vectors of any others are dropped.
+func (s *Store) Vectors(ctx context.Context, complaints []*domain.Complaint) ([][]float32, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+
+	vectors := make([][]float32, len(complaints))
+	hashes := make([]string, len(complaints))
+	current := make(map[string]struct{}, len(complaints))
+
+	var (
+		pending []int
+		texts   []string
+	)
+
+	for i, complaint := range complaints {
+		id := complaint.ID.String()
+		current[id] = struct{}{}
+
+		text := Text(complaint)
+		hashes[i] = hashText(text)
+
+		if stored, ok := s.vectors[id]; ok && stored.Hash == hashes[i] {
+			vectors[i] = stored.Vector
+
+			continue
+		}
+
+		pending = append(pending, i)
+		texts = append(texts, text)
+	}
+
+	for start := 0; start < len(pending); start += batchSize {
+		end := min(start+batchSize, len(pending))
+
+		embedded, err := s.embedder.Embed(ctx, texts[start:end])
+		if err != nil {
+			return nil, fmt.Errorf("failed to embed complaints: %w", err)
+		}
+
+		if len(embedded) != end-start {
+			return nil, fmt.Errorf("embedder returned %d vectors for %d texts", len(embedded), end-start)
+		}
+
+		for j, vector := range embedded {
+			i := pending[start+j]
+			vectors[i] = vector
+			s.vectors[complaints[i].ID.String()] = storedVector{Hash: hashes[i], Vector: vector}
+		}
+
+		s.dirty = true
+	}
+
+	for id := range s.vectors {
+		if _, ok := current[id]; !ok {
+			delete(s.vectors, id)
+
+			s.dirty = true
+		}
+	}
+
+	return vectors, nil
+}
+
+// Forget drops the vectors of deleted complaints.
+func (s *Store) Forget(ids ...domain.ComplaintID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.load(); err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		if _, ok := s.vectors[id.String()]; ok {
+			delete(s.vectors, id.String())
+
+			s.dirty = true
+		}
+	}
+
+	return nil
+}
+
+// Flush writes the vectors to the store's file if they changed since it was
+// read or last written.
+func (s *Store) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.dirty || s.path == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(storeFile{Embedder: s.embedder.Name(), Vectors: s.vectors})
+	if err != nil {
+		return fmt.Errorf("failed to encode embeddings: %w", err)
+	}
+
+	if err := writeFileAtomic(s.path, data); err != nil {
+		return fmt.Errorf("failed to write embeddings: %w", err)
+	}
+
+	s.dirty = false
+
+	return nil
+}
+
+// load reads the store's file, once.
+func (s *Store) load() error {
+	if s.loaded {
+		return nil
+	}
+
+	s.vectors = make(map[string]storedVector)
+
+	if s.path != "" {
+		data, err := os.ReadFile(s.path)
+
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+		case err != nil:
+			return fmt.Errorf("failed to read embeddings: %w", err)
+		default:
+			var file storeFile
+			if err := json.Unmarshal(data, &file); err != nil || file.Embedder != s.embedder.Name() {
+				s.dirty = true // replace it
+			} else if file.Vectors != nil {
+				s.vectors = file.Vectors
+			}
+		}
+	}
+
+	s.loaded = true
+
+	return nil
+}
+
+// hashText returns a short fingerprint of an embedded text.
+func hashText(text string) string {
+	sum := sha256.Sum256([]byte(text))
+
+	return hex.EncodeToString(sum[:16])
+}
+
+// writeFileAtomic writes data next to path and renames it into place, so a
+// crash never leaves a half-written file.
+func writeFileAtomic(path string, data []byte) error {
+	dir := filepath.Dir(path)
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(tmp.Name()) // no-op once renamed
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
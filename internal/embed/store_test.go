@@ -0,0 +1,162 @@
+package embed
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/larsartmann/complaints-mcp/internal/domain"
+)
+
+// countingEmbedder records the texts it is asked to embed.
+type countingEmbedder struct {
+	*HashEmbedder
+
+	name     string
+	embedded []string
+}
+
+func (e *countingEmbedder) Name() string {
+	return e.name
+}
+
+func (e *countingEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	e.embedded = append(e.embedded, texts...)
+
+	return e.HashEmbedder.Embed(ctx, texts)
+}
+
+func newCountingEmbedder(name string) *countingEmbedder {
+	return &countingEmbedder{HashEmbedder: NewHashEmbedder(64), name: name}
+}
+
+func newComplaint(t *testing.T, task string) *domain.Complaint {
+	t.Helper()
+
+	id, err := domain.NewComplaintID()
+	if err != nil {
+		t.Fatalf("NewComplaintID() error = %v", err)
+	}
+
+	return &domain.Complaint{ID: id, TaskDescription: task}
+}
+
+func TestStore_EmbedsOnlyNewAndChangedComplaints(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	first := newComplaint(t, "Cobra flags are confusing")
+	second := newComplaint(t, "Viper keys are undocumented")
+
+	embedder := newCountingEmbedder("counting")
+	store := NewStore(embedder, path)
+
+	if _, err := store.Vectors(t.Context(), []*domain.Complaint{first, second}); err != nil {
+		t.Fatalf("Vectors() error = %v", err)
+	}
+
+	if err := store.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	// A new store reads the persisted vectors back
+	embedder = newCountingEmbedder("counting")
+	store = NewStore(embedder, path)
+
+	second.TaskDescription = "Viper keys are still undocumented"
+
+	vectors, err := store.Vectors(t.Context(), []*domain.Complaint{first, second})
+	if err != nil {
+		t.Fatalf("Vectors() error = %v", err)
+	}
+
+	if len(embedder.embedded) != 1 || embedder.embedded[0] != second.TaskDescription {
+		t.Errorf("embedded %q, want only the changed complaint", embedder.embedded)
+	}
+
+	if len(vectors) != 2 || len(vectors[0]) != 64 || len(vectors[1]) != 64 {
+		t.Errorf("Vectors() = %d vectors, want 2 of 64 dimensions", len(vectors))
+	}
+}
+
+func TestStore_ForgetsDeletedComplaints(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	kept := newComplaint(t, "Cobra flags are confusing")
+	deleted := newComplaint(t, "Viper keys are undocumented")
+
+	store := NewStore(newCountingEmbedder("counting"), path)
+	if _, err := store.Vectors(t.Context(), []*domain.Complaint{kept, deleted}); err != nil {
+		t.Fatalf("Vectors() error = %v", err)
+	}
+
+	if err := store.Forget(deleted.ID); err != nil {
+		t.Fatalf("Forget() error = %v", err)
+	}
+
+	if err := store.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(data), kept.ID.String()) || strings.Contains(string(data), deleted.ID.String()) {
+		t.Errorf("stored vectors = %s, want only %s", data, kept.ID.String())
+	}
+}
+
+func TestStore_DiscardsVectorsOfAnotherEmbedder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	complaint := newComplaint(t, "Cobra flags are confusing")
+
+	store := NewStore(newCountingEmbedder("old-model"), path)
+	if _, err := store.Vectors(t.Context(), []*domain.Complaint{complaint}); err != nil {
+		t.Fatalf("Vectors() error = %v", err)
+	}
+
+	if err := store.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	embedder := newCountingEmbedder("new-model")
+	store = NewStore(embedder, path)
+
+	if _, err := store.Vectors(t.Context(), []*domain.Complaint{complaint}); err != nil {
+		t.Fatalf("Vectors() error = %v", err)
+	}
+
+	if len(embedder.embedded) != 1 {
+		t.Errorf("embedded %q, want the complaint embedded again", embedder.embedded)
+	}
+}
+
+func TestStore_StartsOverFromACorruptFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	if err := os.WriteFile(path, []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	complaint := newComplaint(t, "Cobra flags are confusing")
+
+	store := NewStore(newCountingEmbedder("counting"), path)
+	if _, err := store.Vectors(t.Context(), []*domain.Complaint{complaint}); err != nil {
+		t.Fatalf("Vectors() error = %v", err)
+	}
+
+	if err := store.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	embedder := newCountingEmbedder("counting")
+	store = NewStore(embedder, path)
+
+	if _, err := store.Vectors(t.Context(), []*domain.Complaint{complaint}); err != nil {
+		t.Fatalf("Vectors() error = %v", err)
+	}
+
+	if len(embedder.embedded) != 0 {
+		t.Errorf("embedded %q, want the rewritten file to be read back", embedder.embedded)
+	}
+}
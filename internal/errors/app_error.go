@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"time"
 )
 
 // ErrorCode represents different types of application errors.
@@ -14,6 +15,7 @@ const (
 	ErrCodeValidation   ErrorCode = "VALIDATION_ERROR"
 	ErrCodeNotFound     ErrorCode = "NOT_FOUND"
 	ErrCodeDuplicate    ErrorCode = "DUPLICATE_ERROR"
+	ErrCodeConflict     ErrorCode = "CONFLICT_ERROR"
 	ErrCodeInvalidInput ErrorCode = "INVALID_INPUT"
 
 	// Repository errors.
@@ -132,7 +134,7 @@ func errorCodeToHTTPStatus(code ErrorCode) int {
 		return http.StatusForbidden
 	case ErrCodeNotFound:
 		return http.StatusNotFound
-	case ErrCodeDuplicate:
+	case ErrCodeDuplicate, ErrCodeConflict:
 		return http.StatusConflict
 	case ErrCodeRateLimit:
 		return http.StatusTooManyRequests
@@ -202,3 +204,61 @@ func NewExternalError(service string, cause error) *AppError {
 
 	return NewAppErrorWithCause(ErrCodeExternal, message, cause)
 }
+
+// QuotaDetails describes why a storage quota rejected a write.
+type QuotaDetails struct {
+	UsedBytes     int64 `json:"used_bytes"`
+	MaxBytes      int64 `json:"max_bytes"`
+	RequiredBytes int64 `json:"required_bytes"`
+}
+
+// NewQuotaExceededError creates a storage error for a write that would exceed the quota.
+func NewQuotaExceededError(usedBytes, maxBytes, requiredBytes int64) *AppError {
+	message := fmt.Sprintf(
+		"storage quota exceeded: %d of %d bytes used, %d more needed",
+		usedBytes, maxBytes, requiredBytes,
+	)
+
+	return NewAppErrorWithDetails(ErrCodeStorage, message, QuotaDetails{
+		UsedBytes:     usedBytes,
+		MaxBytes:      maxBytes,
+		RequiredBytes: requiredBytes,
+	})
+}
+
+// LockDetails describes a lock that could not be acquired in time.
+type LockDetails struct {
+	Path    string `json:"path"`
+	Timeout string `json:"timeout"`
+}
+
+// NewLockTimeoutError creates a timeout error for a lock held by another process.
+func NewLockTimeoutError(path string, timeout time.Duration) *AppError {
+	message := fmt.Sprintf("timed out after %s waiting for lock %s", timeout, path)
+
+	return NewAppErrorWithDetails(ErrCodeTimeout, message, LockDetails{
+		Path:    path,
+		Timeout: timeout.String(),
+	})
+}
+
+// VersionDetails describes a write made against a stale version.
+type VersionDetails struct {
+	ID              string `json:"id"`
+	ExpectedVersion uint64 `json:"expected_version"`
+	CurrentVersion  uint64 `json:"current_version"`
+}
+
+// NewVersionConflictError creates a conflict error for a write against a stale version.
+func NewVersionConflictError(id string, expectedVersion, currentVersion uint64) *AppError {
+	message := fmt.Sprintf(
+		"complaint %s was modified concurrently: expected version %d, current version is %d",
+		id, expectedVersion, currentVersion,
+	)
+
+	return NewAppErrorWithDetails(ErrCodeConflict, message, VersionDetails{
+		ID:              id,
+		ExpectedVersion: expectedVersion,
+		CurrentVersion:  currentVersion,
+	})
+}
@@ -0,0 +1,92 @@
+// Package filelock provides advisory, cross-process exclusive file locks.
+//
+// Locks are held on an open file, so they are released when the holder
+// exits, even if it crashes. Lock files are never deleted: removing a lock
+// file while another process waits on it would let two holders in.
+//
+// Locks use flock(2), so they exclude other processes only on Unix systems.
+// Elsewhere they exclude other holders in the same process only; see
+// CrossProcess.
+package filelock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	apperrors "github.com/larsartmann/complaints-mcp/internal/errors"
+)
+
+// pollInterval is how often a contended lock is retried.
+const pollInterval = 10 * time.Millisecond
+
+// errLocked reports that another holder has the lock.
+var errLocked = errors.New("lock is held")
+
+// Lock is an acquired file lock.
+type Lock struct {
+	file *os.File
+	path string
+}
+
+// Acquire takes an exclusive lock on path, creating the file if needed. It
+// waits up to timeout and then fails with an ErrCodeTimeout AppError.
+func Acquire(ctx context.Context, path string, timeout time.Duration) (*Lock, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create lock directory: %w", err)
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %w", err)
+	}
+
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		err := tryLock(f)
+		if err == nil {
+			return &Lock{file: f, path: path}, nil
+		}
+
+		if !errors.Is(err, errLocked) {
+			_ = f.Close()
+
+			return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			_ = f.Close()
+
+			return nil, ctx.Err()
+		case <-deadline.C:
+			_ = f.Close()
+
+			return nil, apperrors.NewLockTimeoutError(path, timeout)
+		case <-ticker.C:
+		}
+	}
+}
+
+// Release releases the lock.
+func (l *Lock) Release() error {
+	err := unlock(l.file)
+	if closeErr := l.file.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
+
+// Path returns the lock file path.
+func (l *Lock) Path() string {
+	return l.path
+}
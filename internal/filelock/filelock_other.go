@@ -0,0 +1,41 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd)
+
+package filelock
+
+import (
+	"os"
+	"sync"
+)
+
+// CrossProcess reports whether locks exclude other processes. Without flock
+// they only exclude other holders in this process, so stores must not be
+// shared between processes.
+const CrossProcess = false
+
+// Held locks in this process, by lock file path.
+var (
+	heldMu sync.Mutex
+	held   = make(map[string]bool)
+)
+
+func tryLock(f *os.File) error {
+	heldMu.Lock()
+	defer heldMu.Unlock()
+
+	if held[f.Name()] {
+		return errLocked
+	}
+
+	held[f.Name()] = true
+
+	return nil
+}
+
+func unlock(f *os.File) error {
+	heldMu.Lock()
+	defer heldMu.Unlock()
+
+	delete(held, f.Name())
+
+	return nil
+}
@@ -0,0 +1,108 @@
+package projectdetect
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+)
+
+// PathChange records that a commit changed a file.
+type PathChange struct {
+	Path   string    // slash-separated, relative to the repository root
+	Commit string    // full commit SHA
+	At     time.Time // commit time
+}
+
+// ChangesSince lists the files changed by commits reachable from HEAD of the
+// repository at root that were made after since, newest first. Merge commits
+// are skipped; the commits they bring in are listed themselves.
+func (d *GitDetector) ChangesSince(ctx context.Context, root string, since time.Time) ([]PathChange, error) {
+	repo, err := v5.PlainOpenWithOptions(root, &v5.PlainOpenOptions{
+		DetectDotGit: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open git repository: %w", err)
+	}
+
+	commits, err := repo.Log(&v5.LogOptions{Order: v5.LogOrderCommitterTime})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read git log: %w", err)
+	}
+	defer commits.Close()
+
+	var changes []PathChange
+
+	err = commits.ForEach(func(commit *object.Commit) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		// Commits come newest first, so the rest are older too
+		if !commit.Committer.When.After(since) {
+			return storer.ErrStop
+		}
+
+		if commit.NumParents() > 1 {
+			return nil
+		}
+
+		paths, err := changedPaths(ctx, commit)
+		if err != nil {
+			return err
+		}
+
+		for _, path := range paths {
+			changes = append(changes, PathChange{Path: path, Commit: commit.Hash.String(), At: commit.Committer.When})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk git log: %w", err)
+	}
+
+	return changes, nil
+}
+
+// changedPaths lists the files a commit added, modified or deleted relative
+// to its parent; for a root commit that is every file.
+func changedPaths(ctx context.Context, commit *object.Commit) ([]string, error) {
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tree of %s: %w", commit.Hash, err)
+	}
+
+	var parentTree *object.Tree
+
+	if commit.NumParents() == 1 {
+		parent, err := commit.Parent(0)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read parent of %s: %w", commit.Hash, err)
+		}
+
+		parentTree, err = parent.Tree()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read tree of %s: %w", parent.Hash, err)
+		}
+	}
+
+	diff, err := object.DiffTreeContext(ctx, parentTree, tree)
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff %s: %w", commit.Hash, err)
+	}
+
+	paths := make([]string, 0, len(diff))
+
+	for _, change := range diff {
+		if change.To.Name != "" {
+			paths = append(paths, change.To.Name)
+		} else {
+			paths = append(paths, change.From.Name)
+		}
+	}
+
+	return paths, nil
+}
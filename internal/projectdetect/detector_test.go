@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing"
@@ -203,3 +204,52 @@ func commitAsTestUser(w *v5.Worktree, message string) (plumbing.Hash, error) {
 		},
 	})
 }
+
+func TestGitDetector_ChangesSince(t *testing.T) {
+	detector := NewGitDetector()
+
+	tmpDir := t.TempDir()
+	repo, err := v5.PlainInit(tmpDir, false)
+	require.NoError(t, err)
+
+	w, err := repo.Worktree()
+	require.NoError(t, err)
+
+	filed := time.Now().Add(-time.Hour)
+
+	writeAndCommit := func(path, content string, when time.Time) plumbing.Hash {
+		t.Helper()
+
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(tmpDir, path)), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, path), []byte(content), 0o644))
+
+		_, err := w.Add(path)
+		require.NoError(t, err)
+
+		hash, err := w.Commit("Update "+path, &v5.CommitOptions{
+			Author: &object.Signature{Name: "Test User", Email: "test@example.com", When: when},
+		})
+		require.NoError(t, err)
+
+		return hash
+	}
+
+	writeAndCommit("README.md", "before", filed.Add(-time.Hour))
+	writeAndCommit("docs/setup.md", "before", filed.Add(-time.Minute))
+	first := writeAndCommit("docs/setup.md", "after", filed.Add(time.Minute))
+	second := writeAndCommit("cmd/main.go", "package main", filed.Add(2*time.Minute))
+
+	changes, err := detector.ChangesSince(t.Context(), tmpDir, filed)
+	require.NoError(t, err)
+
+	require.Len(t, changes, 2)
+	assert.Equal(t, "cmd/main.go", changes[0].Path)
+	assert.Equal(t, second.String(), changes[0].Commit)
+	assert.Equal(t, "docs/setup.md", changes[1].Path)
+	assert.Equal(t, first.String(), changes[1].Commit)
+	assert.True(t, changes[1].At.After(filed))
+
+	changes, err = detector.ChangesSince(t.Context(), tmpDir, time.Now())
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+}
@@ -0,0 +1,230 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/larsartmann/complaints-mcp/internal/domain"
+)
+
+// parseField parses the value of a field:value term into a filter.
+type parseField func(value string) (Filter, error)
+
+// fields are the filters a query can use. A word whose part before the
+// colon is not one of them, such as "main.go:42", is searched as text.
+var fields = map[string]parseField{
+	"severity": parseSeverities,
+	"state":    parseStates,
+	"resolved": parseResolved,
+	"project":  idField(domain.ComplaintFieldProjectID),
+	"agent":    idField(domain.ComplaintFieldAgentID),
+	"session":  idField(domain.ComplaintFieldSessionID),
+	"tag":      labelField(func(labels []string) Filter { return TagFilter(labels) }),
+	"category": labelField(func(labels []string) Filter { return CategoryFilter(labels) }),
+	"after":    timeField(func(t time.Time) Filter { return AfterFilter(t) }),
+	"before":   timeField(func(t time.Time) Filter { return BeforeFilter(t) }),
+}
+
+// Parse parses a query such as
+//
+//	severity:high project:complaints-mcp agent:claude* resolved:false after:2026-09-01 "exact phrase" -word
+//
+// Terms are separated by spaces. A field:value term is a filter; several
+// values separated by commas, where a field allows it, match any of them.
+// Other terms are text: a word, or a phrase in double quotes. A leading -
+// negates a filter or excludes a word or phrase.
+func Parse(input string) (Query, error) {
+	var q Query
+
+	for _, raw := range split(input) {
+		negated := strings.HasPrefix(raw, "-") && len(raw) > 1
+		if negated {
+			raw = raw[1:]
+		}
+
+		name, value, ok := strings.Cut(raw, ":")
+		if parse, known := fields[strings.ToLower(name)]; ok && known {
+			filter, err := parseFilter(name, unquote(value), parse)
+			if err != nil {
+				return Query{}, err
+			}
+
+			if negated {
+				filter = NotFilter{Filter: filter}
+			}
+
+			q.Filters = append(q.Filters, filter)
+
+			continue
+		}
+
+		phrase := strings.HasPrefix(raw, `"`)
+
+		text := strings.TrimSpace(strings.ReplaceAll(raw, `"`, ""))
+		if text == "" {
+			continue
+		}
+
+		q.Terms = append(q.Terms, Term{Text: text, Phrase: phrase, Negated: negated})
+	}
+
+	return q, nil
+}
+
+func parseFilter(name, value string, parse parseField) (Filter, error) {
+	if value == "" {
+		return nil, fmt.Errorf("missing value for %s", name)
+	}
+
+	filter, err := parse(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", name, err)
+	}
+
+	return filter, nil
+}
+
+// split breaks input into terms at spaces outside double quotes. An
+// unterminated quote runs to the end of the input.
+func split(input string) []string {
+	var (
+		terms  []string
+		term   strings.Builder
+		quoted bool
+	)
+
+	for _, r := range input {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			term.WriteRune(r)
+		case unicode.IsSpace(r) && !quoted:
+			if term.Len() > 0 {
+				terms = append(terms, term.String())
+				term.Reset()
+			}
+		default:
+			term.WriteRune(r)
+		}
+	}
+
+	if term.Len() > 0 {
+		terms = append(terms, term.String())
+	}
+
+	return terms
+}
+
+// unquote strips the double quotes around a filter value such as project:"my project".
+func unquote(value string) string {
+	return strings.TrimSpace(strings.ReplaceAll(value, `"`, ""))
+}
+
+func parseSeverities(value string) (Filter, error) {
+	var severities SeverityFilter
+
+	for part := range strings.SplitSeq(value, ",") {
+		severity, err := domain.ParseSeverity(strings.ToLower(strings.TrimSpace(part)))
+		if err != nil {
+			return nil, err
+		}
+
+		severities = append(severities, severity)
+	}
+
+	return severities, nil
+}
+
+func parseStates(value string) (Filter, error) {
+	var states StateFilter
+
+	for part := range strings.SplitSeq(value, ",") {
+		state, err := domain.ParseResolutionState(strings.ToLower(strings.TrimSpace(part)))
+		if err != nil {
+			return nil, err
+		}
+
+		states = append(states, state)
+	}
+
+	return states, nil
+}
+
+func parseResolved(value string) (Filter, error) {
+	resolved, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, errors.New("expected true or false, got " + value)
+	}
+
+	return ResolvedFilter(resolved), nil
+}
+
+// labelField parses comma-separated tags or categories, lowercased as they are stored.
+func labelField(filter func([]string) Filter) parseField {
+	return func(value string) (Filter, error) {
+		var labels []string
+
+		for part := range strings.SplitSeq(strings.ToLower(value), ",") {
+			if label := strings.TrimSpace(part); label != "" {
+				labels = append(labels, label)
+			}
+		}
+
+		if len(labels) == 0 {
+			return nil, errors.New("expected a label, got " + value)
+		}
+
+		return filter(labels), nil
+	}
+}
+
+func idField(field domain.ComplaintIDField) parseField {
+	return func(value string) (Filter, error) {
+		return IDFilter{Field: field, Pattern: value}, nil
+	}
+}
+
+// timeField parses a time filter with ParseTime.
+func timeField(filter func(time.Time) Filter) parseField {
+	return func(value string) (Filter, error) {
+		t, err := ParseTime(value)
+		if err != nil {
+			return nil, err
+		}
+
+		return filter(t), nil
+	}
+}
+
+// ParseTime parses a date, taken as midnight UTC, or an RFC 3339 time, as
+// the after and before filters take them.
+func ParseTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, errors.New("expected a date such as 2026-09-01 or an RFC 3339 time, got " + value)
+	}
+
+	return t, nil
+}
+
+// idFieldName returns the query field name of an ID field.
+func idFieldName(field domain.ComplaintIDField) string {
+	switch field {
+	case domain.ComplaintFieldAgentID:
+		return "agent"
+	case domain.ComplaintFieldProjectID:
+		return "project"
+	case domain.ComplaintFieldSessionID:
+		return "session"
+	default:
+		return "unknown"
+	}
+}
@@ -0,0 +1,259 @@
+// Package query parses the search syntax shared by search_complaints and
+// the CLI into a typed query: field filters such as severity:high and
+// after:2026-09-01, and free text with "quoted phrases" and -excluded words.
+package query
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/larsartmann/complaints-mcp/internal/domain"
+)
+
+// Query is a parsed search query. A complaint matches if it passes every
+// filter and, when the query has text, contains at least one of its words
+// and every one of its phrases, and none of its negated terms.
+type Query struct {
+	Filters []Filter
+	Terms   []Term
+
+	// Fuzziness is the number of typos tolerated in a word, such as
+	// "cobar" for "cobra"; 0 matches words exactly. It is set by the
+	// caller, not by the query syntax.
+	Fuzziness int
+
+	// Sort orders the complaints listed by a query without text. Like
+	// Fuzziness, it is set by the caller.
+	Sort Sort
+}
+
+// Term is a word or quoted phrase of a query's text.
+type Term struct {
+	Text    string
+	Phrase  bool
+	Negated bool // the complaint must not contain it
+}
+
+// Filter is a condition on a complaint's fields.
+type Filter interface {
+	Matches(c *domain.Complaint) bool
+	// String returns the filter in query syntax.
+	String() string
+}
+
+// Matches reports whether the complaint passes every filter. Text is left
+// to the search index, which knows how words are stemmed.
+func (q Query) Matches(c *domain.Complaint) bool {
+	for _, filter := range q.Filters {
+		if !filter.Matches(c) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// HasText returns true if the query has words or phrases to rank by.
+func (q Query) HasText() bool {
+	return slices.ContainsFunc(q.Terms, func(t Term) bool { return !t.Negated })
+}
+
+// String returns the query in canonical syntax: filters first, then text.
+func (q Query) String() string {
+	parts := make([]string, 0, len(q.Filters)+len(q.Terms))
+
+	for _, filter := range q.Filters {
+		parts = append(parts, filter.String())
+	}
+
+	for _, term := range q.Terms {
+		parts = append(parts, term.String())
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// String returns the term in query syntax.
+func (t Term) String() string {
+	text := t.Text
+	if t.Phrase {
+		text = strconv.Quote(text)
+	}
+
+	if t.Negated {
+		return "-" + text
+	}
+
+	return text
+}
+
+// SeverityFilter matches complaints with any of the severities: severity:high,critical.
+type SeverityFilter []domain.Severity
+
+func (f SeverityFilter) Matches(c *domain.Complaint) bool {
+	return slices.Contains(f, c.Severity)
+}
+
+func (f SeverityFilter) String() string {
+	values := make([]string, len(f))
+	for i, severity := range f {
+		values[i] = string(severity)
+	}
+
+	return "severity:" + strings.Join(values, ",")
+}
+
+// StateFilter matches complaints in any of the lifecycle states: state:open,in_progress.
+type StateFilter []domain.ResolutionState
+
+func (f StateFilter) Matches(c *domain.Complaint) bool {
+	// Complaints stored before the lifecycle existed have no state
+	state := c.ResolutionState
+	if state == "" {
+		state = domain.ResolutionStateOpen
+	}
+
+	return slices.Contains(f, state)
+}
+
+func (f StateFilter) String() string {
+	values := make([]string, len(f))
+	for i, state := range f {
+		values[i] = string(state)
+	}
+
+	return "state:" + strings.Join(values, ",")
+}
+
+// ResolvedFilter matches closed complaints if true and open ones if false:
+// resolved:false. Closed means resolved, won't fix or duplicate.
+type ResolvedFilter bool
+
+func (f ResolvedFilter) Matches(c *domain.Complaint) bool {
+	return c.IsClosed() == bool(f)
+}
+
+func (f ResolvedFilter) String() string {
+	return "resolved:" + strconv.FormatBool(bool(f))
+}
+
+// IDFilter matches the agent, project or session of a complaint against a
+// pattern, ignoring case. A * in the pattern matches any run of characters:
+// agent:claude*.
+type IDFilter struct {
+	Field   domain.ComplaintIDField
+	Pattern string
+}
+
+func (f IDFilter) Matches(c *domain.Complaint) bool {
+	return MatchPattern(f.Pattern, c.GetID(f.Field))
+}
+
+func (f IDFilter) String() string {
+	return idFieldName(f.Field) + ":" + quoteValue(f.Pattern)
+}
+
+// TagFilter matches complaints carrying any of the tags: tag:cobra,viper.
+type TagFilter []string
+
+func (f TagFilter) Matches(c *domain.Complaint) bool {
+	return slices.ContainsFunc(f, func(tag string) bool { return slices.Contains(c.Tags, tag) })
+}
+
+func (f TagFilter) String() string {
+	return "tag:" + strings.Join(f, ",")
+}
+
+// CategoryFilter matches complaints in any of the categories: category:docs,api.
+type CategoryFilter []string
+
+func (f CategoryFilter) Matches(c *domain.Complaint) bool {
+	return slices.ContainsFunc(f, func(category string) bool { return slices.Contains(c.Categories, category) })
+}
+
+func (f CategoryFilter) String() string {
+	return "category:" + strings.Join(f, ",")
+}
+
+// AfterFilter matches complaints filed at or after a time: after:2026-09-01.
+type AfterFilter time.Time
+
+func (f AfterFilter) Matches(c *domain.Complaint) bool {
+	return !c.Timestamp.Before(time.Time(f))
+}
+
+func (f AfterFilter) String() string {
+	return "after:" + formatTime(time.Time(f))
+}
+
+// BeforeFilter matches complaints filed before a time: before:2026-10-01.
+type BeforeFilter time.Time
+
+func (f BeforeFilter) Matches(c *domain.Complaint) bool {
+	return c.Timestamp.Before(time.Time(f))
+}
+
+func (f BeforeFilter) String() string {
+	return "before:" + formatTime(time.Time(f))
+}
+
+// NotFilter matches complaints its filter does not: -severity:low.
+type NotFilter struct {
+	Filter Filter
+}
+
+func (f NotFilter) Matches(c *domain.Complaint) bool {
+	return !f.Filter.Matches(c)
+}
+
+func (f NotFilter) String() string {
+	return "-" + f.Filter.String()
+}
+
+// MatchPattern reports whether value matches pattern, ignoring case, where
+// a * in pattern matches any run of characters.
+func MatchPattern(pattern, value string) bool {
+	parts := strings.Split(strings.ToLower(pattern), "*")
+	value = strings.ToLower(value)
+
+	if len(parts) == 1 {
+		return parts[0] == value
+	}
+
+	if !strings.HasPrefix(value, parts[0]) {
+		return false
+	}
+
+	value = value[len(parts[0]):]
+
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(value, part)
+		if i < 0 {
+			return false
+		}
+
+		value = value[i+len(part):]
+	}
+
+	return strings.HasSuffix(value, parts[len(parts)-1])
+}
+
+// formatTime writes midnight UTC as a plain date.
+func formatTime(t time.Time) string {
+	if t.Equal(t.UTC().Truncate(24 * time.Hour)) {
+		return t.UTC().Format(time.DateOnly)
+	}
+
+	return t.Format(time.RFC3339)
+}
+
+// quoteValue quotes a filter value containing spaces or quotes.
+func quoteValue(value string) string {
+	if strings.ContainsAny(value, " \t\n\"") {
+		return strconv.Quote(value)
+	}
+
+	return value
+}
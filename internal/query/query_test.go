@@ -0,0 +1,189 @@
+package query
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/larsartmann/complaints-mcp/internal/domain"
+)
+
+func TestParse(t *testing.T) {
+	q, err := Parse(`severity:high project:complaints-mcp agent:claude* resolved:false ` +
+		`after:2026-09-01 "exact phrase" -word main.go:42`)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	want := `severity:high project:complaints-mcp agent:claude* resolved:false ` +
+		`after:2026-09-01 "exact phrase" -word main.go:42`
+	if got := q.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	if _, ok := q.Filters[4].(AfterFilter); !ok {
+		t.Errorf("Filters[4] = %T, want AfterFilter", q.Filters[4])
+	}
+
+	wantTerms := []Term{
+		{Text: "exact phrase", Phrase: true},
+		{Text: "word", Negated: true},
+		{Text: "main.go:42"},
+	}
+	if len(q.Terms) != len(wantTerms) {
+		t.Fatalf("Terms = %v, want %v", q.Terms, wantTerms)
+	}
+
+	for i, term := range wantTerms {
+		if q.Terms[i] != term {
+			t.Errorf("Terms[%d] = %+v, want %+v", i, q.Terms[i], term)
+		}
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := map[string]string{
+		"severity:urgent": "invalid severity",
+		"resolved:maybe":  "invalid resolved",
+		"after:yesterday": "invalid after",
+		"state:done":      "invalid state",
+		"tag:":            "missing value for tag",
+	}
+
+	for input, want := range tests {
+		_, err := Parse(input)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Parse(%q) error = %v, want %q", input, err, want)
+		}
+	}
+}
+
+func TestQuery_Matches(t *testing.T) {
+	complaint := &domain.Complaint{
+		Severity:        domain.SeverityHigh,
+		ResolutionState: domain.ResolutionStateOpen,
+		Tags:            []string{"cobra"},
+		Timestamp:       time.Date(2026, 9, 15, 12, 0, 0, 0, time.UTC),
+	}
+
+	tests := map[string]bool{
+		"severity:high":               true,
+		"severity:low,critical":       false,
+		"-severity:low":               true,
+		"resolved:false":              true,
+		"resolved:true":               false,
+		"state:open,in_progress":      true,
+		"tag:cobra":                   true,
+		"tag:viper":                   false,
+		"tag:viper,cobra":             true,
+		"after:2026-09-01":            true,
+		"after:2026-09-16":            false,
+		"before:2026-09-16":           true,
+		"after:2026-09-15T12:00:00Z":  true,
+		"before:2026-09-15T12:00:00Z": false,
+		"unrelated words":             true,
+	}
+
+	// Complaints stored before the lifecycle existed count as open
+	legacy := &domain.Complaint{Severity: domain.SeverityLow}
+	if q, _ := Parse("state:open"); !q.Matches(legacy) {
+		t.Error("Parse(\"state:open\").Matches() = false for a complaint without a state, want true")
+	}
+
+	for input, want := range tests {
+		q, err := Parse(input)
+		if err != nil {
+			t.Fatalf("Parse(%q) error = %v", input, err)
+		}
+
+		if got := q.Matches(complaint); got != want {
+			t.Errorf("Parse(%q).Matches() = %v, want %v", input, got, want)
+		}
+	}
+}
+
+func TestParseSort(t *testing.T) {
+	tests := []struct {
+		by, direction string
+		want          Sort
+	}{
+		{"", "", Sort{By: SortByTimestamp}},
+		{"severity", "asc", Sort{By: SortBySeverity, Ascending: true}},
+		{"Occurrence", "DESC", Sort{By: SortByOccurrence}},
+		{"priority", "", Sort{By: SortByPriority}},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseSort(tt.by, tt.direction)
+		if err != nil {
+			t.Fatalf("ParseSort(%q, %q) error = %v", tt.by, tt.direction, err)
+		}
+
+		if got != tt.want {
+			t.Errorf("ParseSort(%q, %q) = %+v, want %+v", tt.by, tt.direction, got, tt.want)
+		}
+	}
+
+	if _, err := ParseSort("popularity", ""); err == nil || !strings.Contains(err.Error(), "invalid sort field") {
+		t.Errorf("ParseSort(\"popularity\") error = %v, want invalid sort field", err)
+	}
+
+	if _, err := ParseSort("", "up"); err == nil || !strings.Contains(err.Error(), "invalid sort direction") {
+		t.Errorf("ParseSort(\"\", \"up\") error = %v, want invalid sort direction", err)
+	}
+}
+
+func TestSort_Apply(t *testing.T) {
+	day := time.Date(2026, 9, 15, 0, 0, 0, 0, time.UTC)
+
+	oldHigh := &domain.Complaint{TaskDescription: "old", Severity: domain.SeverityHigh, Timestamp: day}
+	newHigh := &domain.Complaint{TaskDescription: "new", Severity: domain.SeverityHigh, Timestamp: day.AddDate(0, 0, 2)}
+	low := &domain.Complaint{TaskDescription: "low", Severity: domain.SeverityLow, Timestamp: day.AddDate(0, 0, 1)}
+	low.Occurrences = []domain.Occurrence{{At: day}, {At: day}}
+
+	tests := []struct {
+		sort Sort
+		want []*domain.Complaint
+	}{
+		{Sort{}, []*domain.Complaint{newHigh, low, oldHigh}},
+		{Sort{By: SortByTimestamp, Ascending: true}, []*domain.Complaint{oldHigh, low, newHigh}},
+		{Sort{By: SortBySeverity}, []*domain.Complaint{newHigh, oldHigh, low}},
+		{Sort{By: SortBySeverity, Ascending: true}, []*domain.Complaint{low, oldHigh, newHigh}},
+		{Sort{By: SortByOccurrence}, []*domain.Complaint{low, newHigh, oldHigh}},
+	}
+
+	for _, tt := range tests {
+		complaints := []*domain.Complaint{oldHigh, low, newHigh}
+		tt.sort.Apply(complaints)
+
+		for i, complaint := range tt.want {
+			if complaints[i] != complaint {
+				t.Errorf("%s: complaints[%d] = %q, want %q",
+					tt.sort, i, complaints[i].TaskDescription, complaint.TaskDescription)
+			}
+		}
+	}
+}
+
+func TestMatchPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		value   string
+		want    bool
+	}{
+		{"claude*", "Claude Code", true},
+		{"claude*", "my-claude", false},
+		{"*claude*", "my-claude-agent", true},
+		{"complaints-mcp", "Complaints-MCP", true},
+		{"complaints", "complaints-mcp", false},
+		{"a*b*c", "a-b-b-c", true},
+		{"a*a", "a", false},
+		{"*", "", true},
+	}
+
+	for _, tt := range tests {
+		if got := MatchPattern(tt.pattern, tt.value); got != tt.want {
+			t.Errorf("MatchPattern(%q, %q) = %v, want %v", tt.pattern, tt.value, got, tt.want)
+		}
+	}
+}
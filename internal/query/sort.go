@@ -0,0 +1,97 @@
+package query
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/larsartmann/complaints-mcp/internal/domain"
+)
+
+// SortField is what listed complaints are ordered by.
+type SortField string
+
+const (
+	SortByTimestamp  SortField = "timestamp"  // when the complaint was filed
+	SortBySeverity   SortField = "severity"   // low to critical
+	SortByOccurrence SortField = "occurrence" // how often the problem was hit
+	SortByPriority   SortField = "priority"   // severity weighted by occurrences
+)
+
+// Sort orders listed complaints. The zero value lists the newest first.
+type Sort struct {
+	By        SortField
+	Ascending bool
+}
+
+// ParseSort parses a sort field and a direction, asc or desc. An empty field
+// means timestamp, and an empty direction desc.
+func ParseSort(by, direction string) (Sort, error) {
+	var sort Sort
+
+	switch field := SortField(strings.ToLower(by)); field {
+	case "":
+		sort.By = SortByTimestamp
+	case SortByTimestamp, SortBySeverity, SortByOccurrence, SortByPriority:
+		sort.By = field
+	default:
+		return Sort{}, fmt.Errorf("invalid sort field: %s (allowed: timestamp, severity, occurrence, priority)", by)
+	}
+
+	switch strings.ToLower(direction) {
+	case "", "desc":
+	case "asc":
+		sort.Ascending = true
+	default:
+		return Sort{}, fmt.Errorf("invalid sort direction: %s (allowed: asc, desc)", direction)
+	}
+
+	return sort, nil
+}
+
+// Apply sorts complaints in place. Complaints that tie are ordered by
+// timestamp, in the same direction.
+func (s Sort) Apply(complaints []*domain.Complaint) {
+	slices.SortStableFunc(complaints, func(a, b *domain.Complaint) int {
+		order := s.compare(a, b)
+		if order == 0 {
+			order = a.Timestamp.Compare(b.Timestamp)
+		}
+
+		if s.Ascending {
+			return order
+		}
+
+		return -order
+	})
+}
+
+func (s Sort) compare(a, b *domain.Complaint) int {
+	switch s.By {
+	case SortBySeverity:
+		return cmp.Compare(a.Severity.Weight(), b.Severity.Weight())
+	case SortByOccurrence:
+		return cmp.Compare(a.OccurrenceCount(), b.OccurrenceCount())
+	case SortByPriority:
+		return cmp.Compare(a.EffectivePriority(), b.EffectivePriority())
+	case SortByTimestamp:
+		// Left to the tie-break
+	}
+
+	return 0
+}
+
+// String returns the sort as a field and direction, such as "severity desc".
+func (s Sort) String() string {
+	by := s.By
+	if by == "" {
+		by = SortByTimestamp
+	}
+
+	if s.Ascending {
+		return string(by) + " asc"
+	}
+
+	return string(by) + " desc"
+}
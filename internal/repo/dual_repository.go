@@ -0,0 +1,491 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	v2 "charm.land/log/v2"
+	"github.com/larsartmann/complaints-mcp/internal/domain"
+	"github.com/larsartmann/complaints-mcp/internal/query"
+	"github.com/larsartmann/complaints-mcp/internal/tracing"
+)
+
+const (
+	// globalProjectsDir partitions the global store by project under GlobalDir.
+	globalProjectsDir = "projects"
+	// localStorageDir holds the project-local copy, relative to the project root.
+	localStorageDir = ".complaints-mcp"
+	// unknownProject names the global partition for complaints without a project.
+	unknownProject = "_unknown"
+)
+
+// ProjectRegistry is implemented by repositories that keep a project-local
+// copy of complaints, and so need to know where projects live on this machine.
+type ProjectRegistry interface {
+	// AddProject records the root a project was detected at on this machine,
+	// and reports whether that made a new project-local store known.
+	AddProject(projectID, root string) bool
+}
+
+// DualRepository writes every complaint to a global, per-project store under
+// GlobalDir and to a project-local store under the project's root.
+// Reads merge both, deduplicated by ComplaintID, with the global copy winning.
+//
+// Project roots are only taken from AddProject, never from the ProjectRoot
+// recorded on complaints: that is the path on the machine the complaint was
+// filed from, which complaints committed by teammates carry over. Complaints
+// of projects not detected on this machine are only kept globally.
+type DualRepository struct {
+	globalDir  string
+	tracer     tracing.Tracer
+	locks      locks       // shared by every global partition
+	generation *generation // bumped by the global partitions' writes
+
+	mu       sync.RWMutex
+	locals   map[string]*FileRepository // keyed by project root
+	projects map[string]string          // project ID to its root on this machine
+}
+
+// NewDualRepository creates a dual repository rooted at globalDir.
+func NewDualRepository(globalDir string, tracer tracing.Tracer) *DualRepository {
+	r := &DualRepository{
+		globalDir: globalDir,
+		tracer:    tracer,
+		locks:     newLocks(globalDir),
+		locals:    make(map[string]*FileRepository),
+		projects:  make(map[string]string),
+	}
+	r.generation = newGeneration(&r.locks)
+
+	return r
+}
+
+// Save writes a complaint to the global store and, if its project was added
+// with AddProject, to the project-local store. A failed local write is
+// logged, not returned: the global copy is the source of truth.
+func (r *DualRepository) Save(ctx context.Context, complaint *domain.Complaint) error {
+	ctx, span := r.tracer.Start(ctx, "DualRepository.Save")
+	defer span.End()
+
+	ctx, unlock, err := r.locks.complaint(ctx, complaint.ID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := r.global(complaint.ProjectID.String()).Save(ctx, complaint); err != nil {
+		return fmt.Errorf("failed to save complaint to global store: %w", err)
+	}
+
+	local, ok := r.projectStore(complaint.ProjectID.String())
+	if !ok {
+		return nil
+	}
+
+	if err := local.Save(ctx, complaint); err != nil {
+		v2.FromContext(ctx).Warn("Failed to save project-local complaint copy",
+			"error", err, "id", complaint.ID.String(), "project", complaint.ProjectID.String())
+	}
+
+	return nil
+}
+
+// AddProject implements ProjectRegistry. Roots that are not an existing
+// directory are ignored, so no store is ever created outside a project.
+func (r *DualRepository) AddProject(projectID, root string) bool {
+	if info, err := os.Stat(root); err != nil || !info.IsDir() {
+		return false
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.projects[projectID] = root
+
+	if _, ok := r.locals[root]; ok {
+		return false
+	}
+
+	store := NewFileRepository(filepath.Join(root, localStorageDir), r.tracer)
+	store.SetLockTimeout(r.locks.timeout)
+	r.locals[root] = store
+
+	return true
+}
+
+// FindByID finds a complaint by ID, preferring the global copy.
+func (r *DualRepository) FindByID(
+	ctx context.Context,
+	id domain.ComplaintID,
+) (*domain.Complaint, error) {
+	if id.IsZero() {
+		return nil, errors.New("invalid ComplaintID: cannot be empty")
+	}
+
+	stores, err := r.stores()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, store := range stores {
+		complaint, err := store.FindByID(ctx, id)
+		if err == nil {
+			return complaint, nil
+		}
+	}
+
+	return nil, fmt.Errorf("complaint not found: %s", id.String())
+}
+
+// FindAll finds all complaints across stores, newest page first, returned oldest first.
+func (r *DualRepository) FindAll(
+	ctx context.Context,
+	limit, offset int,
+) ([]*domain.Complaint, error) {
+	all, err := r.all(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// all is oldest first; paginate from the newest end like FileRepository
+	end := max(len(all)-offset, 0)
+	start := max(end-limit, 0)
+
+	return all[start:end], nil
+}
+
+// FindBySeverity finds complaints by severity.
+func (r *DualRepository) FindBySeverity(
+	ctx context.Context,
+	severity domain.Severity,
+	limit int,
+) ([]*domain.Complaint, error) {
+	return r.filter(ctx, func(c *domain.Complaint) bool {
+		return c.Severity == severity
+	}, limit)
+}
+
+// FindUnresolved finds unresolved complaints.
+func (r *DualRepository) FindUnresolved(
+	ctx context.Context,
+	limit int,
+) ([]*domain.Complaint, error) {
+	return r.filter(ctx, func(c *domain.Complaint) bool {
+		return !c.IsClosed()
+	}, limit)
+}
+
+// Update updates both copies of a complaint, rejecting writes made against
+// a stale version of the global copy.
+func (r *DualRepository) Update(ctx context.Context, complaint *domain.Complaint) error {
+	return updateVersioned(ctx, r.locks, r, complaint)
+}
+
+// Delete deletes every copy of a complaint.
+func (r *DualRepository) Delete(ctx context.Context, id domain.ComplaintID) error {
+	ctx, unlock, err := r.locks.complaint(ctx, id)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	stores, err := r.stores()
+	if err != nil {
+		return err
+	}
+
+	deleted := false
+
+	for _, store := range stores {
+		err := store.Delete(ctx, id)
+		if err == nil {
+			deleted = true
+
+			continue
+		}
+
+		if !os.IsNotExist(err) {
+			return fmt.Errorf("failed to delete complaint: %w", err)
+		}
+	}
+
+	if !deleted {
+		return fmt.Errorf("complaint not found: %s", id.String())
+	}
+
+	return nil
+}
+
+// Search searches complaints by text.
+func (r *DualRepository) Search(
+	ctx context.Context,
+	query string,
+	limit int,
+) ([]*domain.Complaint, error) {
+	query = strings.ToLower(query)
+
+	return r.filter(ctx, func(c *domain.Complaint) bool {
+		return matchesQuery(c, query)
+	}, limit)
+}
+
+// FindByQuery returns up to limit merged complaints passing the query's
+// filters, evaluated in memory, in its sort order.
+func (r *DualRepository) FindByQuery(
+	ctx context.Context,
+	q query.Query,
+	limit int,
+) ([]*domain.Complaint, error) {
+	matching, err := r.filter(ctx, q.Matches, math.MaxInt)
+	if err != nil {
+		return nil, err
+	}
+
+	return sortedPage(matching, q, limit), nil
+}
+
+// WarmCache is a no-op: DualRepository has no cache of its own.
+func (r *DualRepository) WarmCache(ctx context.Context) error {
+	return nil
+}
+
+// GetCacheStats returns cache statistics.
+func (r *DualRepository) GetCacheStats() CacheStats {
+	return CacheStats{}
+}
+
+// GetFilePath returns the global file path for a complaint.
+func (r *DualRepository) GetFilePath(ctx context.Context, id domain.ComplaintID) (string, error) {
+	complaint, err := r.FindByID(ctx, id)
+	if err != nil {
+		return "", err
+	}
+
+	return r.global(complaint.ProjectID.String()).GetFilePath(ctx, id)
+}
+
+// GetDocsPath returns the rendered document path, or "" if the complaint was never exported.
+func (r *DualRepository) GetDocsPath(ctx context.Context, id domain.ComplaintID) (string, error) {
+	complaint, err := r.FindByID(ctx, id)
+	if err != nil {
+		return "", err
+	}
+
+	return complaint.DocsPath, nil
+}
+
+// FindBySession finds complaints by session.
+func (r *DualRepository) FindBySession(
+	ctx context.Context,
+	sessionID string,
+	limit int,
+) ([]*domain.Complaint, error) {
+	return r.filter(ctx, func(c *domain.Complaint) bool {
+		return c.GetID(domain.ComplaintFieldSessionID) == sessionID
+	}, limit)
+}
+
+// FindByProject finds complaints by project.
+func (r *DualRepository) FindByProject(
+	ctx context.Context,
+	projectID string,
+	limit int,
+) ([]*domain.Complaint, error) {
+	return r.filter(ctx, func(c *domain.Complaint) bool {
+		return c.GetID(domain.ComplaintFieldProjectID) == projectID
+	}, limit)
+}
+
+// FindByAgent finds complaints by agent.
+func (r *DualRepository) FindByAgent(
+	ctx context.Context,
+	agentID string,
+	limit int,
+) ([]*domain.Complaint, error) {
+	return r.filter(ctx, func(c *domain.Complaint) bool {
+		return c.GetID(domain.ComplaintFieldAgentID) == agentID
+	}, limit)
+}
+
+// global returns the global store partition for a project.
+func (r *DualRepository) global(projectID string) *FileRepository {
+	return r.globalStore(filepath.Join(r.globalDir, globalProjectsDir, projectDirName(projectID)))
+}
+
+// globalStore opens a global partition guarded by the shared global locks.
+// Its writes bump the generation of the whole global store.
+func (r *DualRepository) globalStore(dir string) *FileRepository {
+	store := NewFileRepository(dir, r.tracer)
+	store.locks = r.locks
+	store.generation = r.generation
+
+	return store
+}
+
+// projectStore returns the project-local store of a project added with AddProject.
+func (r *DualRepository) projectStore(projectID string) (*FileRepository, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	root, ok := r.projects[projectID]
+	if !ok {
+		return nil, false
+	}
+
+	return r.locals[root], true
+}
+
+// stores lists the global partitions followed by every known project-local store.
+func (r *DualRepository) stores() ([]*FileRepository, error) {
+	globals, err := r.globalStores()
+	if err != nil {
+		return nil, err
+	}
+
+	return append(globals, r.localStores()...), nil
+}
+
+// globalStores lists the per-project partitions of the global store.
+func (r *DualRepository) globalStores() ([]*FileRepository, error) {
+	entries, err := os.ReadDir(filepath.Join(r.globalDir, globalProjectsDir))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to list global projects: %w", err)
+	}
+
+	var stores []*FileRepository
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			stores = append(stores, r.globalStore(
+				filepath.Join(r.globalDir, globalProjectsDir, entry.Name()),
+			))
+		}
+	}
+
+	return stores, nil
+}
+
+// localStores lists the known project-local stores in a stable order.
+func (r *DualRepository) localStores() []*FileRepository {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	stores := make([]*FileRepository, 0, len(r.locals))
+	for _, root := range slices.Sorted(maps.Keys(r.locals)) {
+		stores = append(stores, r.locals[root])
+	}
+
+	return stores
+}
+
+// all loads and merges every complaint, oldest first. Global copies are read
+// first so they win deduplication.
+func (r *DualRepository) all(ctx context.Context) ([]*domain.Complaint, error) {
+	ctx, span := r.tracer.Start(ctx, "DualRepository.all")
+	defer span.End()
+
+	stores, err := r.stores()
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[domain.ComplaintID]bool)
+
+	var merged []*domain.Complaint
+
+	for _, store := range stores {
+		complaints, err := store.FindAll(ctx, math.MaxInt32, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, complaint := range complaints {
+			if seen[complaint.ID] {
+				continue
+			}
+
+			seen[complaint.ID] = true
+			merged = append(merged, complaint)
+		}
+	}
+
+	slices.SortStableFunc(merged, func(a, b *domain.Complaint) int {
+		return a.Timestamp.Compare(b.Timestamp)
+	})
+
+	return merged, nil
+}
+
+// filter returns up to limit merged complaints matching a predicate, oldest first.
+func (r *DualRepository) filter(
+	ctx context.Context,
+	matches func(*domain.Complaint) bool,
+	limit int,
+) ([]*domain.Complaint, error) {
+	all, err := r.all(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var filtered []*domain.Complaint
+
+	for _, complaint := range all {
+		if len(filtered) >= limit {
+			break
+		}
+
+		if matches(complaint) {
+			filtered = append(filtered, complaint)
+		}
+	}
+
+	return filtered, nil
+}
+
+// projectDirName turns a project ID into a single safe path element.
+func projectDirName(projectID string) string {
+	name := strings.NewReplacer("/", "_", `\`, "_", ":", "_").Replace(projectID)
+	if name == "" || name == "." || name == ".." {
+		return unknownProject
+	}
+
+	return name
+}
+
+// AddProject delegates to the wrapped repository.
+func (r *SimpleCachedRepository) AddProject(projectID, root string) bool {
+	registry, ok := r.base.(ProjectRegistry)
+
+	return ok && registry.AddProject(projectID, root)
+}
+
+// AddProject delegates to the wrapped repository.
+func (r *QuotaRepository) AddProject(projectID, root string) bool {
+	registry, ok := r.Repository.(ProjectRegistry)
+
+	return ok && registry.AddProject(projectID, root)
+}
+
+// AddProject delegates to the wrapped repository. The index is rebuilt on
+// the next search if the project's store is new, as it was never indexed.
+func (r *IndexedRepository) AddProject(projectID, root string) bool {
+	registry, ok := r.Repository.(ProjectRegistry)
+	if !ok || !registry.AddProject(projectID, root) {
+		return false
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.built = false
+
+	return true
+}
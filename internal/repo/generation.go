@@ -0,0 +1,175 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	v2 "charm.land/log/v2"
+)
+
+const (
+	// generationName counts the writes to a store, next to its lock files.
+	generationName = "generation"
+	// generationLockName guards the counter.
+	generationLockName = "generation.lock"
+	// maxOwnGenerations bounds the writes a generation remembers making.
+	maxOwnGenerations = 4096
+)
+
+// ChangeTracker is implemented by repositories that can tell whether other
+// processes sharing the store wrote to it, so in-memory state built from
+// storage, such as a search index, can be rebuilt.
+type ChangeTracker interface {
+	// Changes returns the store's generation, which every write increments,
+	// and whether a writer other than this repository moved it past since.
+	Changes(ctx context.Context, since uint64) (uint64, bool, error)
+}
+
+// generation counts the writes to one store in a file shared by every
+// process, and remembers which of them were made through this repository.
+type generation struct {
+	locks *locks // the store's, so lock timeouts set later apply
+	path  string
+
+	mu    sync.Mutex
+	own   map[uint64]bool
+	floor uint64 // generations up to floor were forgotten
+}
+
+func newGeneration(l *locks) *generation {
+	return &generation{
+		locks: l,
+		path:  filepath.Join(l.dir, generationName),
+		own:   make(map[uint64]bool),
+	}
+}
+
+// bump records a write to the store. A failure is logged rather than
+// returned, as the write itself succeeded: other processes then only notice
+// it with the next write.
+func (g *generation) bump(ctx context.Context) {
+	if err := g.increment(ctx); err != nil {
+		v2.FromContext(ctx).Warn("Failed to record store write", "path", g.path, "error", err)
+	}
+}
+
+func (g *generation) increment(ctx context.Context) error {
+	_, release, err := g.locks.acquire(ctx, generationLockName)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	current, err := g.read()
+	if err != nil {
+		return err
+	}
+
+	next := current + 1
+
+	tmp := g.path + tempSuffix
+	if err := os.WriteFile(tmp, []byte(strconv.FormatUint(next, 10)), 0o644); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmp, g.path); err != nil {
+		return err
+	}
+
+	// Recorded under the file lock, so no reader sees next before it is known as ours
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if len(g.own) >= maxOwnGenerations {
+		clear(g.own)
+		g.floor = current
+	}
+
+	g.own[next] = true
+
+	return nil
+}
+
+// changes implements ChangeTracker.Changes.
+func (g *generation) changes(ctx context.Context, since uint64) (uint64, bool, error) {
+	_, release, err := g.locks.acquire(ctx, generationLockName)
+	if err != nil {
+		return 0, false, err
+	}
+	defer release()
+
+	current, err := g.read()
+	if err != nil {
+		return 0, false, err
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if current < since || since < g.floor {
+		return current, true, nil
+	}
+
+	foreign := false
+
+	for n := since + 1; n <= current; n++ {
+		if !g.own[n] {
+			foreign = true
+		}
+
+		delete(g.own, n)
+	}
+
+	return current, foreign, nil
+}
+
+// read returns the store's generation; a store never written to has 0.
+func (g *generation) read() (uint64, error) {
+	data, err := os.ReadFile(g.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to read store generation: %w", err)
+	}
+
+	n, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid store generation %q: %w", data, err)
+	}
+
+	return n, nil
+}
+
+// Changes implements ChangeTracker.
+func (r *FileRepository) Changes(ctx context.Context, since uint64) (uint64, bool, error) {
+	return r.generation.changes(ctx, since)
+}
+
+// Changes implements ChangeTracker.
+func (r *SQLiteRepository) Changes(ctx context.Context, since uint64) (uint64, bool, error) {
+	return r.generation.changes(ctx, since)
+}
+
+// Changes implements ChangeTracker for the global store, which every write
+// through a DualRepository reaches.
+func (r *DualRepository) Changes(ctx context.Context, since uint64) (uint64, bool, error) {
+	return r.generation.changes(ctx, since)
+}
+
+// Changes delegates to the wrapped repository.
+func (r *SimpleCachedRepository) Changes(ctx context.Context, since uint64) (uint64, bool, error) {
+	tracker, ok := r.base.(ChangeTracker)
+	if !ok {
+		return since, false, nil
+	}
+
+	return tracker.Changes(ctx, since)
+}
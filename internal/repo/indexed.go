@@ -0,0 +1,321 @@
+package repo
+
+import (
+	"context"
+	"io"
+	"math"
+	"sync"
+
+	v2 "charm.land/log/v2"
+	"github.com/larsartmann/complaints-mcp/internal/domain"
+	"github.com/larsartmann/complaints-mcp/internal/query"
+	"github.com/larsartmann/complaints-mcp/internal/search"
+)
+
+// SearchHit is a complaint matched by a ranked search.
+type SearchHit struct {
+	Complaint *domain.Complaint
+	Score     float64 // BM25 relevance; only comparable within one search
+	Field     string  // field the snippet was taken from
+	Snippet   string  // excerpt with matched words wrapped in **
+}
+
+// RankedSearcher is implemented by repositories that rank search results by relevance.
+type RankedSearcher interface {
+	SearchRanked(ctx context.Context, q query.Query, limit int) ([]SearchHit, error)
+}
+
+// IndexedRepository keeps a full-text index of the wrapped repository's
+// complaints and answers searches from it, best match first. The index is
+// built from storage on WarmCache or the first search, and updated by every
+// Save, Update and Delete made through it. If the wrapped repository is a
+// ChangeTracker, the index is rebuilt on the next search after another
+// process sharing the store wrote to it. All other operations are delegated
+// to the wrapped repository.
+type IndexedRepository struct {
+	Repository
+
+	index *search.Index
+	// mu guards building and updating the index. It is never held across a
+	// write to storage, which may wait on another process's complaint lock
+	// while the caller holding that lock waits for mu.
+	mu         sync.Mutex
+	built      bool
+	generation uint64 // of the store, as of the last build or search
+}
+
+// NewIndexedRepository wraps a repository with a full-text index.
+func NewIndexedRepository(base Repository) *IndexedRepository {
+	return &IndexedRepository{Repository: base, index: search.NewIndex()}
+}
+
+// Save saves a complaint and indexes it.
+func (r *IndexedRepository) Save(ctx context.Context, complaint *domain.Complaint) error {
+	return r.write(func() error { return r.Repository.Save(ctx, complaint) }, complaint)
+}
+
+// Update updates a complaint and re-indexes it.
+func (r *IndexedRepository) Update(ctx context.Context, complaint *domain.Complaint) error {
+	return r.write(func() error { return r.Repository.Update(ctx, complaint) }, complaint)
+}
+
+// Delete deletes a complaint and drops it from the index.
+func (r *IndexedRepository) Delete(ctx context.Context, id domain.ComplaintID) error {
+	if err := r.Repository.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.index.Remove(id)
+
+	return nil
+}
+
+// write performs a write and, if the index is built, indexes the complaint.
+// An index that is not built yet will read the complaint from storage; one
+// being built waits for the write, so it cannot miss it.
+func (r *IndexedRepository) write(save func() error, complaint *domain.Complaint) error {
+	if err := save(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.built {
+		r.index.Add(complaint)
+	}
+
+	return nil
+}
+
+// Search returns the complaints best matching a query in the syntax of
+// query.Parse, best first.
+func (r *IndexedRepository) Search(
+	ctx context.Context,
+	text string,
+	limit int,
+) ([]*domain.Complaint, error) {
+	q, err := query.Parse(text)
+	if err != nil {
+		return nil, err
+	}
+
+	hits, err := r.SearchRanked(ctx, q, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	complaints := make([]*domain.Complaint, len(hits))
+	for i, hit := range hits {
+		complaints[i] = hit.Complaint
+	}
+
+	return complaints, nil
+}
+
+// SearchRanked returns up to limit complaints matching q with their scores
+// and snippets, best first. See search.Index.Search for the semantics.
+// Queries without text are left to the wrapped repository's FindByQuery,
+// and listed in q's sort order.
+func (r *IndexedRepository) SearchRanked(
+	ctx context.Context,
+	q query.Query,
+	limit int,
+) ([]SearchHit, error) {
+	if len(q.Terms) == 0 {
+		complaints, err := r.Repository.FindByQuery(ctx, q, limit)
+		if err != nil {
+			return nil, err
+		}
+
+		return unscored(complaints), nil
+	}
+
+	index, err := r.builtIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return resolveHits(ctx, r.Repository, index.Search(q, limit))
+}
+
+// WarmCache warms the wrapped repository, then rebuilds the index.
+func (r *IndexedRepository) WarmCache(ctx context.Context) error {
+	if err := r.Repository.WarmCache(ctx); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.built = false
+
+	return r.build(ctx)
+}
+
+// builtIndex returns the index, building it first if needed.
+func (r *IndexedRepository) builtIndex(ctx context.Context) (*search.Index, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.built {
+		foreign, err := r.syncGeneration(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		if foreign {
+			v2.FromContext(ctx).Debug("Store changed by another process; rebuilding search index")
+
+			r.built = false
+		}
+	}
+
+	if !r.built {
+		if err := r.build(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	return r.index, nil
+}
+
+// syncGeneration records the store's generation and reports whether another
+// process wrote to the store since it was last recorded. The caller holds r.mu.
+func (r *IndexedRepository) syncGeneration(ctx context.Context) (bool, error) {
+	tracker, ok := r.Repository.(ChangeTracker)
+	if !ok {
+		return false, nil
+	}
+
+	current, foreign, err := tracker.Changes(ctx, r.generation)
+	if err != nil {
+		return false, err
+	}
+
+	r.generation = current
+
+	return foreign, nil
+}
+
+// build indexes every stored complaint. The caller holds r.mu.
+func (r *IndexedRepository) build(ctx context.Context) error {
+	// Taken first, so writes made while reading are caught by the next search
+	if _, err := r.syncGeneration(ctx); err != nil {
+		return err
+	}
+
+	index, err := buildIndex(ctx, r.Repository)
+	if err != nil {
+		return err
+	}
+
+	r.index, r.built = index, true
+
+	v2.FromContext(ctx).Debug("Search index built", "complaints", index.Len())
+
+	return nil
+}
+
+// buildIndex reads every complaint in base into a new index.
+func buildIndex(ctx context.Context, base Repository) (*search.Index, error) {
+	index := search.NewIndex()
+
+	for offset := 0; ; offset += scanPageSize {
+		page, err := base.FindAll(ctx, scanPageSize, offset)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, complaint := range page {
+			index.Add(complaint)
+		}
+
+		if len(page) < scanPageSize {
+			return index, nil
+		}
+	}
+}
+
+// unscored turns the complaints matched by a query without text into
+// unscored hits, in the same order.
+func unscored(complaints []*domain.Complaint) []SearchHit {
+	hits := make([]SearchHit, len(complaints))
+	for i, complaint := range complaints {
+		hits[i] = SearchHit{Complaint: complaint}
+	}
+
+	return hits
+}
+
+// resolveHits loads the complaints behind index hits. Hits whose complaint
+// can no longer be read, for instance because another process deleted it,
+// are left out.
+func resolveHits(ctx context.Context, base Repository, hits []search.Hit) ([]SearchHit, error) {
+	results := make([]SearchHit, 0, len(hits))
+
+	for _, hit := range hits {
+		complaint, err := base.FindByID(ctx, hit.ID)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+
+			v2.FromContext(ctx).Warn("Skipping unreadable search hit", "complaint_id", hit.ID, "error", err)
+
+			continue
+		}
+
+		results = append(results, SearchHit{
+			Complaint: complaint,
+			Score:     hit.Score,
+			Field:     hit.Field,
+			Snippet:   hit.Snippet,
+		})
+	}
+
+	return results, nil
+}
+
+// RankedSearch evaluates q against repository, best match first.
+// Repositories without an index of their own are ranked over a temporary
+// index of every complaint passing q's filters.
+func RankedSearch(ctx context.Context, repository Repository, q query.Query, limit int) ([]SearchHit, error) {
+	if searcher, ok := repository.(RankedSearcher); ok {
+		return searcher.SearchRanked(ctx, q, limit)
+	}
+
+	candidates, err := repository.FindByQuery(ctx, q, math.MaxInt)
+	if err != nil {
+		return nil, err
+	}
+
+	index := search.NewIndex()
+	byID := make(map[domain.ComplaintID]*domain.Complaint, len(candidates))
+
+	for _, complaint := range candidates {
+		index.Add(complaint)
+		byID[complaint.ID] = complaint
+	}
+
+	hits := index.Search(q, limit)
+	results := make([]SearchHit, len(hits))
+
+	for i, hit := range hits {
+		results[i] = SearchHit{Complaint: byID[hit.ID], Score: hit.Score, Field: hit.Field, Snippet: hit.Snippet}
+	}
+
+	return results, nil
+}
+
+// Close delegates to the wrapped repository when it holds resources.
+func (r *IndexedRepository) Close() error {
+	if closer, ok := r.Repository.(io.Closer); ok {
+		return closer.Close()
+	}
+
+	return nil
+}
@@ -0,0 +1,246 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"path/filepath"
+	"time"
+
+	v2 "charm.land/log/v2"
+	"github.com/larsartmann/complaints-mcp/internal/config"
+	"github.com/larsartmann/complaints-mcp/internal/domain"
+	"github.com/larsartmann/complaints-mcp/internal/filelock"
+)
+
+const (
+	// locksDir holds a store's lock files, relative to the directory they guard.
+	locksDir = ".locks"
+	// lockStripes bounds the number of complaint lock files per store.
+	lockStripes = 64
+	// storeLockName guards maintenance jobs that walk the whole store.
+	storeLockName = "store.lock"
+	// defaultLockTimeout is how long a write waits for another process's lock.
+	defaultLockTimeout = 5 * time.Second
+)
+
+// ComplaintLocker is implemented by repositories that can lock a complaint
+// against writers in other processes. The returned context marks the lock as
+// held, so writes made with it do not wait on themselves; reads made with it
+// bypass caches.
+type ComplaintLocker interface {
+	LockComplaint(ctx context.Context, id domain.ComplaintID) (context.Context, func(), error)
+}
+
+// StoreLocker is implemented by repositories that can lock the whole store, so
+// maintenance jobs in different processes do not run over each other.
+type StoreLocker interface {
+	LockStore(ctx context.Context) (func(), error)
+}
+
+// heldLocksKey is the context key for the lock files held by the caller.
+type heldLocksKey struct{}
+
+// locks names the lock files guarding one store.
+type locks struct {
+	dir     string
+	timeout time.Duration
+}
+
+func newLocks(dir string) locks {
+	return locks{dir: filepath.Join(dir, locksDir), timeout: defaultLockTimeout}
+}
+
+// complaint locks the stripe guarding id, unless ctx already holds it.
+func (l locks) complaint(
+	ctx context.Context,
+	id domain.ComplaintID,
+) (context.Context, func(), error) {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(id.String()))
+
+	return l.acquire(ctx, fmt.Sprintf("complaint-%02d.lock", h.Sum32()%lockStripes))
+}
+
+// store locks the whole store for a maintenance job.
+func (l locks) store(ctx context.Context) (func(), error) {
+	_, release, err := l.acquire(ctx, storeLockName)
+
+	return release, err
+}
+
+func (l locks) acquire(ctx context.Context, name string) (context.Context, func(), error) {
+	path := filepath.Join(l.dir, name)
+
+	held, _ := ctx.Value(heldLocksKey{}).(map[string]bool)
+	if held[path] {
+		return ctx, func() {}, nil
+	}
+
+	lock, err := filelock.Acquire(ctx, path, l.timeout)
+	if err != nil {
+		return ctx, nil, err
+	}
+
+	// Copy on write: contexts derived from ctx must not see this lock
+	next := make(map[string]bool, len(held)+1)
+	for p := range held {
+		next[p] = true
+	}
+
+	next[path] = true
+
+	release := func() {
+		if err := lock.Release(); err != nil {
+			v2.FromContext(ctx).Warn("Failed to release lock", "path", path, "error", err)
+		}
+	}
+
+	return context.WithValue(ctx, heldLocksKey{}, next), release, nil
+}
+
+// lockTimeout returns the configured lock timeout, or the default for
+// configs built without one.
+func lockTimeout(cfg *config.Config) time.Duration {
+	if cfg.Storage.LockTimeout <= 0 {
+		return defaultLockTimeout
+	}
+
+	return cfg.Storage.LockTimeout
+}
+
+// holdsLock reports whether ctx was returned by a LockComplaint call.
+func holdsLock(ctx context.Context) bool {
+	held, _ := ctx.Value(heldLocksKey{}).(map[string]bool)
+
+	return len(held) > 0
+}
+
+// LockComplaint locks a complaint against writers in other processes.
+func (r *FileRepository) LockComplaint(
+	ctx context.Context,
+	id domain.ComplaintID,
+) (context.Context, func(), error) {
+	return r.locks.complaint(ctx, id)
+}
+
+// LockStore locks the store for a maintenance job.
+func (r *FileRepository) LockStore(ctx context.Context) (func(), error) {
+	return r.locks.store(ctx)
+}
+
+// SetLockTimeout sets how long writes wait for locks held by other processes.
+func (r *FileRepository) SetLockTimeout(timeout time.Duration) {
+	r.locks.timeout = timeout
+}
+
+// LockComplaint locks a complaint against writers in other processes.
+func (r *SQLiteRepository) LockComplaint(
+	ctx context.Context,
+	id domain.ComplaintID,
+) (context.Context, func(), error) {
+	return r.locks.complaint(ctx, id)
+}
+
+// LockStore locks the store for a maintenance job.
+func (r *SQLiteRepository) LockStore(ctx context.Context) (func(), error) {
+	return r.locks.store(ctx)
+}
+
+// SetLockTimeout sets how long writes wait for locks held by other processes.
+func (r *SQLiteRepository) SetLockTimeout(timeout time.Duration) {
+	r.locks.timeout = timeout
+}
+
+// LockComplaint locks a complaint in the global store, which every process shares.
+func (r *DualRepository) LockComplaint(
+	ctx context.Context,
+	id domain.ComplaintID,
+) (context.Context, func(), error) {
+	return r.locks.complaint(ctx, id)
+}
+
+// LockStore locks the global store for a maintenance job.
+func (r *DualRepository) LockStore(ctx context.Context) (func(), error) {
+	return r.locks.store(ctx)
+}
+
+// SetLockTimeout sets how long writes wait for locks held by other processes.
+func (r *DualRepository) SetLockTimeout(timeout time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.locks.timeout = timeout
+	for _, store := range r.locals {
+		store.SetLockTimeout(timeout)
+	}
+}
+
+// LockComplaint delegates to the wrapped repository.
+func (r *SimpleCachedRepository) LockComplaint(
+	ctx context.Context,
+	id domain.ComplaintID,
+) (context.Context, func(), error) {
+	locker, ok := r.base.(ComplaintLocker)
+	if !ok {
+		return ctx, func() {}, nil
+	}
+
+	return locker.LockComplaint(ctx, id)
+}
+
+// LockStore delegates to the wrapped repository.
+func (r *SimpleCachedRepository) LockStore(ctx context.Context) (func(), error) {
+	locker, ok := r.base.(StoreLocker)
+	if !ok {
+		return func() {}, nil
+	}
+
+	return locker.LockStore(ctx)
+}
+
+// LockComplaint delegates to the wrapped repository.
+func (r *QuotaRepository) LockComplaint(
+	ctx context.Context,
+	id domain.ComplaintID,
+) (context.Context, func(), error) {
+	locker, ok := r.Repository.(ComplaintLocker)
+	if !ok {
+		return ctx, func() {}, nil
+	}
+
+	return locker.LockComplaint(ctx, id)
+}
+
+// LockStore delegates to the wrapped repository.
+func (r *QuotaRepository) LockStore(ctx context.Context) (func(), error) {
+	locker, ok := r.Repository.(StoreLocker)
+	if !ok {
+		return func() {}, nil
+	}
+
+	return locker.LockStore(ctx)
+}
+
+// LockComplaint delegates to the wrapped repository.
+func (r *IndexedRepository) LockComplaint(
+	ctx context.Context,
+	id domain.ComplaintID,
+) (context.Context, func(), error) {
+	locker, ok := r.Repository.(ComplaintLocker)
+	if !ok {
+		return ctx, func() {}, nil
+	}
+
+	return locker.LockComplaint(ctx, id)
+}
+
+// LockStore delegates to the wrapped repository.
+func (r *IndexedRepository) LockStore(ctx context.Context) (func(), error) {
+	locker, ok := r.Repository.(StoreLocker)
+	if !ok {
+		return func() {}, nil
+	}
+
+	return locker.LockStore(ctx)
+}
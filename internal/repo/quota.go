@@ -0,0 +1,456 @@
+package repo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	v2 "charm.land/log/v2"
+	"github.com/larsartmann/complaints-mcp/internal/domain"
+	apperrors "github.com/larsartmann/complaints-mcp/internal/errors"
+	"github.com/larsartmann/complaints-mcp/internal/query"
+)
+
+// UsageReporter is implemented by repositories that can measure their storage footprint.
+type UsageReporter interface {
+	StorageUsage(ctx context.Context) (int64, error)
+}
+
+// StorageStatsProvider is implemented by repositories that enforce a storage quota.
+type StorageStatsProvider interface {
+	GetStorageStats(ctx context.Context) (StorageStats, error)
+}
+
+// StorageStats represents storage quota usage.
+type StorageStats struct {
+	UsedBytes        int64   `json:"used_bytes"`
+	MaxBytes         int64   `json:"max_bytes"`
+	UsagePercent     float64 `json:"usage_percent"`
+	PruneEnabled     bool    `json:"prune_enabled"`
+	PrunedComplaints int64   `json:"pruned_complaints"`
+}
+
+// QuotaRepository rejects writes that would push storage past a byte quota,
+// optionally pruning the oldest closed complaints to make room. Writes that
+// do not grow a complaint are always accepted. Besides the complaints, the
+// quota counts the files kept next to them, such as rendered documents and
+// vectors; see SetExtraPaths.
+// All other operations are delegated to the wrapped repository.
+type QuotaRepository struct {
+	Repository
+
+	usage    UsageReporter
+	maxBytes int64
+	prune    bool
+	extra    []string // files and directories counted besides the complaints
+
+	// mu guards the counters below. It is never held across a write to
+	// storage, which may wait on another process's complaint lock.
+	mu       sync.Mutex
+	used     int64
+	reserved int64 // bytes of writes in flight
+	measured bool
+	pruned   int64
+}
+
+// NewQuotaRepository wraps a repository with a storage quota of maxBytes.
+func NewQuotaRepository(base Repository, maxBytes int64, prune bool) (*QuotaRepository, error) {
+	usage, ok := base.(UsageReporter)
+	if !ok {
+		return nil, fmt.Errorf("repository %T cannot report storage usage", base)
+	}
+
+	return &QuotaRepository{
+		Repository: base,
+		usage:      usage,
+		maxBytes:   maxBytes,
+		prune:      prune,
+	}, nil
+}
+
+// SetExtraPaths makes the quota count every file at or below paths as well,
+// such as the rendered documents and vectors kept in the storage directory.
+// Missing paths count as empty.
+func (r *QuotaRepository) SetExtraPaths(paths ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.extra = paths
+	r.measured = false
+}
+
+// Save saves a complaint if it fits within the quota.
+func (r *QuotaRepository) Save(ctx context.Context, complaint *domain.Complaint) error {
+	return r.write(ctx, complaint, r.Repository.Save)
+}
+
+// Update updates a complaint if it fits within the quota.
+func (r *QuotaRepository) Update(ctx context.Context, complaint *domain.Complaint) error {
+	return r.write(ctx, complaint, r.Repository.Update)
+}
+
+// GetStorageStats returns current quota usage.
+func (r *QuotaRepository) GetStorageStats(ctx context.Context) (StorageStats, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.measure(ctx); err != nil {
+		return StorageStats{}, err
+	}
+
+	return StorageStats{
+		UsedBytes:        r.used,
+		MaxBytes:         r.maxBytes,
+		UsagePercent:     float64(r.used) / float64(r.maxBytes) * 100,
+		PruneEnabled:     r.prune,
+		PrunedComplaints: r.pruned,
+	}, nil
+}
+
+// Close delegates to the wrapped repository when it holds resources.
+func (r *QuotaRepository) Close() error {
+	if closer, ok := r.Repository.(io.Closer); ok {
+		return closer.Close()
+	}
+
+	return nil
+}
+
+// write reserves room for the bytes the complaint grows by, then performs
+// the write. Usage is tracked incrementally and only re-measured near the limit.
+func (r *QuotaRepository) write(
+	ctx context.Context,
+	complaint *domain.Complaint,
+	save func(context.Context, *domain.Complaint) error,
+) error {
+	size, err := encodedSize(complaint)
+	if err != nil {
+		return fmt.Errorf("failed to marshal complaint: %w", err)
+	}
+
+	growth := size - r.storedSize(ctx, complaint.ID)
+	reservation := max(growth, 0)
+
+	if err := r.reserve(ctx, complaint.ID, reservation); err != nil {
+		return err
+	}
+
+	err = save(ctx, complaint)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.reserved -= reservation
+	if err != nil {
+		return err
+	}
+
+	r.used += growth
+
+	return nil
+}
+
+// storedSize returns the size of the stored copy of a complaint, or 0 if there is none.
+func (r *QuotaRepository) storedSize(ctx context.Context, id domain.ComplaintID) int64 {
+	stored, err := r.Repository.FindByID(ctx, id)
+	if err != nil {
+		return 0
+	}
+
+	size, err := encodedSize(stored)
+	if err != nil {
+		return 0
+	}
+
+	return size
+}
+
+// reserve ensures size more bytes fit and sets them aside, pruning if allowed.
+func (r *QuotaRepository) reserve(ctx context.Context, writing domain.ComplaintID, size int64) error {
+	if size == 0 {
+		return nil
+	}
+
+	r.mu.Lock()
+	fits, err := r.fits(ctx, size)
+	r.mu.Unlock()
+
+	if err != nil || fits {
+		return err
+	}
+
+	if r.prune {
+		// Pruning deletes complaints, which takes their locks, so it runs without mu
+		if err := r.pruneFor(ctx, writing, size); err != nil {
+			return err
+		}
+
+		r.mu.Lock()
+		fits, err = r.fits(ctx, size)
+		r.mu.Unlock()
+
+		if err != nil || fits {
+			return err
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return apperrors.NewQuotaExceededError(r.used+r.reserved, r.maxBytes, size)
+}
+
+// fits reports whether size more bytes fit, re-measuring if they might not,
+// and reserves them if so. Callers hold mu.
+func (r *QuotaRepository) fits(ctx context.Context, size int64) (bool, error) {
+	if !r.measured || r.used+r.reserved+size > r.maxBytes {
+		if err := r.measure(ctx); err != nil {
+			return false, err
+		}
+	}
+
+	if r.used+r.reserved+size > r.maxBytes {
+		return false, nil
+	}
+
+	r.reserved += size
+
+	return true, nil
+}
+
+// pruneFor deletes closed complaints (resolved, wont_fix or duplicate) and
+// their rendered documents, oldest first, until size more bytes fit. Usage
+// is estimated from the deleted complaints and measured once at the end.
+func (r *QuotaRepository) pruneFor(ctx context.Context, writing domain.ComplaintID, size int64) error {
+	all, err := r.Repository.FindAll(ctx, math.MaxInt32, 0)
+	if err != nil {
+		return fmt.Errorf("failed to list complaints for pruning: %w", err)
+	}
+
+	candidates := slices.DeleteFunc(all, func(complaint *domain.Complaint) bool {
+		return !complaint.IsClosed() || complaint.ID == writing
+	})
+	slices.SortStableFunc(candidates, func(a, b *domain.Complaint) int {
+		return a.Timestamp.Compare(b.Timestamp)
+	})
+
+	logger := v2.FromContext(ctx)
+	deleted := false
+
+	for _, complaint := range candidates {
+		r.mu.Lock()
+		room := r.used+r.reserved+size <= r.maxBytes
+		r.mu.Unlock()
+
+		if room {
+			break
+		}
+
+		if err := r.Repository.Delete(ctx, complaint.ID); err != nil {
+			return fmt.Errorf("failed to prune complaint %s: %w", complaint.ID.String(), err)
+		}
+
+		deleted = true
+		freed, _ := encodedSize(complaint)
+
+		if docSize := RemoveDocs(ctx, complaint); r.counts(complaint.DocsPath) {
+			freed += docSize
+		}
+
+		logger.Info("Pruned closed complaint to stay within storage quota",
+			"id", complaint.ID.String(), "max_bytes", r.maxBytes)
+
+		r.mu.Lock()
+		r.pruned++
+		r.used -= freed
+		r.mu.Unlock()
+	}
+
+	if !deleted {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.measure(ctx)
+}
+
+// counts reports whether path is below one of the extra paths.
+func (r *QuotaRepository) counts(path string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, extra := range r.extra {
+		if rel, err := filepath.Rel(extra, path); err == nil && filepath.IsLocal(rel) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RemoveDocs deletes the rendered document of a deleted complaint and
+// returns its size. Failures are logged: the complaint itself is gone.
+func RemoveDocs(ctx context.Context, complaint *domain.Complaint) int64 {
+	if complaint.DocsPath == "" {
+		return 0
+	}
+
+	info, err := os.Stat(complaint.DocsPath)
+	if err != nil {
+		return 0
+	}
+
+	if err := os.Remove(complaint.DocsPath); err != nil {
+		v2.FromContext(ctx).Warn("Failed to remove complaint document",
+			"error", err, "id", complaint.ID.String(), "path", complaint.DocsPath)
+
+		return 0
+	}
+
+	return info.Size()
+}
+
+// encodedSize is the size of a complaint as stored.
+func encodedSize(complaint *domain.Complaint) (int64, error) {
+	data, err := json.Marshal(complaint)
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(len(data)), nil
+}
+
+// measure refreshes the tracked usage from the wrapped repository and the
+// extra paths. Callers hold mu.
+func (r *QuotaRepository) measure(ctx context.Context) error {
+	used, err := r.usage.StorageUsage(ctx)
+	if err != nil {
+		return apperrors.NewAppErrorWithCause(
+			apperrors.ErrCodeStorage, "failed to measure storage usage", err)
+	}
+
+	for _, path := range r.extra {
+		size, err := treeUsage(path, func(string) bool { return true })
+		if err != nil {
+			return apperrors.NewAppErrorWithCause(
+				apperrors.ErrCodeStorage, "failed to measure storage usage", err)
+		}
+
+		used += size
+	}
+
+	r.used = used
+	r.measured = true
+
+	return nil
+}
+
+// StorageUsage sums the size of all complaint files.
+func (r *FileRepository) StorageUsage(ctx context.Context) (int64, error) {
+	return dirUsage(r.complaintsDir)
+}
+
+// StorageUsage reports the bytes of the database file in use, excluding free pages,
+// so deletions are reflected without a VACUUM.
+func (r *SQLiteRepository) StorageUsage(ctx context.Context) (int64, error) {
+	var pageCount, freeCount, pageSize int64
+
+	for pragma, dest := range map[string]*int64{
+		"page_count":     &pageCount,
+		"freelist_count": &freeCount,
+		"page_size":      &pageSize,
+	} {
+		if err := r.db.QueryRowContext(ctx, "PRAGMA "+pragma).Scan(dest); err != nil {
+			return 0, fmt.Errorf("failed to read %s: %w", pragma, err)
+		}
+	}
+
+	return (pageCount - freeCount) * pageSize, nil
+}
+
+// StorageUsage sums the global store; project-local copies live in each project.
+func (r *DualRepository) StorageUsage(ctx context.Context) (int64, error) {
+	return dirUsage(filepath.Join(r.globalDir, globalProjectsDir))
+}
+
+// StorageUsage delegates to the wrapped repository.
+func (r *SimpleCachedRepository) StorageUsage(ctx context.Context) (int64, error) {
+	usage, ok := r.base.(UsageReporter)
+	if !ok {
+		return 0, fmt.Errorf("repository %T cannot report storage usage", r.base)
+	}
+
+	return usage.StorageUsage(ctx)
+}
+
+// StorageUsage delegates to the wrapped repository.
+func (r *IndexedRepository) StorageUsage(ctx context.Context) (int64, error) {
+	usage, ok := r.Repository.(UsageReporter)
+	if !ok {
+		return 0, fmt.Errorf("repository %T cannot report storage usage", r.Repository)
+	}
+
+	return usage.StorageUsage(ctx)
+}
+
+// SearchRanked delegates to the wrapped repository.
+func (r *QuotaRepository) SearchRanked(ctx context.Context, q query.Query, limit int) ([]SearchHit, error) {
+	return RankedSearch(ctx, r.Repository, q, limit)
+}
+
+// dirUsage sums the size of every complaint JSON file below dir.
+func dirUsage(dir string) (int64, error) {
+	return treeUsage(dir, func(name string) bool { return strings.HasSuffix(name, ".json") })
+}
+
+// treeUsage sums the size of the files at or below root whose name passes
+// keep, skipping quarantined files.
+func treeUsage(root string, keep func(name string) bool) (int64, error) {
+	var total int64
+
+	err := filepath.WalkDir(root, func(path string, entry os.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+
+			return err
+		}
+
+		if entry.IsDir() && entry.Name() == corruptDir {
+			return filepath.SkipDir // quarantined files are not complaints
+		}
+
+		if entry.IsDir() || !keep(entry.Name()) {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil // removed while walking
+			}
+
+			return err
+		}
+
+		total += info.Size()
+
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to measure %s: %w", root, err)
+	}
+
+	return total, nil
+}
@@ -0,0 +1,233 @@
+package repo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	v2 "charm.land/log/v2"
+	"github.com/larsartmann/complaints-mcp/internal/domain"
+)
+
+const (
+	// corruptDir holds quarantined complaint files, relative to the complaints dir.
+	corruptDir = "corrupt"
+	// tempSuffix marks in-flight atomic writes.
+	tempSuffix = ".tmp"
+	// staleTempAge is how old a temp file must be before Repair treats it as abandoned.
+	staleTempAge = time.Minute
+)
+
+// ErrCorruptComplaint reports a complaint file that cannot be decoded.
+var ErrCorruptComplaint = errors.New("corrupt complaint file")
+
+// RepairReport summarizes a storage integrity check.
+type RepairReport struct {
+	Scanned     int
+	Quarantined []string // new paths of quarantined files
+	StaleTemps  int      // abandoned temp files removed
+}
+
+// Repairer is implemented by repositories that can check and repair their storage.
+type Repairer interface {
+	Repair(ctx context.Context) (RepairReport, error)
+}
+
+// Repair decodes every complaint file, moves corrupt ones into corrupt/ and
+// removes temp files left behind by interrupted writes.
+func (r *FileRepository) Repair(ctx context.Context) (RepairReport, error) {
+	ctx, span := r.tracer.Start(ctx, "FileRepository.Repair")
+	defer span.End()
+
+	var report RepairReport
+
+	unlock, err := r.locks.store(ctx)
+	if err != nil {
+		return report, err
+	}
+	defer unlock()
+
+	files, err := r.listComplaintFiles()
+	if err != nil {
+		return report, fmt.Errorf("failed to list complaint files: %w", err)
+	}
+
+	for _, file := range files {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+
+		name := file.Name()
+
+		info, err := file.Info()
+		if err != nil {
+			continue // removed while scanning
+		}
+
+		if strings.HasPrefix(name, ".") && strings.HasSuffix(name, tempSuffix) {
+			if time.Since(info.ModTime()) >= staleTempAge &&
+				os.Remove(filepath.Join(r.complaintsDir, name)) == nil {
+				report.StaleTemps++
+			}
+
+			continue
+		}
+
+		if !strings.HasSuffix(name, ".json") {
+			continue
+		}
+
+		report.Scanned++
+
+		data, err := r.readFile(name)
+		if err != nil {
+			continue
+		}
+
+		if _, err := decodeComplaintFile(data); err != nil {
+			if path := r.quarantine(ctx, name, info, err); path != "" {
+				report.Quarantined = append(report.Quarantined, path)
+			}
+		}
+	}
+
+	span.SetAttribute(ctx, "repair.scanned", report.Scanned)
+	span.SetAttribute(ctx, "repair.quarantined", len(report.Quarantined))
+
+	return report, nil
+}
+
+// quarantine moves a corrupt complaint file into corrupt/ and returns its new
+// path. Files changed since info was taken were rewritten concurrently and
+// are left alone, as is anything that cannot be moved.
+func (r *FileRepository) quarantine(
+	ctx context.Context,
+	fileName string,
+	info os.FileInfo,
+	cause error,
+) string {
+	logger := v2.FromContext(ctx)
+	src := filepath.Join(r.complaintsDir, fileName)
+
+	current, err := os.Stat(src)
+	if err != nil || !current.ModTime().Equal(info.ModTime()) || current.Size() != info.Size() {
+		return ""
+	}
+
+	dir := filepath.Join(r.complaintsDir, corruptDir)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		logger.Error("Failed to create quarantine directory", "dir", dir, "error", err)
+
+		return ""
+	}
+
+	stamp := time.Now().UTC().Format("20060102-150405.000000000")
+	dst := filepath.Join(dir, strings.TrimSuffix(fileName, ".json")+"-"+stamp+".json")
+
+	if err := os.Rename(src, dst); err != nil {
+		logger.Error("Failed to quarantine corrupt complaint file", "file", src, "error", err)
+
+		return ""
+	}
+
+	logger.Warn("Quarantined corrupt complaint file",
+		"file", src, "quarantined_to", dst, "size", info.Size(), "error", cause)
+
+	return dst
+}
+
+// decodeComplaintFile decodes a complaint file, reporting truncated or
+// unparsable content as ErrCorruptComplaint.
+func decodeComplaintFile(data []byte) (*domain.Complaint, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("%w: empty file", ErrCorruptComplaint)
+	}
+
+	var complaint domain.Complaint
+	if err := json.Unmarshal(data, &complaint); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrCorruptComplaint, err)
+	}
+
+	return &complaint, nil
+}
+
+// syncDir flushes a directory entry so a rename survives a crash. This is
+// best effort: not every platform and filesystem can sync a directory.
+func syncDir(dir string) {
+	d, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+	defer d.Close()
+
+	_ = d.Sync()
+}
+
+// Repair checks both the global partitions and every known project-local store.
+func (r *DualRepository) Repair(ctx context.Context) (RepairReport, error) {
+	var report RepairReport
+
+	// Loading everything registers the project-local stores
+	if _, err := r.all(ctx); err != nil {
+		return report, err
+	}
+
+	stores, err := r.stores()
+	if err != nil {
+		return report, err
+	}
+
+	for _, store := range stores {
+		storeReport, err := store.Repair(ctx)
+		report.Scanned += storeReport.Scanned
+		report.StaleTemps += storeReport.StaleTemps
+		report.Quarantined = append(report.Quarantined, storeReport.Quarantined...)
+
+		if err != nil {
+			return report, err
+		}
+	}
+
+	return report, nil
+}
+
+// Repair delegates to the wrapped repository. Cached copies are kept: they
+// were decoded before the file went bad and may be the only intact version.
+func (r *SimpleCachedRepository) Repair(ctx context.Context) (RepairReport, error) {
+	repairer, ok := r.base.(Repairer)
+	if !ok {
+		return RepairReport{}, nil
+	}
+
+	return repairer.Repair(ctx)
+}
+
+// Repair delegates to the wrapped repository.
+func (r *QuotaRepository) Repair(ctx context.Context) (RepairReport, error) {
+	repairer, ok := r.Repository.(Repairer)
+	if !ok {
+		return RepairReport{}, nil
+	}
+
+	return repairer.Repair(ctx)
+}
+
+// Repair delegates to the wrapped repository and rebuilds the index on the
+// next search, since repair may have quarantined complaints.
+func (r *IndexedRepository) Repair(ctx context.Context) (RepairReport, error) {
+	repairer, ok := r.Repository.(Repairer)
+	if !ok {
+		return RepairReport{}, nil
+	}
+
+	r.mu.Lock()
+	r.built = false
+	r.mu.Unlock()
+
+	return repairer.Repair(ctx)
+}
@@ -1,25 +1,32 @@
 package repo
 
 import (
+	"container/list"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"math"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
 
 	"github.com/larsartmann/complaints-mcp/internal/config"
 	"github.com/larsartmann/complaints-mcp/internal/domain"
+	"github.com/larsartmann/complaints-mcp/internal/embed"
+	"github.com/larsartmann/complaints-mcp/internal/query"
 	"github.com/larsartmann/complaints-mcp/internal/tracing"
+	"github.com/larsartmann/complaints-mcp/internal/types"
 )
 
 const (
 	defaultComplaintsDir = "complaints"
-	defaultDocsDir       = "docs/complaints"
-	defaultFindAllLimit  = 1000
+	// scanPageSize is the number of complaints a full scan reads at a time.
+	scanPageSize = 500
 )
 
 func (r *FileRepository) findByPredicate(
@@ -27,7 +34,7 @@ func (r *FileRepository) findByPredicate(
 	matches func(*domain.Complaint) bool,
 	limit int,
 ) ([]*domain.Complaint, error) {
-	all, err := r.FindAll(ctx, defaultFindAllLimit, 0)
+	all, err := r.FindAll(ctx, math.MaxInt32, 0)
 	if err != nil {
 		return nil, err
 	}
@@ -47,6 +54,31 @@ func (r *FileRepository) findByPredicate(
 	return filtered, nil
 }
 
+// FindByQuery returns up to limit complaints passing the query's filters,
+// evaluated in memory over every stored complaint, in its sort order.
+func (r *FileRepository) FindByQuery(
+	ctx context.Context,
+	q query.Query,
+	limit int,
+) ([]*domain.Complaint, error) {
+	// One listing of the directory: pages would list and sort it again each
+	all, err := r.FindAll(ctx, math.MaxInt32, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	matching := slices.DeleteFunc(all, func(c *domain.Complaint) bool { return !q.Matches(c) })
+
+	return sortedPage(matching, q, limit), nil
+}
+
+// sortedPage orders complaints by the query's sort and returns the first limit.
+func sortedPage(complaints []*domain.Complaint, q query.Query, limit int) []*domain.Complaint {
+	q.Sort.Apply(complaints)
+
+	return complaints[:min(len(complaints), limit)]
+}
+
 func (r *FileRepository) findByID(
 	ctx context.Context,
 	field domain.ComplaintIDField,
@@ -69,6 +101,9 @@ type Repository interface {
 		limit int,
 	) ([]*domain.Complaint, error)
 	FindUnresolved(ctx context.Context, limit int) ([]*domain.Complaint, error)
+	// FindByQuery returns up to limit complaints passing the query's filters,
+	// in its sort order. Text is left to the caller.
+	FindByQuery(ctx context.Context, q query.Query, limit int) ([]*domain.Complaint, error)
 	Update(ctx context.Context, complaint *domain.Complaint) error
 	Delete(ctx context.Context, id domain.ComplaintID) error
 	Search(ctx context.Context, query string, limit int) ([]*domain.Complaint, error)
@@ -82,22 +117,27 @@ type Repository interface {
 }
 
 // FileRepository implements Repository interface using file system.
+// Writes hold an advisory lock on the complaint, so processes sharing a
+// directory do not interleave them.
 type FileRepository struct {
 	complaintsDir string
-	docsDir       string
 	tracer        tracing.Tracer
+	locks         locks
+	generation    *generation
 }
 
 // NewFileRepository creates a new file repository.
 func NewFileRepository(baseDir string, tracer tracing.Tracer) *FileRepository {
 	complaintsDir := filepath.Join(baseDir, defaultComplaintsDir)
-	docsDir := filepath.Join(baseDir, defaultDocsDir)
 
-	return &FileRepository{
+	r := &FileRepository{
 		complaintsDir: complaintsDir,
-		docsDir:       docsDir,
 		tracer:        tracer,
+		locks:         newLocks(complaintsDir),
 	}
+	r.generation = newGeneration(&r.locks)
+
+	return r
 }
 
 // Save saves a complaint to file system with FLAT JSON.
@@ -112,10 +152,22 @@ func (r *FileRepository) Save(ctx context.Context, complaint *domain.Complaint)
 		return fmt.Errorf("failed to marshal complaint: %w", err)
 	}
 
+	_, unlock, err := r.locks.complaint(ctx, complaint.ID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	// Use phantom type ID for file naming
 	fileName := complaint.ID.String() + ".json"
 
-	return r.writeFile(fileName, data)
+	if err := r.writeFile(fileName, data); err != nil {
+		return err
+	}
+
+	r.generation.bump(ctx)
+
+	return nil
 }
 
 // FindByID finds a complaint by ID.
@@ -130,17 +182,28 @@ func (r *FileRepository) FindByID(
 	// Load from file
 	fileName := id.String() + ".json"
 
+	info, err := os.Stat(filepath.Join(r.complaintsDir, fileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("complaint not found: %s", fileName)
+		}
+
+		return nil, err
+	}
+
 	data, err := r.readFile(fileName)
 	if err != nil {
 		return nil, err
 	}
 
-	var complaint domain.Complaint
-	if err := json.Unmarshal(data, &complaint); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal complaint: %w", err)
+	complaint, err := decodeComplaintFile(data)
+	if err != nil {
+		r.quarantine(ctx, fileName, info, err)
+
+		return nil, err
 	}
 
-	return &complaint, nil
+	return complaint, nil
 }
 
 // FindAll finds all complaints.
@@ -211,7 +274,7 @@ func (r *FileRepository) FindBySeverity(
 	severity domain.Severity,
 	limit int,
 ) ([]*domain.Complaint, error) {
-	all, err := r.FindAll(ctx, 1000, 0)
+	all, err := r.FindAll(ctx, math.MaxInt32, 0)
 	if err != nil {
 		return nil, err
 	}
@@ -236,7 +299,7 @@ func (r *FileRepository) FindUnresolved(
 	ctx context.Context,
 	limit int,
 ) ([]*domain.Complaint, error) {
-	all, err := r.FindAll(ctx, 1000, 0)
+	all, err := r.FindAll(ctx, math.MaxInt32, 0)
 	if err != nil {
 		return nil, err
 	}
@@ -244,7 +307,7 @@ func (r *FileRepository) FindUnresolved(
 	var unresolved []*domain.Complaint
 
 	for _, complaint := range all {
-		if !complaint.ResolutionState.IsResolved() {
+		if !complaint.IsClosed() {
 			unresolved = append(unresolved, complaint)
 		}
 
@@ -256,16 +319,28 @@ func (r *FileRepository) FindUnresolved(
 	return unresolved, nil
 }
 
-// Update updates a complaint.
+// Update updates a complaint, rejecting writes made against a stale version.
 func (r *FileRepository) Update(ctx context.Context, complaint *domain.Complaint) error {
-	return r.Save(ctx, complaint)
+	return updateVersioned(ctx, r.locks, r, complaint)
 }
 
 // Delete deletes a complaint by ID.
 func (r *FileRepository) Delete(ctx context.Context, id domain.ComplaintID) error {
+	_, unlock, err := r.locks.complaint(ctx, id)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	fileName := id.String() + ".json"
 
-	return os.Remove(filepath.Join(r.complaintsDir, fileName))
+	if err := os.Remove(filepath.Join(r.complaintsDir, fileName)); err != nil {
+		return err
+	}
+
+	r.generation.bump(ctx)
+
+	return nil
 }
 
 // Search searches complaints by text.
@@ -274,7 +349,7 @@ func (r *FileRepository) Search(
 	query string,
 	limit int,
 ) ([]*domain.Complaint, error) {
-	all, err := r.FindAll(ctx, 1000, 0)
+	all, err := r.FindAll(ctx, math.MaxInt32, 0)
 	if err != nil {
 		return nil, err
 	}
@@ -284,11 +359,7 @@ func (r *FileRepository) Search(
 	var results []*domain.Complaint
 
 	for _, complaint := range all {
-		if strings.Contains(strings.ToLower(complaint.TaskDescription), query) ||
-			strings.Contains(strings.ToLower(complaint.ContextInfo), query) ||
-			strings.Contains(strings.ToLower(complaint.MissingInfo), query) ||
-			strings.Contains(strings.ToLower(complaint.ConfusedBy), query) ||
-			strings.Contains(strings.ToLower(complaint.AgentID.String()), query) {
+		if matchesQuery(complaint, query) {
 			results = append(results, complaint)
 		}
 
@@ -300,9 +371,22 @@ func (r *FileRepository) Search(
 	return results, nil
 }
 
-// WarmCache loads all complaints into cache.
+// matchesQuery reports whether a lower-cased query occurs in the searchable fields.
+func matchesQuery(complaint *domain.Complaint, query string) bool {
+	return strings.Contains(strings.ToLower(complaint.TaskDescription), query) ||
+		strings.Contains(strings.ToLower(complaint.ContextInfo), query) ||
+		strings.Contains(strings.ToLower(complaint.MissingInfo), query) ||
+		strings.Contains(strings.ToLower(complaint.ConfusedBy), query) ||
+		strings.Contains(strings.ToLower(complaint.FutureWishes), query) ||
+		strings.Contains(strings.ToLower(complaint.AgentID.String()), query) ||
+		strings.Contains(strings.ToLower(complaint.ProjectID.String()), query) ||
+		strings.Contains(strings.ToLower(complaint.SessionID.String()), query)
+}
+
+// WarmCache is a no-op: FileRepository has no cache of its own.
+// SimpleCachedRepository warms itself through LoadRecent.
 func (r *FileRepository) WarmCache(ctx context.Context) error {
-	return nil // No cache in minimal version
+	return nil
 }
 
 // GetCacheStats returns cache statistics.
@@ -326,26 +410,14 @@ func (r *FileRepository) GetFilePath(ctx context.Context, id domain.ComplaintID)
 	return filepath.Join(r.complaintsDir, fileName), nil
 }
 
-// GetDocsPath returns documentation path for a complaint.
+// GetDocsPath returns the rendered document path, or "" if the complaint was never exported.
 func (r *FileRepository) GetDocsPath(ctx context.Context, id domain.ComplaintID) (string, error) {
 	complaint, err := r.FindByID(ctx, id)
 	if err != nil {
 		return "", err
 	}
 
-	timestamp := complaint.Timestamp.Format("2006-01-02_15-04")
-
-	fileName := fmt.Sprintf(
-		"%s-%s-%s.md",
-		timestamp,
-		complaint.SessionID.String(),
-		complaint.TaskDescription[:20],
-	)
-	if len(fileName) > 100 {
-		fileName = fileName[:100]
-	}
-
-	return filepath.Join(r.docsDir, fileName), nil
+	return complaint.DocsPath, nil
 }
 
 // findByField delegates to findByID with the specified field.
@@ -395,6 +467,9 @@ type CacheStats struct {
 	Evictions        int64   `json:"evictions"`
 	CurrentSize      int64   `json:"current_size"`
 	HitRate          float64 `json:"hit_rate_percent"`
+	WarmedComplaints int64   `json:"warmed_complaints"`
+	WarmSkipped      int64   `json:"warm_skipped"`
+	WarmDurationMs   int64   `json:"warm_duration_ms"`
 }
 
 // listComplaintFiles lists all complaint files.
@@ -419,51 +494,162 @@ func (r *FileRepository) listComplaintFiles() ([]os.DirEntry, error) {
 	return files, nil
 }
 
-// writeFile writes data to a file.
+// writeFile writes a complaint file atomically; see WriteFileAtomic.
 func (r *FileRepository) writeFile(fileName string, data []byte) error {
-	err := os.MkdirAll(r.complaintsDir, 0o755)
+	return WriteFileAtomic(filepath.Join(r.complaintsDir, fileName), data)
+}
+
+// WriteFileAtomic writes data to a file atomically: the data is written and
+// synced to a temp file in the same directory, which then replaces the
+// target. A crash leaves either the old or the new file, never a truncated one.
+func WriteFileAtomic(path string, data []byte) error {
+	dir := filepath.Dir(path)
+
+	err := os.MkdirAll(dir, 0o755)
 	if err != nil {
 		return err
 	}
 
-	filePath := filepath.Join(r.complaintsDir, fileName)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+"-*"+tempSuffix)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // no-op once renamed
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+
+		return err
+	}
+
+	if err := tmp.Chmod(0o644); err != nil {
+		_ = tmp.Close()
 
-	return os.WriteFile(filePath, data, 0o644)
+		return err
+	}
+
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+
+	syncDir(dir)
+
+	return nil
 }
 
 // NewRepositoryFromConfig creates a repository based on configuration.
-func NewRepositoryFromConfig(cfg *config.Config, tracer tracing.Tracer) *FileRepository {
-	// For now, always return a FileRepository
-	// In the future, this could check cfg.Storage.CacheEnabled to return a cached repository
-	return NewFileRepository(cfg.Storage.BaseDir, tracer)
+func NewRepositoryFromConfig(cfg *config.Config, tracer tracing.Tracer) (Repository, error) {
+	var base Repository
+
+	switch {
+	case cfg.Storage.Dual:
+		dualRepo := NewDualRepository(cfg.Storage.GlobalDir, tracer)
+		dualRepo.SetLockTimeout(lockTimeout(cfg))
+		base = dualRepo
+	case cfg.Storage.StorageBackend == types.StorageBackendSQLite:
+		sqliteRepo, err := NewSQLiteRepository(cfg.Storage.BaseDir, tracer)
+		if err != nil {
+			return nil, err
+		}
+
+		sqliteRepo.SetLockTimeout(lockTimeout(cfg))
+		base = sqliteRepo
+	default:
+		fileRepo := NewFileRepository(cfg.Storage.BaseDir, tracer)
+		fileRepo.SetLockTimeout(lockTimeout(cfg))
+		base = fileRepo
+	}
+
+	if cfg.Storage.CacheEnabled {
+		base = NewSimpleCachedRepository(
+			base,
+			cfg.Storage.CacheSize.Int(),
+			cfg.Storage.EvictionPolicy,
+		)
+	}
+
+	base = NewIndexedRepository(base)
+
+	if cfg.Storage.MaxSize == 0 {
+		return base, nil
+	}
+
+	quotaRepo, err := NewQuotaRepository(base, int64(cfg.Storage.MaxSize), cfg.Storage.QuotaPrune)
+	if err != nil {
+		return nil, err
+	}
+
+	// Documents without a project and vectors are kept in the storage dir too
+	extra := []string{filepath.Join(cfg.Storage.BaseDir, embed.FileName)}
+	if cfg.Storage.Docs.Enabled && cfg.Storage.Docs.Dir != "" {
+		extra = append(extra, filepath.Join(cfg.Storage.BaseDir, cfg.Storage.Docs.Dir))
+	}
+
+	quotaRepo.SetExtraPaths(extra...)
+
+	return quotaRepo, nil
 }
 
 // SimpleCachedRepository provides basic caching functionality.
 type SimpleCachedRepository struct {
 	base    Repository
-	cache   map[domain.ComplaintID]*domain.Complaint
+	cache   map[domain.ComplaintID]*list.Element
+	order   *list.List // front = most recently used (LRU) or most recently inserted (FIFO)
 	maxSize int
+	policy  types.CacheEvictionPolicy
 	stats   CacheStats
 	mu      sync.RWMutex
 }
 
+// cacheEntry is the value stored in each element of SimpleCachedRepository.order.
+type cacheEntry struct {
+	id        domain.ComplaintID
+	complaint *domain.Complaint
+}
+
 // NewSimpleCachedRepository creates a simple cached repository wrapper.
-func NewSimpleCachedRepository(base Repository, maxSize int) *SimpleCachedRepository {
+func NewSimpleCachedRepository(
+	base Repository,
+	maxSize int,
+	policy types.CacheEvictionPolicy,
+) *SimpleCachedRepository {
+	if maxSize <= 0 {
+		maxSize = types.DefaultCacheSize.Int()
+	}
+
+	if !policy.IsValid() {
+		policy = types.EvictionLRU
+	}
+
 	return &SimpleCachedRepository{
 		base:    base,
-		cache:   make(map[domain.ComplaintID]*domain.Complaint),
+		cache:   make(map[domain.ComplaintID]*list.Element),
+		order:   list.New(),
 		maxSize: maxSize,
+		policy:  policy,
 		stats: CacheStats{
-			MaxSize: int64(maxSize),
+			MaxCacheSize: int64(maxSize),
+			MaxSize:      int64(maxSize),
 		},
 	}
 }
 
 // Save implements Repository interface.
+//
+// Writes to the base repository run without r.mu: they may wait on another
+// process's complaint lock, and a caller holding that lock may be waiting
+// for r.mu.
 func (r *SimpleCachedRepository) Save(ctx context.Context, complaint *domain.Complaint) error {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-
 	// Save to base repository
 	err := r.base.Save(ctx, complaint)
 	if err != nil {
@@ -471,8 +657,9 @@ func (r *SimpleCachedRepository) Save(ctx context.Context, complaint *domain.Com
 	}
 
 	// Add to cache
-	r.cache[complaint.ID] = complaint
-	r.updateCacheSize()
+	r.mu.Lock()
+	r.store(complaint)
+	r.mu.Unlock()
 
 	return nil
 }
@@ -482,20 +669,34 @@ func (r *SimpleCachedRepository) FindByID(
 	ctx context.Context,
 	id domain.ComplaintID,
 ) (*domain.Complaint, error) {
-	r.mu.RLock()
+	// Read-modify-write cycles under a lock must see other processes' writes
+	if holdsLock(ctx) {
+		complaint, err := r.base.FindByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
 
-	// Check cache first
-	if cached, found := r.cache[id]; found {
-		r.mu.RUnlock()
 		r.mu.Lock()
+		r.store(complaint)
+		r.mu.Unlock()
+
+		return complaint, nil
+	}
+
+	// A hit may reorder the LRU list, so lookups need the write lock
+	r.mu.Lock()
+
+	// Check cache first
+	if elem, found := r.cache[id]; found {
+		r.touch(elem)
 		r.stats.Hits++
 		r.recalculateHitRate()
 		r.mu.Unlock()
 
-		return cached, nil
+		return elem.Value.(*cacheEntry).complaint, nil
 	}
 
-	r.mu.RUnlock()
+	r.mu.Unlock()
 
 	// Get from base repository
 	complaint, err := r.base.FindByID(ctx, id)
@@ -505,10 +706,9 @@ func (r *SimpleCachedRepository) FindByID(
 
 	// Add to cache
 	r.mu.Lock()
-	r.cache[id] = complaint
 	r.stats.Misses++
 	r.recalculateHitRate()
-	r.updateCacheSize()
+	r.store(complaint)
 	r.mu.Unlock()
 
 	return complaint, nil
@@ -526,26 +726,52 @@ func (r *SimpleCachedRepository) GetCacheStats() CacheStats {
 	return r.stats
 }
 
-// updateCacheSize updates current cache size and eviction logic.
-func (r *SimpleCachedRepository) updateCacheSize() {
-	currentSize := int64(len(r.cache))
-	r.stats.CurrentSize = currentSize
-	r.stats.CachedComplaints = currentSize
+// touch records an access; only LRU cares about recency.
+func (r *SimpleCachedRepository) touch(elem *list.Element) {
+	if r.policy == types.EvictionLRU {
+		r.order.MoveToFront(elem)
+	}
+}
 
-	// Simple eviction: if over max size, remove oldest (first) items
-	for currentSize > int64(r.maxSize) {
-		var oldestID domain.ComplaintID
-		for id := range r.cache {
-			oldestID = id
+// store inserts or refreshes a complaint and evicts according to the policy.
+func (r *SimpleCachedRepository) store(complaint *domain.Complaint) {
+	if elem, found := r.cache[complaint.ID]; found {
+		elem.Value.(*cacheEntry).complaint = complaint
+		r.touch(elem)
 
-			break
-		}
+		return
+	}
 
-		delete(r.cache, oldestID)
+	// With no eviction policy a full cache simply stops admitting entries
+	if r.policy == types.EvictionNone && r.order.Len() >= r.maxSize {
+		return
+	}
+
+	r.cache[complaint.ID] = r.order.PushFront(&cacheEntry{id: complaint.ID, complaint: complaint})
+	r.updateCacheSize()
+}
+
+// remove drops a complaint from the cache if present.
+func (r *SimpleCachedRepository) remove(id domain.ComplaintID) {
+	if elem, found := r.cache[id]; found {
+		r.order.Remove(elem)
+		delete(r.cache, id)
+	}
+
+	r.updateCacheSize()
+}
+
+// updateCacheSize updates current cache size and eviction logic.
+func (r *SimpleCachedRepository) updateCacheSize() {
+	// Evict from the back: least recently used (LRU) or oldest inserted (FIFO)
+	for r.order.Len() > r.maxSize {
+		oldest := r.order.Back()
+		r.order.Remove(oldest)
+		delete(r.cache, oldest.Value.(*cacheEntry).id)
 		r.stats.Evictions++
-		currentSize = int64(len(r.cache))
 	}
 
+	currentSize := int64(r.order.Len())
 	r.stats.CurrentSize = currentSize
 	r.stats.CachedComplaints = currentSize
 }
@@ -566,6 +792,15 @@ func (r *SimpleCachedRepository) Search(
 	return r.base.Search(ctx, query, limit)
 }
 
+// FindByQuery delegates to the wrapped repository.
+func (r *SimpleCachedRepository) FindByQuery(
+	ctx context.Context,
+	q query.Query,
+	limit int,
+) ([]*domain.Complaint, error) {
+	return r.base.FindByQuery(ctx, q, limit)
+}
+
 func (r *SimpleCachedRepository) FindByProject(
 	ctx context.Context,
 	projectID string,
@@ -617,9 +852,6 @@ func (r *SimpleCachedRepository) FindByAgent(
 }
 
 func (r *SimpleCachedRepository) Delete(ctx context.Context, id domain.ComplaintID) error {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-
 	// Delete from base repository
 	err := r.base.Delete(ctx, id)
 	if err != nil {
@@ -627,34 +859,35 @@ func (r *SimpleCachedRepository) Delete(ctx context.Context, id domain.Complaint
 	}
 
 	// Remove from cache
-	delete(r.cache, id)
-	r.updateCacheSize()
+	r.mu.Lock()
+	r.remove(id)
+	r.mu.Unlock()
 
 	return nil
 }
 
 func (r *SimpleCachedRepository) Update(ctx context.Context, complaint *domain.Complaint) error {
+	// Update base repository
+	err := r.base.Update(ctx, complaint)
+
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	// Update base repository
-	err := r.base.Update(ctx, complaint)
 	if err != nil {
+		// The cached copy may be stale, e.g. after a version conflict
+		r.remove(complaint.ID)
+
 		return err
 	}
 
 	// Update cache
 	if _, exists := r.cache[complaint.ID]; exists {
-		r.cache[complaint.ID] = complaint
+		r.store(complaint)
 	}
 
 	return nil
 }
 
-func (r *SimpleCachedRepository) WarmCache(ctx context.Context) error {
-	return r.base.WarmCache(ctx)
-}
-
 func (r *SimpleCachedRepository) GetFilePath(
 	ctx context.Context,
 	id domain.ComplaintID,
@@ -675,13 +908,22 @@ func getCachedPath(ctx context.Context, id domain.ComplaintID, fn pathFunc) (str
 	return fn(ctx, id)
 }
 
+// Close releases the base repository if it holds resources.
+func (r *SimpleCachedRepository) Close() error {
+	if closer, ok := r.base.(io.Closer); ok {
+		return closer.Close()
+	}
+
+	return nil
+}
+
 // NewCachedRepository creates a cached repository with minimal cache implementation.
 func NewCachedRepository(baseDir string, tracer tracing.Tracer) *SimpleCachedRepository {
 	// Create file repository as base
 	baseRepo := NewFileRepository(baseDir, tracer)
 
 	// Wrap with simple cache layer
-	return NewSimpleCachedRepository(baseRepo, 1000)
+	return NewSimpleCachedRepository(baseRepo, types.DefaultCacheSize.Int(), types.EvictionLRU)
 }
 
 // readFile reads data from a file.
@@ -0,0 +1,502 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/larsartmann/complaints-mcp/internal/domain"
+	"github.com/larsartmann/complaints-mcp/internal/query"
+	"github.com/larsartmann/complaints-mcp/internal/tracing"
+
+	_ "modernc.org/sqlite" // registers the pure-Go "sqlite" driver
+)
+
+const defaultSQLiteFile = "complaints.db"
+
+// sqliteSchema keeps the full complaint as a JSON document and promotes
+// the fields we filter on into indexed columns.
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS complaints (
+	id          TEXT PRIMARY KEY,
+	agent_id    TEXT NOT NULL DEFAULT '',
+	session_id  TEXT NOT NULL DEFAULT '',
+	project_id  TEXT NOT NULL DEFAULT '',
+	severity    TEXT NOT NULL,
+	resolved    INTEGER NOT NULL DEFAULT 0,
+	created_at  INTEGER NOT NULL,
+	search_text TEXT NOT NULL DEFAULT '',
+	data        TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_complaints_severity   ON complaints (severity, created_at);
+CREATE INDEX IF NOT EXISTS idx_complaints_resolved   ON complaints (resolved, created_at);
+CREATE INDEX IF NOT EXISTS idx_complaints_agent_id   ON complaints (agent_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_complaints_session_id ON complaints (session_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_complaints_project_id ON complaints (project_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_complaints_created_at ON complaints (created_at);
+`
+
+// SQLiteRepository implements Repository interface on top of an embedded SQLite database.
+// Single statements are atomic in SQLite, so its locks only serialize
+// read-modify-write cycles and maintenance jobs.
+type SQLiteRepository struct {
+	db         *sql.DB
+	dbPath     string
+	tracer     tracing.Tracer
+	locks      locks
+	generation *generation
+}
+
+// NewSQLiteRepository opens (or creates) the complaints database under baseDir.
+func NewSQLiteRepository(baseDir string, tracer tracing.Tracer) (*SQLiteRepository, error) {
+	err := os.MkdirAll(baseDir, 0o755)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create storage directory: %w", err)
+	}
+
+	dbPath := filepath.Join(baseDir, defaultSQLiteFile)
+
+	db, err := sql.Open("sqlite", dbPath+"?_pragma=journal_mode(WAL)&_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sqlite database: %w", err)
+	}
+
+	// SQLite serializes writers anyway; a single connection avoids SQLITE_BUSY churn.
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		_ = db.Close()
+
+		return nil, fmt.Errorf("failed to initialize sqlite schema: %w", err)
+	}
+
+	r := &SQLiteRepository{
+		db:     db,
+		dbPath: dbPath,
+		tracer: tracer,
+		locks:  newLocks(baseDir),
+	}
+	r.generation = newGeneration(&r.locks)
+
+	return r, nil
+}
+
+// Close closes the underlying database handle.
+func (r *SQLiteRepository) Close() error {
+	return r.db.Close()
+}
+
+// Save inserts or replaces a complaint.
+func (r *SQLiteRepository) Save(ctx context.Context, complaint *domain.Complaint) error {
+	ctx, span := r.tracer.Start(ctx, "SQLiteRepository.Save")
+	defer span.End()
+
+	if err := complaint.Validate(); err != nil {
+		return fmt.Errorf("invalid complaint: %w", err)
+	}
+
+	data, err := json.Marshal(complaint)
+	if err != nil {
+		return fmt.Errorf("failed to marshal complaint: %w", err)
+	}
+
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO complaints
+			(id, agent_id, session_id, project_id, severity, resolved, created_at, search_text, data)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			agent_id    = excluded.agent_id,
+			session_id  = excluded.session_id,
+			project_id  = excluded.project_id,
+			severity    = excluded.severity,
+			resolved    = excluded.resolved,
+			created_at  = excluded.created_at,
+			search_text = excluded.search_text,
+			data        = excluded.data`,
+		complaint.ID.String(),
+		complaint.AgentID.String(),
+		complaint.SessionID.String(),
+		complaint.ProjectID.String(),
+		string(complaint.Severity),
+		complaint.IsClosed(),
+		complaint.Timestamp.UnixNano(),
+		searchText(complaint),
+		string(data),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save complaint: %w", err)
+	}
+
+	r.generation.bump(ctx)
+
+	return nil
+}
+
+// FindByID finds a complaint by ID.
+func (r *SQLiteRepository) FindByID(
+	ctx context.Context,
+	id domain.ComplaintID,
+) (*domain.Complaint, error) {
+	if id.IsZero() {
+		return nil, errors.New("invalid ComplaintID: cannot be empty")
+	}
+
+	var data string
+
+	err := r.db.QueryRowContext(ctx, `SELECT data FROM complaints WHERE id = ?`, id.String()).
+		Scan(&data)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("complaint not found: %s", id.String())
+		}
+
+		return nil, fmt.Errorf("failed to query complaint: %w", err)
+	}
+
+	return decodeComplaint(data)
+}
+
+// FindAll finds all complaints, newest page first, returned oldest first.
+func (r *SQLiteRepository) FindAll(
+	ctx context.Context,
+	limit, offset int,
+) ([]*domain.Complaint, error) {
+	return r.query(ctx, "", nil, limit, offset)
+}
+
+// FindBySeverity finds complaints by severity.
+func (r *SQLiteRepository) FindBySeverity(
+	ctx context.Context,
+	severity domain.Severity,
+	limit int,
+) ([]*domain.Complaint, error) {
+	return r.query(ctx, "severity = ?", []any{string(severity)}, limit, 0)
+}
+
+// FindUnresolved finds unresolved complaints.
+func (r *SQLiteRepository) FindUnresolved(
+	ctx context.Context,
+	limit int,
+) ([]*domain.Complaint, error) {
+	return r.query(ctx, "resolved = 0", nil, limit, 0)
+}
+
+// Update updates a complaint, rejecting writes made against a stale version.
+func (r *SQLiteRepository) Update(ctx context.Context, complaint *domain.Complaint) error {
+	return updateVersioned(ctx, r.locks, r, complaint)
+}
+
+// Delete deletes a complaint by ID.
+func (r *SQLiteRepository) Delete(ctx context.Context, id domain.ComplaintID) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM complaints WHERE id = ?`, id.String())
+	if err != nil {
+		return fmt.Errorf("failed to delete complaint: %w", err)
+	}
+
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("complaint not found: %s", id.String())
+	}
+
+	r.generation.bump(ctx)
+
+	return nil
+}
+
+// Search searches complaints by text.
+func (r *SQLiteRepository) Search(
+	ctx context.Context,
+	query string,
+	limit int,
+) ([]*domain.Complaint, error) {
+	pattern := "%" + escapeLike(strings.ToLower(query)) + "%"
+
+	return r.query(ctx, `search_text LIKE ? ESCAPE '\'`, []any{pattern}, limit, 0)
+}
+
+// FindByQuery returns up to limit complaints passing the query's filters,
+// in its sort order. Severity, resolved, date and agent, project and session
+// filters become SQL conditions on the indexed columns, and the sort an
+// ORDER BY; the rest is evaluated in memory. Rows are read a page at a time
+// until limit complaints match.
+func (r *SQLiteRepository) FindByQuery(
+	ctx context.Context,
+	q query.Query,
+	limit int,
+) ([]*domain.Complaint, error) {
+	var (
+		conditions []string
+		args       []any
+	)
+
+	for _, filter := range q.Filters {
+		if condition, conditionArgs, ok := sqlCondition(filter); ok {
+			conditions = append(conditions, condition)
+			args = append(args, conditionArgs...)
+		}
+	}
+
+	where := strings.Join(conditions, " AND ")
+	orderBy := sqlOrder(q.Sort)
+
+	var matching []*domain.Complaint
+
+	for offset := 0; ; offset += scanPageSize {
+		page, err := r.selectOrdered(ctx, where, args, orderBy, scanPageSize, offset)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, complaint := range page {
+			if q.Matches(complaint) {
+				matching = append(matching, complaint)
+			}
+		}
+
+		// Rows come in the query's order, so the first limit matches are the answer
+		if len(page) < scanPageSize || len(matching) >= limit {
+			break
+		}
+	}
+
+	return sortedPage(matching, q, limit), nil
+}
+
+// sqlCondition translates a query filter into a WHERE condition, if the
+// columns allow it.
+func sqlCondition(filter query.Filter) (string, []any, bool) {
+	switch f := filter.(type) {
+	case query.SeverityFilter:
+		args := make([]any, len(f))
+		for i, severity := range f {
+			args[i] = string(severity)
+		}
+
+		return "severity IN (?" + strings.Repeat(", ?", len(f)-1) + ")", args, true
+	case query.ResolvedFilter:
+		return "resolved = ?", []any{bool(f)}, true
+	case query.AfterFilter:
+		return "created_at >= ?", []any{time.Time(f).UnixNano()}, true
+	case query.BeforeFilter:
+		return "created_at < ?", []any{time.Time(f).UnixNano()}, true
+	case query.IDFilter:
+		// LIKE ignores ASCII case, as query.MatchPattern does
+		pattern := strings.ReplaceAll(escapeLike(f.Pattern), "*", "%")
+
+		return idColumn(f.Field) + ` LIKE ? ESCAPE '\'`, []any{pattern}, true
+	case query.NotFilter:
+		condition, args, ok := sqlCondition(f.Filter)
+
+		return "NOT (" + condition + ")", args, ok
+	default:
+		return "", nil, false
+	}
+}
+
+// sqlOrder translates a sort into an ORDER BY clause. Occurrences and
+// priority are computed from the JSON document as domain.Complaint does.
+func sqlOrder(sort query.Sort) string {
+	direction := " DESC"
+	if sort.Ascending {
+		direction = " ASC"
+	}
+
+	switch sort.By {
+	case query.SortBySeverity:
+		return severityRank() + direction + ", created_at" + direction
+	case query.SortByOccurrence:
+		return occurrenceCount + direction + ", created_at" + direction
+	case query.SortByPriority:
+		return severityRank() + " * (1 + log2(" + occurrenceCount + "))" + direction + ", created_at" + direction
+	case query.SortByTimestamp:
+	}
+
+	return "created_at" + direction
+}
+
+// occurrenceCount is an SQL expression for domain.Complaint.OccurrenceCount.
+const occurrenceCount = "(1 + COALESCE(json_array_length(data, '$.occurrences'), 0)" +
+	" + COALESCE(json_array_length(data, '$.duplicates'), 0))"
+
+// severityRank returns an SQL expression ranking the severity column by weight.
+func severityRank() string {
+	var rank strings.Builder
+
+	rank.WriteString("CASE severity")
+
+	for _, severity := range []domain.Severity{
+		domain.SeverityLow, domain.SeverityMedium, domain.SeverityHigh, domain.SeverityCritical,
+	} {
+		fmt.Fprintf(&rank, " WHEN '%s' THEN %d", severity, severity.Weight())
+	}
+
+	rank.WriteString(" ELSE 0 END")
+
+	return rank.String()
+}
+
+// idColumn returns the column holding an ID field.
+func idColumn(field domain.ComplaintIDField) string {
+	switch field {
+	case domain.ComplaintFieldAgentID:
+		return "agent_id"
+	case domain.ComplaintFieldProjectID:
+		return "project_id"
+	case domain.ComplaintFieldSessionID:
+		return "session_id"
+	default:
+		return "id"
+	}
+}
+
+// WarmCache is a no-op: SQLite keeps its own page cache.
+func (r *SQLiteRepository) WarmCache(ctx context.Context) error {
+	return nil
+}
+
+// GetCacheStats returns cache statistics.
+func (r *SQLiteRepository) GetCacheStats() CacheStats {
+	return CacheStats{}
+}
+
+// GetFilePath returns the database file holding the complaint.
+func (r *SQLiteRepository) GetFilePath(ctx context.Context, id domain.ComplaintID) (string, error) {
+	return r.dbPath, nil
+}
+
+// GetDocsPath returns the rendered document path, or "" if the complaint was never exported.
+func (r *SQLiteRepository) GetDocsPath(ctx context.Context, id domain.ComplaintID) (string, error) {
+	complaint, err := r.FindByID(ctx, id)
+	if err != nil {
+		return "", err
+	}
+
+	return complaint.DocsPath, nil
+}
+
+// FindBySession finds complaints by session.
+func (r *SQLiteRepository) FindBySession(
+	ctx context.Context,
+	sessionID string,
+	limit int,
+) ([]*domain.Complaint, error) {
+	return r.query(ctx, "session_id = ?", []any{sessionID}, limit, 0)
+}
+
+// FindByProject finds complaints by project.
+func (r *SQLiteRepository) FindByProject(
+	ctx context.Context,
+	projectID string,
+	limit int,
+) ([]*domain.Complaint, error) {
+	return r.query(ctx, "project_id = ?", []any{projectID}, limit, 0)
+}
+
+// FindByAgent finds complaints by agent.
+func (r *SQLiteRepository) FindByAgent(
+	ctx context.Context,
+	agentID string,
+	limit int,
+) ([]*domain.Complaint, error) {
+	return r.query(ctx, "agent_id = ?", []any{agentID}, limit, 0)
+}
+
+// query selects the newest matching page and returns it oldest first,
+// mirroring the ordering FileRepository.FindAll produces.
+func (r *SQLiteRepository) query(
+	ctx context.Context,
+	where string,
+	args []any,
+	limit, offset int,
+) ([]*domain.Complaint, error) {
+	complaints, err := r.selectOrdered(ctx, where, args, "created_at DESC", limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	slices.Reverse(complaints)
+
+	return complaints, nil
+}
+
+// selectOrdered selects a page of matching complaints in the given order.
+func (r *SQLiteRepository) selectOrdered(
+	ctx context.Context,
+	where string,
+	args []any,
+	orderBy string,
+	limit, offset int,
+) ([]*domain.Complaint, error) {
+	ctx, span := r.tracer.Start(ctx, "SQLiteRepository.query")
+	defer span.End()
+
+	stmt := "SELECT data FROM complaints"
+	if where != "" {
+		stmt += " WHERE " + where
+	}
+
+	stmt += " ORDER BY " + orderBy + " LIMIT ? OFFSET ?"
+
+	rows, err := r.db.QueryContext(ctx, stmt, append(args, limit, offset)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query complaints: %w", err)
+	}
+	defer rows.Close()
+
+	var complaints []*domain.Complaint
+
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("failed to scan complaint: %w", err)
+		}
+
+		complaint, err := decodeComplaint(data)
+		if err != nil {
+			return nil, err
+		}
+
+		complaints = append(complaints, complaint)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate complaints: %w", err)
+	}
+
+	return complaints, nil
+}
+
+func decodeComplaint(data string) (*domain.Complaint, error) {
+	var complaint domain.Complaint
+	if err := json.Unmarshal([]byte(data), &complaint); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal complaint: %w", err)
+	}
+
+	return &complaint, nil
+}
+
+// searchText builds the lower-cased haystack matched by Search; it covers
+// the same fields FileRepository.Search inspects.
+func searchText(c *domain.Complaint) string {
+	return strings.ToLower(strings.Join([]string{
+		c.TaskDescription,
+		c.ContextInfo,
+		c.MissingInfo,
+		c.ConfusedBy,
+		c.FutureWishes,
+		c.AgentID.String(),
+		c.ProjectID.String(),
+		c.SessionID.String(),
+	}, "\n"))
+}
+
+// escapeLike escapes LIKE wildcards so user input is matched literally.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
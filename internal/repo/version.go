@@ -0,0 +1,53 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/larsartmann/complaints-mcp/internal/domain"
+	apperrors "github.com/larsartmann/complaints-mcp/internal/errors"
+)
+
+// versionedStore is the part of a repository updateVersioned builds on.
+type versionedStore interface {
+	FindByID(ctx context.Context, id domain.ComplaintID) (*domain.Complaint, error)
+	Save(ctx context.Context, complaint *domain.Complaint) error
+}
+
+// updateVersioned saves complaint only if its Version matches the stored
+// copy, then bumps complaint.Version. The comparison and write happen under
+// the complaint lock, so writers in other processes cannot slip in between.
+// History is append-only: an update that drops recorded events is rejected.
+// A complaint without a readable stored copy is written as-is.
+func updateVersioned(
+	ctx context.Context,
+	l locks,
+	store versionedStore,
+	complaint *domain.Complaint,
+) error {
+	ctx, unlock, err := l.complaint(ctx, complaint.ID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if current, err := store.FindByID(ctx, complaint.ID); err == nil {
+		if current.Version != complaint.Version {
+			return apperrors.NewVersionConflictError(
+				complaint.ID.String(), complaint.Version, current.Version)
+		}
+
+		if len(complaint.History) < len(current.History) {
+			return apperrors.NewValidationError("complaint history is append-only: events cannot be removed")
+		}
+	}
+
+	complaint.Version++
+
+	if err := store.Save(ctx, complaint); err != nil {
+		complaint.Version--
+
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,180 @@
+package repo
+
+import (
+	"context"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	v2 "charm.land/log/v2"
+	"github.com/larsartmann/complaints-mcp/internal/domain"
+)
+
+const (
+	maxWarmWorkers   = 8
+	warmProgressStep = 250
+)
+
+// WarmResult is the outcome of bulk-loading complaints for cache warming.
+type WarmResult struct {
+	Complaints []*domain.Complaint // oldest first
+	Skipped    int                 // unreadable or unparsable entries
+}
+
+// RecentLoader is implemented by backends that can bulk-load their newest complaints.
+type RecentLoader interface {
+	LoadRecent(ctx context.Context, limit int) (WarmResult, error)
+}
+
+// LoadRecent parses the newest limit complaint files with a bounded worker pool.
+// On cancellation it returns whatever was loaded so far together with ctx.Err().
+func (r *FileRepository) LoadRecent(ctx context.Context, limit int) (WarmResult, error) {
+	logger := v2.FromContext(ctx)
+
+	files, err := r.listComplaintFiles()
+	if err != nil {
+		return WarmResult{}, err
+	}
+
+	type candidate struct {
+		name    string
+		modTime time.Time
+	}
+
+	candidates := make([]candidate, 0, len(files))
+
+	for _, file := range files {
+		if !strings.HasSuffix(file.Name(), ".json") {
+			continue
+		}
+
+		info, err := file.Info()
+		if err != nil {
+			continue
+		}
+
+		candidates = append(candidates, candidate{name: file.Name(), modTime: info.ModTime()})
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].modTime.After(candidates[j].modTime)
+	})
+
+	if len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+
+	workers := min(runtime.GOMAXPROCS(0), maxWarmWorkers, max(len(candidates), 1))
+	jobs := make(chan string)
+
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		result WarmResult
+	)
+
+	for range workers {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for name := range jobs {
+				complaint, err := r.loadComplaintFile(name)
+
+				mu.Lock()
+				if err != nil {
+					result.Skipped++
+
+					logger.Debug("Skipping complaint file during warm-up", "file", name, "error", err)
+				} else {
+					result.Complaints = append(result.Complaints, complaint)
+				}
+
+				if done := len(result.Complaints) + result.Skipped; done%warmProgressStep == 0 {
+					logger.Info("Warming complaint cache", "processed", done, "total", len(candidates))
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+feed:
+	for _, c := range candidates {
+		select {
+		case <-ctx.Done():
+			break feed
+		case jobs <- c.name:
+		}
+	}
+
+	close(jobs)
+	wg.Wait()
+
+	sort.Slice(result.Complaints, func(i, j int) bool {
+		return result.Complaints[i].Timestamp.Before(result.Complaints[j].Timestamp)
+	})
+
+	return result, ctx.Err()
+}
+
+// loadComplaintFile reads and parses a single complaint file by name.
+func (r *FileRepository) loadComplaintFile(name string) (*domain.Complaint, error) {
+	id, err := domain.ParseComplaintID(strings.TrimSuffix(name, ".json"))
+	if err != nil {
+		return nil, err
+	}
+
+	return r.FindByID(context.Background(), id)
+}
+
+// LoadRecent loads the newest limit complaints in a single indexed query.
+func (r *SQLiteRepository) LoadRecent(ctx context.Context, limit int) (WarmResult, error) {
+	complaints, err := r.FindAll(ctx, limit, 0)
+	if err != nil {
+		return WarmResult{}, err
+	}
+
+	return WarmResult{Complaints: complaints}, nil
+}
+
+// WarmCache preloads the most recent complaints, up to the cache size.
+// Backends without RecentLoader fall back to FindAll.
+func (r *SimpleCachedRepository) WarmCache(ctx context.Context) error {
+	logger := v2.FromContext(ctx)
+	start := time.Now()
+
+	var (
+		result WarmResult
+		err    error
+	)
+
+	if loader, ok := r.base.(RecentLoader); ok {
+		result, err = loader.LoadRecent(ctx, r.maxSize)
+	} else {
+		result.Complaints, err = r.base.FindAll(ctx, r.maxSize, 0)
+	}
+
+	elapsed := time.Since(start)
+
+	// Keep whatever was loaded, even if warming was cut short
+	r.mu.Lock()
+	for _, complaint := range result.Complaints {
+		r.store(complaint)
+	}
+
+	r.stats.WarmedComplaints = int64(len(result.Complaints))
+	r.stats.WarmSkipped = int64(result.Skipped)
+	r.stats.WarmDurationMs = elapsed.Milliseconds()
+	r.mu.Unlock()
+
+	logger.Info("Complaint cache warm-up finished",
+		"warmed", len(result.Complaints),
+		"skipped", result.Skipped,
+		"elapsed", elapsed,
+		"error", err)
+
+	return err
+}
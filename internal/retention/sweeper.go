@@ -0,0 +1,294 @@
+// Package retention enforces storage.retention_days by periodically pruning old complaints.
+package retention
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+
+	v2 "charm.land/log/v2"
+	"github.com/larsartmann/complaints-mcp/internal/domain"
+	"github.com/larsartmann/complaints-mcp/internal/embed"
+	"github.com/larsartmann/complaints-mcp/internal/repo"
+	"github.com/larsartmann/complaints-mcp/internal/tracing"
+	"github.com/larsartmann/complaints-mcp/internal/types"
+)
+
+const (
+	// archiveDir holds retention bundles, relative to the storage base dir.
+	archiveDir = "archive"
+	// bundleDocsDir holds the documents of archived complaints in a bundle.
+	bundleDocsDir = "docs"
+)
+
+// Policy describes which complaints a sweep removes and how.
+type Policy struct {
+	MaxAge       time.Duration
+	Mode         types.RetentionMode
+	ResolvedOnly bool
+	Interval     time.Duration
+}
+
+// Result reports what a single sweep did.
+type Result struct {
+	Examined    int
+	Pruned      int
+	ArchivePath string // empty unless complaints were archived
+}
+
+// Sweeper removes or archives complaints older than the retention window.
+type Sweeper struct {
+	repo    repo.Repository
+	policy  Policy
+	baseDir string
+	tracer  tracing.Tracer
+	vectors *embed.Store // optional
+}
+
+// NewSweeper creates a sweeper; archives are written under baseDir/archive.
+func NewSweeper(
+	repository repo.Repository,
+	policy Policy,
+	baseDir string,
+	tracer tracing.Tracer,
+) *Sweeper {
+	return &Sweeper{
+		repo:    repository,
+		policy:  policy,
+		baseDir: baseDir,
+		tracer:  tracer,
+	}
+}
+
+// SetEmbeddings makes sweeps drop the vectors of pruned complaints from store.
+func (s *Sweeper) SetEmbeddings(store *embed.Store) {
+	s.vectors = store
+}
+
+// Run sweeps immediately and then every policy.Interval until ctx is cancelled.
+func (s *Sweeper) Run(ctx context.Context) {
+	logger := v2.FromContext(ctx)
+
+	ticker := time.NewTicker(s.policy.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.Sweep(ctx); err != nil && ctx.Err() == nil {
+			logger.Warn("Retention sweep failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sweep runs a single retention pass.
+func (s *Sweeper) Sweep(ctx context.Context) (Result, error) {
+	ctx, span := s.tracer.Start(ctx, "RetentionSweeper.Sweep")
+	defer span.End()
+
+	var result Result
+
+	// Another process sharing the store may be sweeping it too
+	if locker, ok := s.repo.(repo.StoreLocker); ok {
+		unlock, err := locker.LockStore(ctx)
+		if err != nil {
+			return result, fmt.Errorf("failed to lock store: %w", err)
+		}
+		defer unlock()
+	}
+
+	all, err := s.repo.FindAll(ctx, math.MaxInt32, 0)
+	if err != nil {
+		return result, fmt.Errorf("failed to list complaints: %w", err)
+	}
+
+	result.Examined = len(all)
+	now := time.Now()
+	cutoff := now.Add(-s.policy.MaxAge)
+
+	var expired []*domain.Complaint
+
+	for _, complaint := range all {
+		if !s.agingSince(complaint).Before(cutoff) {
+			continue
+		}
+
+		if s.policy.ResolvedOnly && !complaint.IsClosed() {
+			continue
+		}
+
+		expired = append(expired, complaint)
+	}
+
+	if len(expired) > 0 && s.policy.Mode == types.RetentionModeArchive {
+		result.ArchivePath, err = s.archive(expired, now)
+		if err != nil {
+			return result, err
+		}
+	}
+
+	defer func() { s.forget(ctx, expired[:result.Pruned]) }()
+
+	for _, complaint := range expired {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		if err := s.repo.Delete(ctx, complaint.ID); err != nil {
+			return result, fmt.Errorf("failed to prune complaint %s: %w", complaint.ID.String(), err)
+		}
+
+		repo.RemoveDocs(ctx, complaint)
+
+		result.Pruned++
+	}
+
+	span.SetAttribute(ctx, "retention.examined", result.Examined)
+	span.SetAttribute(ctx, "retention.pruned", result.Pruned)
+	span.SetAttribute(ctx, "retention.mode", s.policy.Mode.String())
+
+	if result.Pruned > 0 {
+		v2.FromContext(ctx).Info("Pruned expired complaints",
+			"pruned", result.Pruned,
+			"examined", result.Examined,
+			"mode", s.policy.Mode.String(),
+			"archive", result.ArchivePath)
+	}
+
+	return result, nil
+}
+
+// agingSince returns when a complaint's retention window starts: when it was
+// filed or, when only closed complaints are pruned, when it was closed.
+func (s *Sweeper) agingSince(complaint *domain.Complaint) time.Time {
+	if !s.policy.ResolvedOnly {
+		return complaint.Timestamp
+	}
+
+	if complaint.ResolvedAt != nil {
+		return *complaint.ResolvedAt
+	}
+
+	transitions := complaint.Transitions()
+	for i := len(transitions) - 1; i >= 0; i-- {
+		if transitions[i].To.IsClosed() {
+			return transitions[i].At
+		}
+	}
+
+	return complaint.Timestamp
+}
+
+// archive bundles complaints into a timestamped .tar.gz and returns its path.
+func (s *Sweeper) archive(complaints []*domain.Complaint, now time.Time) (string, error) {
+	dir := filepath.Join(s.baseDir, archiveDir)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create archive directory: %w", err)
+	}
+
+	path := filepath.Join(dir, "complaints-"+now.UTC().Format("20060102-150405.000000000")+".tar.gz")
+
+	var bundle bytes.Buffer
+	if err := writeBundle(&bundle, complaints, now); err != nil {
+		return "", fmt.Errorf("failed to write archive: %w", err)
+	}
+
+	if err := repo.WriteFileAtomic(path, bundle.Bytes()); err != nil {
+		return "", fmt.Errorf("failed to write archive: %w", err)
+	}
+
+	return path, nil
+}
+
+// forget drops the vectors of pruned complaints. Failures are logged: the
+// complaints are gone, and their vectors are dropped on the next search too.
+func (s *Sweeper) forget(ctx context.Context, pruned []*domain.Complaint) {
+	if s.vectors == nil || len(pruned) == 0 {
+		return
+	}
+
+	ids := make([]domain.ComplaintID, len(pruned))
+	for i, complaint := range pruned {
+		ids[i] = complaint.ID
+	}
+
+	err := s.vectors.Forget(ids...)
+	if err == nil {
+		err = s.vectors.Flush()
+	}
+
+	if err != nil {
+		v2.FromContext(ctx).Warn("Failed to drop vectors of pruned complaints", "error", err)
+	}
+}
+
+// writeBundle writes one <id>.json entry per complaint, and a docs/<id><ext>
+// entry per rendered document, as a gzipped tarball.
+func writeBundle(w io.Writer, complaints []*domain.Complaint, modTime time.Time) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	for _, complaint := range complaints {
+		data, err := json.Marshal(complaint)
+		if err != nil {
+			return err
+		}
+
+		if err := writeEntry(tw, complaint.ID.String()+".json", data, modTime); err != nil {
+			return err
+		}
+
+		if complaint.DocsPath == "" {
+			continue
+		}
+
+		doc, err := os.ReadFile(complaint.DocsPath)
+		if os.IsNotExist(err) {
+			continue
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to read document of %s: %w", complaint.ID.String(), err)
+		}
+
+		name := bundleDocsDir + "/" + complaint.ID.String() + filepath.Ext(complaint.DocsPath)
+		if err := writeEntry(tw, name, doc, modTime); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+
+	return gz.Close()
+}
+
+// writeEntry writes one file to a bundle.
+func writeEntry(tw *tar.Writer, name string, data []byte, modTime time.Time) error {
+	err := tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0o644,
+		Size:    int64(len(data)),
+		ModTime: modTime,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = tw.Write(data)
+
+	return err
+}
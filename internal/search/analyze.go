@@ -0,0 +1,116 @@
+package search
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// stopWords are not indexed. They still take up a position, so a phrase
+// such as "missing the flag" only matches words in that order and distance.
+var stopWords = []string{
+	"a", "an", "and", "are", "as", "at", "be", "by", "for", "in", "is", "it",
+	"of", "on", "or", "the", "this", "that", "to", "was", "with",
+}
+
+// token is an indexed word: its stem, its position within the text and
+// the byte range of the original word, for highlighting.
+type token struct {
+	term     string
+	position int
+	start    int
+	end      int
+}
+
+// analyze splits text into lowercase, stemmed words, leaving out stop words.
+func analyze(text string) []token {
+	var (
+		tokens   []token
+		position int
+	)
+
+	for start := 0; start < len(text); {
+		r, size := utf8.DecodeRuneInString(text[start:])
+		if !isWordRune(r) {
+			start += size
+
+			continue
+		}
+
+		end := start + size
+		for end < len(text) {
+			next, width := utf8.DecodeRuneInString(text[end:])
+			if !isWordRune(next) {
+				break
+			}
+
+			end += width
+		}
+
+		word := strings.ToLower(text[start:end])
+		if !slices.Contains(stopWords, word) {
+			tokens = append(tokens, token{term: stem(word), position: position, start: start, end: end})
+		}
+
+		position++
+		start = end
+	}
+
+	return tokens
+}
+
+// Terms returns the stemmed words of text as the index sees them, in order,
+// leaving out stop words.
+func Terms(text string) []string {
+	tokens := analyze(text)
+
+	terms := make([]string, len(tokens))
+	for i, tok := range tokens {
+		terms[i] = tok.term
+	}
+
+	return terms
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// stem reduces an English word to a crude stem by stripping plural and verb
+// endings, so that "variables", "variable" and "configured", "configuring",
+// "configure" meet. It is deliberately light: a wrong stem only costs recall.
+func stem(word string) string {
+	switch {
+	case len(word) > 4 && strings.HasSuffix(word, "ies"):
+		word = word[:len(word)-3] + "y"
+	case strings.HasSuffix(word, "sses"):
+		word = word[:len(word)-2]
+	case len(word) > 3 && strings.HasSuffix(word, "s") &&
+		!strings.HasSuffix(word, "ss") && !strings.HasSuffix(word, "us") && !strings.HasSuffix(word, "is"):
+		word = word[:len(word)-1]
+	}
+
+	switch {
+	case len(word) > 5 && strings.HasSuffix(word, "ing"):
+		word = undouble(word[:len(word)-3])
+	case len(word) > 4 && strings.HasSuffix(word, "ed"):
+		word = undouble(word[:len(word)-2])
+	}
+
+	if len(word) > 4 && strings.HasSuffix(word, "e") {
+		word = word[:len(word)-1]
+	}
+
+	return word
+}
+
+// undouble turns "runn" back into "run" after a suffix was stripped.
+func undouble(word string) string {
+	n := len(word)
+	if n < 3 || word[n-1] != word[n-2] || strings.ContainsRune("aeiouslz", rune(word[n-1])) {
+		return word
+	}
+
+	return word[:n-1]
+}
@@ -0,0 +1,84 @@
+package search
+
+import "unicode/utf8"
+
+// fuzzyWeight discounts a fuzzy match one edit away against an exact one;
+// each further edit divides it again.
+const fuzzyWeight = 0.4
+
+const (
+	// DefaultFuzziness is the edit distance fuzzy searches allow unless configured.
+	DefaultFuzziness = 2
+	// MaxFuzziness is the largest edit distance a query may allow.
+	MaxFuzziness = 3
+)
+
+// allowedEdits caps the edit distance for a query word by its length, so
+// that short words, where one edit already makes another word, stay exact.
+func allowedEdits(word string, fuzziness int) int {
+	n := utf8.RuneCountInString(word)
+
+	switch {
+	case n < 3:
+		return 0
+	case n < 6:
+		return min(fuzziness, 1)
+	case n < 9:
+		return min(fuzziness, 2)
+	default:
+		return min(fuzziness, MaxFuzziness)
+	}
+}
+
+// editDistance returns the optimal string alignment distance between a and
+// b: the insertions, deletions, substitutions and transpositions of adjacent
+// letters turning one into the other, as in "reposiotry". Distances above
+// limit are reported as limit+1.
+func editDistance(a, b string, limit int) int {
+	left, right := []rune(a), []rune(b)
+	if abs(len(left)-len(right)) > limit {
+		return limit + 1
+	}
+
+	// Three rows of the dynamic programming table: two back, previous, current
+	before, prev, cur := make([]int, len(right)+1), make([]int, len(right)+1), make([]int, len(right)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(left); i++ {
+		cur[0] = i
+		rowMin := cur[0]
+
+		for j := 1; j <= len(right); j++ {
+			cost := 1
+			if left[i-1] == right[j-1] {
+				cost = 0
+			}
+
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+
+			if i > 1 && j > 1 && left[i-1] == right[j-2] && left[i-2] == right[j-1] {
+				cur[j] = min(cur[j], before[j-2]+1)
+			}
+
+			rowMin = min(rowMin, cur[j])
+		}
+
+		if rowMin > limit {
+			return limit + 1
+		}
+
+		before, prev, cur = prev, cur, before
+	}
+
+	return min(prev[len(right)], limit+1)
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+
+	return n
+}
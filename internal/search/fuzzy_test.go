@@ -0,0 +1,48 @@
+package search
+
+import "testing"
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b  string
+		limit int
+		want  int
+	}{
+		{a: "cobra", b: "cobra", limit: 2, want: 0},
+		{a: "cobar", b: "cobra", limit: 2, want: 1},
+		{a: "reposiotry", b: "repository", limit: 2, want: 1},
+		{a: "confg", b: "config", limit: 2, want: 1},
+		{a: "documnt", b: "document", limit: 2, want: 1},
+		{a: "kitten", b: "sitting", limit: 3, want: 3},
+		{a: "kitten", b: "sitting", limit: 1, want: 2},
+		{a: "go", b: "golang", limit: 2, want: 3},
+		{a: "naïve", b: "naive", limit: 2, want: 1},
+	}
+
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b, tt.limit); got != tt.want {
+			t.Errorf("editDistance(%q, %q, %d) = %d, want %d", tt.a, tt.b, tt.limit, got, tt.want)
+		}
+	}
+}
+
+func TestAllowedEdits(t *testing.T) {
+	tests := []struct {
+		word      string
+		fuzziness int
+		want      int
+	}{
+		{word: "go", fuzziness: 2, want: 0},
+		{word: "cobra", fuzziness: 2, want: 1},
+		{word: "cobra", fuzziness: 0, want: 0},
+		{word: "repository", fuzziness: 2, want: 2},
+		{word: "repository", fuzziness: 3, want: 3},
+		{word: "config", fuzziness: 3, want: 2},
+	}
+
+	for _, tt := range tests {
+		if got := allowedEdits(tt.word, tt.fuzziness); got != tt.want {
+			t.Errorf("allowedEdits(%q, %d) = %d, want %d", tt.word, tt.fuzziness, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,390 @@
+// Package search keeps an in-memory full-text index of complaints, evaluates
+// parsed queries against it and ranks matches with BM25.
+package search
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/larsartmann/complaints-mcp/internal/domain"
+	"github.com/larsartmann/complaints-mcp/internal/query"
+)
+
+const (
+	// bm25K1 and bm25B are the usual BM25 term-frequency saturation and
+	// length normalization parameters.
+	bm25K1 = 1.2
+	bm25B  = 0.75
+
+	// minPrefixLength is the shortest query word that also matches longer
+	// indexed words, so that "env" finds "environment".
+	minPrefixLength = 3
+	// prefixWeight discounts a prefix match against an exact one.
+	prefixWeight = 0.5
+
+	// fieldGap separates the positions of consecutive fields, so that a
+	// phrase never spans two of them.
+	fieldGap = 100
+)
+
+// Hit is a complaint matched by a search, best first.
+type Hit struct {
+	ID      domain.ComplaintID
+	Score   float64
+	Field   string // field the snippet was taken from
+	Snippet string // excerpt with matched words wrapped in **
+}
+
+// Index is a full-text index of complaints. It is safe for concurrent use.
+type Index struct {
+	mu          sync.RWMutex
+	docs        map[domain.ComplaintID]*document
+	postings    map[string]map[domain.ComplaintID]*posting
+	totalLength int
+}
+
+// document is an indexed complaint.
+type document struct {
+	complaint *domain.Complaint // a copy, for evaluating query filters
+	fields    []indexedField
+	terms     []string // distinct terms, for removal
+	length    int      // number of indexed words
+}
+
+// indexedField is the analyzed text of one complaint field.
+type indexedField struct {
+	name   string
+	text   string
+	boost  float64
+	tokens []token // positions are offset to be unique within the document
+}
+
+// posting records where a term occurs in a document.
+type posting struct {
+	positions []int
+	frequency float64 // occurrences, weighted by the boost of their field
+}
+
+// NewIndex creates an empty index.
+func NewIndex() *Index {
+	return &Index{
+		docs:     make(map[domain.ComplaintID]*document),
+		postings: make(map[string]map[domain.ComplaintID]*posting),
+	}
+}
+
+// Len returns the number of indexed complaints.
+func (ix *Index) Len() int {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	return len(ix.docs)
+}
+
+// Add indexes a complaint, replacing any earlier version of it.
+func (ix *Index) Add(complaint *domain.Complaint) {
+	doc := newDocument(complaint)
+
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	ix.remove(complaint.ID)
+
+	ix.docs[complaint.ID] = doc
+	ix.totalLength += doc.length
+
+	for _, field := range doc.fields {
+		for _, tok := range field.tokens {
+			docs, ok := ix.postings[tok.term]
+			if !ok {
+				docs = make(map[domain.ComplaintID]*posting)
+				ix.postings[tok.term] = docs
+			}
+
+			p, ok := docs[complaint.ID]
+			if !ok {
+				p = &posting{}
+				docs[complaint.ID] = p
+				doc.terms = append(doc.terms, tok.term)
+			}
+
+			p.positions = append(p.positions, tok.position)
+			p.frequency += field.boost
+		}
+	}
+}
+
+// Remove drops a complaint from the index.
+func (ix *Index) Remove(id domain.ComplaintID) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	ix.remove(id)
+}
+
+func (ix *Index) remove(id domain.ComplaintID) {
+	doc, ok := ix.docs[id]
+	if !ok {
+		return
+	}
+
+	for _, term := range doc.terms {
+		delete(ix.postings[term], id)
+
+		if len(ix.postings[term]) == 0 {
+			delete(ix.postings, term)
+		}
+	}
+
+	ix.totalLength -= doc.length
+	delete(ix.docs, id)
+}
+
+// Search returns up to limit complaints matching q, best first.
+// Words match the same word in any indexed field, ignoring case and simple
+// English endings; words of three or more letters also match longer words
+// they start, at a discount, and with q.Fuzziness words within that many
+// edits, at a larger one. A word that splits into several, such as
+// "go-git", and a quoted phrase must occur as a phrase. Complaints that fail
+// a filter, contain a negated term, or miss a phrase are left out, as are
+// those with none of the words when q has any. Without words or phrases,
+// every complaint passing the filters matches, newest first.
+func (ix *Index) Search(q query.Query, limit int) []Hit {
+	if limit <= 0 {
+		return nil
+	}
+
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	scores := make(map[domain.ComplaintID]float64)
+	matched := make(map[domain.ComplaintID][]string)
+	phrases := make(map[domain.ComplaintID]int)
+	excluded := make(map[domain.ComplaintID]bool)
+
+	phraseCount := 0
+
+	for _, c := range clausesOf(q) {
+		switch {
+		case c.negated:
+			for id := range ix.phraseMatches(c.terms) {
+				excluded[id] = true
+			}
+		case c.phrase:
+			phraseCount++
+
+			for id := range ix.phraseMatches(c.terms) {
+				phrases[id]++
+
+				for _, tok := range c.terms {
+					scores[id] += ix.bm25(tok.term, id)
+					matched[id] = append(matched[id], tok.term)
+				}
+			}
+		default:
+			ix.scoreWord(c.terms[0].term, q.Fuzziness, scores, matched)
+		}
+	}
+
+	if !q.HasText() {
+		for id := range ix.docs {
+			scores[id] = 0
+		}
+	}
+
+	hits := make([]Hit, 0, len(scores))
+
+	for id, score := range scores {
+		if phrases[id] < phraseCount || excluded[id] || !q.Matches(ix.docs[id].complaint) {
+			continue
+		}
+
+		hits = append(hits, Hit{ID: id, Score: score})
+	}
+
+	slices.SortFunc(hits, func(a, b Hit) int {
+		if c := cmp.Compare(b.Score, a.Score); c != 0 {
+			return c
+		}
+
+		return ix.docs[b.ID].complaint.Timestamp.Compare(ix.docs[a.ID].complaint.Timestamp)
+	})
+
+	hits = hits[:min(len(hits), limit)]
+
+	for i := range hits {
+		hits[i].Field, hits[i].Snippet = ix.docs[hits[i].ID].snippet(matched[hits[i].ID])
+	}
+
+	return hits
+}
+
+// scoreWord adds the score of a query word to every complaint containing
+// it or, at a discount, a longer word it is a prefix of, or with fuzziness,
+// a word within that many edits of it. A complaint with several such words
+// is only scored for the best one.
+func (ix *Index) scoreWord(
+	word string,
+	fuzziness int,
+	scores map[domain.ComplaintID]float64,
+	matched map[domain.ComplaintID][]string,
+) {
+	best := make(map[domain.ComplaintID]float64)
+	edits := allowedEdits(word, fuzziness)
+
+	for term, docs := range ix.postings {
+		weight := 1.0
+
+		switch {
+		case term == word:
+		case len(word) >= minPrefixLength && strings.HasPrefix(term, word):
+			weight = prefixWeight
+		case edits > 0:
+			distance := editDistance(word, term, edits)
+			if distance > edits {
+				continue
+			}
+
+			weight = fuzzyWeight / float64(distance)
+		default:
+			continue
+		}
+
+		for id := range docs {
+			best[id] = max(best[id], weight*ix.bm25(term, id))
+			matched[id] = append(matched[id], term)
+		}
+	}
+
+	for id, score := range best {
+		scores[id] += score
+	}
+}
+
+// phraseMatches returns the complaints in which terms occur at consecutive
+// positions, allowing for the stop words that were left out in between.
+func (ix *Index) phraseMatches(terms []token) map[domain.ComplaintID]struct{} {
+	found := make(map[domain.ComplaintID]struct{})
+
+	first, ok := ix.postings[terms[0].term]
+	if !ok {
+		return found
+	}
+
+candidates:
+	for id, p := range first {
+		for _, start := range p.positions {
+			if ix.phraseAt(id, terms, start-terms[0].position) {
+				found[id] = struct{}{}
+
+				continue candidates
+			}
+		}
+	}
+
+	return found
+}
+
+func (ix *Index) phraseAt(id domain.ComplaintID, terms []token, offset int) bool {
+	for _, term := range terms[1:] {
+		p, ok := ix.postings[term.term][id]
+		if !ok || !slices.Contains(p.positions, offset+term.position) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// bm25 scores how well term describes the complaint, given how common the
+// term is across complaints and how long the complaint is.
+func (ix *Index) bm25(term string, id domain.ComplaintID) float64 {
+	docs := ix.postings[term]
+
+	p, ok := docs[id]
+	if !ok {
+		return 0
+	}
+
+	n, containing := float64(len(ix.docs)), float64(len(docs))
+	idf := math.Log(1 + (n-containing+0.5)/(containing+0.5))
+
+	average := float64(ix.totalLength) / n
+	norm := 1 - bm25B + bm25B*float64(ix.docs[id].length)/average
+
+	return idf * p.frequency * (bm25K1 + 1) / (p.frequency + bm25K1*norm)
+}
+
+// clause is an analyzed query term: a single word, or a phrase of several.
+type clause struct {
+	terms   []token
+	phrase  bool
+	negated bool
+}
+
+// clausesOf analyzes the text terms of a query. Terms made only of stop
+// words are dropped.
+func clausesOf(q query.Query) []clause {
+	var clauses []clause
+
+	for _, term := range q.Terms {
+		tokens := analyze(term.Text)
+		if len(tokens) == 0 {
+			continue
+		}
+
+		clauses = append(clauses, clause{
+			terms:   tokens,
+			phrase:  term.Phrase || len(tokens) > 1,
+			negated: term.Negated,
+		})
+	}
+
+	return clauses
+}
+
+// newDocument analyzes the searchable fields of a complaint.
+func newDocument(c *domain.Complaint) *document {
+	doc := &document{complaint: c.Clone()}
+	offset := 0
+
+	add := func(name, text string, boost float64) {
+		tokens := analyze(text)
+		if len(tokens) == 0 {
+			return
+		}
+
+		for i := range tokens {
+			tokens[i].position += offset
+		}
+
+		offset = tokens[len(tokens)-1].position + fieldGap
+		doc.length += len(tokens)
+		doc.fields = append(doc.fields, indexedField{name: name, text: text, boost: boost, tokens: tokens})
+	}
+
+	add("task_description", c.TaskDescription, 2)
+	add("context_info", c.ContextInfo, 1)
+	add("missing_info", c.MissingInfo, 1)
+	add("confused_by", c.ConfusedBy, 1)
+	add("future_wishes", c.FutureWishes, 1)
+	add("tags", strings.Join(c.Tags, " "), 1.5)
+	add("categories", strings.Join(c.Categories, " "), 1.5)
+
+	for _, ref := range c.References {
+		add("references", ref.String(), 1)
+	}
+
+	for _, comment := range c.Comments {
+		add("comments", comment.Body, 1)
+	}
+
+	add("agent_id", c.AgentID.String(), 1)
+	add("project_id", c.ProjectID.String(), 1)
+	add("session_id", c.SessionID.String(), 1)
+
+	return doc
+}
@@ -0,0 +1,222 @@
+package search
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/larsartmann/complaints-mcp/internal/domain"
+	"github.com/larsartmann/complaints-mcp/internal/query"
+)
+
+func TestStem(t *testing.T) {
+	tests := map[string]string{
+		"variables":   "variabl",
+		"variable":    "variabl",
+		"configured":  "configur",
+		"configuring": "configur",
+		"configure":   "configur",
+		"running":     "run",
+		"libraries":   "library",
+		"classes":     "class",
+		"status":      "status",
+		"env":         "env",
+	}
+
+	for word, want := range tests {
+		if got := stem(word); got != want {
+			t.Errorf("stem(%q) = %q, want %q", word, got, want)
+		}
+	}
+}
+
+func newComplaint(t *testing.T, task, missing string, age time.Duration) *domain.Complaint {
+	t.Helper()
+
+	id, err := domain.NewComplaintID()
+	if err != nil {
+		t.Fatalf("NewComplaintID() error = %v", err)
+	}
+
+	return &domain.Complaint{
+		ID:              id,
+		TaskDescription: task,
+		MissingInfo:     missing,
+		Timestamp:       time.Now().Add(-age),
+	}
+}
+
+func mustParse(t *testing.T, input string) query.Query {
+	t.Helper()
+
+	q, err := query.Parse(input)
+	if err != nil {
+		t.Fatalf("Parse(%q) error = %v", input, err)
+	}
+
+	return q
+}
+
+func hitIDs(hits []Hit) []domain.ComplaintID {
+	ids := make([]domain.ComplaintID, len(hits))
+	for i, hit := range hits {
+		ids[i] = hit.ID
+	}
+
+	return ids
+}
+
+func TestIndex_SearchRanksBestMatchFirst(t *testing.T) {
+	index := NewIndex()
+
+	envOnly := newComplaint(t, "Deploying the service", "Which environment to deploy to", 0)
+	both := newComplaint(t, "Configuring the server", "Environment variables are not documented", time.Hour)
+	unrelated := newComplaint(t, "Writing tests", "No example for table tests", 0)
+
+	for _, c := range []*domain.Complaint{envOnly, both, unrelated} {
+		index.Add(c)
+	}
+
+	hits := index.Search(mustParse(t, "env var"), 10)
+	if len(hits) != 2 {
+		t.Fatalf("Search() = %v, want 2 hits", hitIDs(hits))
+	}
+
+	if hits[0].ID != both.ID || hits[1].ID != envOnly.ID {
+		t.Errorf("Search() order = %v, want [%v %v]", hitIDs(hits), both.ID, envOnly.ID)
+	}
+
+	if hits[0].Field != "missing_info" {
+		t.Errorf("Field = %q, want missing_info", hits[0].Field)
+	}
+
+	if want := "**Environment** **variables** are not documented"; hits[0].Snippet != want {
+		t.Errorf("Snippet = %q, want %q", hits[0].Snippet, want)
+	}
+}
+
+func TestIndex_SearchPhrase(t *testing.T) {
+	index := NewIndex()
+
+	ordered := newComplaint(t, "Missing the config flag", "", 0)
+	scattered := newComplaint(t, "Flag missing from config", "", 0)
+
+	index.Add(ordered)
+	index.Add(scattered)
+
+	if hits := index.Search(mustParse(t, "config flag"), 10); len(hits) != 2 {
+		t.Errorf("word search = %v, want both complaints", hitIDs(hits))
+	}
+
+	hits := index.Search(mustParse(t, `"missing the config flag"`), 10)
+	if len(hits) != 1 || hits[0].ID != ordered.ID {
+		t.Errorf("phrase search = %v, want [%v]", hitIDs(hits), ordered.ID)
+	}
+
+	if hits := index.Search(mustParse(t, `"flag config"`), 10); len(hits) != 0 {
+		t.Errorf("reversed phrase = %v, want no hits", hitIDs(hits))
+	}
+}
+
+func TestIndex_UpdateAndRemove(t *testing.T) {
+	index := NewIndex()
+
+	complaint := newComplaint(t, "Cobra flags are confusing", "", 0)
+	index.Add(complaint)
+
+	updated := *complaint
+	updated.TaskDescription = "Viper keys are confusing"
+	index.Add(&updated)
+
+	if index.Len() != 1 {
+		t.Fatalf("Len() = %d, want 1", index.Len())
+	}
+
+	if hits := index.Search(mustParse(t, "cobra"), 10); len(hits) != 0 {
+		t.Errorf("stale text still matches: %v", hitIDs(hits))
+	}
+
+	if hits := index.Search(mustParse(t, "viper"), 10); len(hits) != 1 {
+		t.Errorf("updated text does not match: %v", hitIDs(hits))
+	}
+
+	index.Remove(complaint.ID)
+
+	if hits := index.Search(mustParse(t, "viper"), 10); len(hits) != 0 || index.Len() != 0 {
+		t.Errorf("removed complaint still indexed: %v", hitIDs(hits))
+	}
+}
+
+func TestIndex_SearchFiltersAndExclusions(t *testing.T) {
+	index := NewIndex()
+
+	high := newComplaint(t, "Cobra flags are undocumented", "", 0)
+	high.Severity = domain.SeverityHigh
+	low := newComplaint(t, "Cobra completion is undocumented", "", time.Hour)
+	low.Severity = domain.SeverityLow
+
+	index.Add(high)
+	index.Add(low)
+
+	tests := []struct {
+		query string
+		want  []domain.ComplaintID
+	}{
+		{query: "cobra", want: []domain.ComplaintID{high.ID, low.ID}},
+		{query: "severity:high cobra", want: []domain.ComplaintID{high.ID}},
+		{query: "-severity:high", want: []domain.ComplaintID{low.ID}},
+		{query: "cobra -completion", want: []domain.ComplaintID{high.ID}},
+		{query: "severity:low,high", want: []domain.ComplaintID{high.ID, low.ID}},
+		{query: "severity:critical", want: nil},
+	}
+
+	for _, tt := range tests {
+		hits := index.Search(mustParse(t, tt.query), 10)
+		if got := hitIDs(hits); !slices.Equal(got, tt.want) {
+			t.Errorf("Search(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestIndex_SearchFuzzy(t *testing.T) {
+	index := NewIndex()
+
+	cobra := newComplaint(t, "Cobra flags are confusing", "", 0)
+	typo := newComplaint(t, "Cobar flags are confusing", "", time.Hour)
+	repository := newComplaint(t, "The repository layer is slow", "", 0)
+
+	for _, c := range []*domain.Complaint{cobra, typo, repository} {
+		index.Add(c)
+	}
+
+	exact := mustParse(t, "cobar")
+	if got := hitIDs(index.Search(exact, 10)); !slices.Equal(got, []domain.ComplaintID{typo.ID}) {
+		t.Errorf("exact search = %v, want [%v]", got, typo.ID)
+	}
+
+	fuzzy := exact
+	fuzzy.Fuzziness = 2
+
+	hits := index.Search(fuzzy, 10)
+	if got := hitIDs(hits); !slices.Equal(got, []domain.ComplaintID{typo.ID, cobra.ID}) {
+		t.Fatalf("fuzzy search = %v, want exact match %v before %v", got, typo.ID, cobra.ID)
+	}
+
+	if hits[0].Score <= hits[1].Score {
+		t.Errorf("fuzzy match scored %v, not below exact match %v", hits[1].Score, hits[0].Score)
+	}
+
+	if hits[1].Snippet != "**Cobra** flags are confusing" {
+		t.Errorf("fuzzy snippet = %q", hits[1].Snippet)
+	}
+
+	misspelled := mustParse(t, "reposiotry")
+	if hits := index.Search(misspelled, 10); len(hits) != 0 {
+		t.Errorf("exact search for a typo = %v, want no hits", hitIDs(hits))
+	}
+
+	misspelled.Fuzziness = 1
+	if got := hitIDs(index.Search(misspelled, 10)); !slices.Equal(got, []domain.ComplaintID{repository.ID}) {
+		t.Errorf("fuzzy search for a typo = %v, want [%v]", got, repository.ID)
+	}
+}
@@ -0,0 +1,86 @@
+package search
+
+import (
+	"slices"
+	"strings"
+)
+
+// snippetWords is the number of indexed words a snippet spans.
+const snippetWords = 20
+
+// snippet picks the field with the most matched words and returns an
+// excerpt of it around the densest run of matches, with each matched word
+// wrapped in ** as in Markdown bold.
+func (d *document) snippet(terms []string) (string, string) {
+	var (
+		best       *indexedField
+		bestScore  float64
+		bestAnchor int
+	)
+
+	for i := range d.fields {
+		field := &d.fields[i]
+
+		for anchor := range field.tokens {
+			if !slices.Contains(terms, field.tokens[anchor].term) {
+				continue
+			}
+
+			count := 0
+
+			for _, tok := range field.tokens[anchor:min(anchor+snippetWords, len(field.tokens))] {
+				if slices.Contains(terms, tok.term) {
+					count++
+				}
+			}
+
+			if score := float64(count) * field.boost; score > bestScore {
+				best, bestScore, bestAnchor = field, score, anchor
+			}
+		}
+	}
+
+	if best == nil {
+		return "", ""
+	}
+
+	return best.name, best.excerpt(terms, max(0, bestAnchor-snippetWords/4))
+}
+
+// excerpt renders snippetWords words of the field from the first-th on.
+func (f *indexedField) excerpt(terms []string, first int) string {
+	last := min(first+snippetWords, len(f.tokens))
+	tokens := f.tokens[first:last]
+	start, end := 0, len(f.text)
+
+	var b strings.Builder
+
+	if first > 0 {
+		start = tokens[0].start
+		b.WriteString("… ")
+	}
+
+	if last < len(f.tokens) {
+		end = tokens[len(tokens)-1].end
+	}
+
+	written := start
+
+	for _, tok := range tokens {
+		if !slices.Contains(terms, tok.term) {
+			continue
+		}
+
+		b.WriteString(f.text[written:tok.start])
+		b.WriteString("**" + f.text[tok.start:tok.end] + "**")
+		written = tok.end
+	}
+
+	b.WriteString(f.text[written:end])
+
+	if end < len(f.text) {
+		b.WriteString(" …")
+	}
+
+	return strings.Join(strings.Fields(b.String()), " ")
+}
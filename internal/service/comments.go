@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/larsartmann/complaints-mcp/internal/domain"
+)
+
+// AddComment appends a comment by author to a complaint's discussion thread
+// if it is still at expectedVersion, and returns the comment and the updated
+// complaint. An expectedVersion of 0 skips the check.
+func (s *ComplaintService) AddComment(
+	ctx context.Context,
+	id domain.ComplaintID,
+	author string,
+	authorType domain.CommentAuthorType,
+	body string,
+	expectedVersion uint64,
+) (domain.Comment, *domain.Complaint, error) {
+	comment, err := domain.NewComment(author, authorType, body)
+	if err != nil {
+		return domain.Comment{}, nil, fmt.Errorf("invalid comment: %w", err)
+	}
+
+	event := domain.Event{Actor: author, Action: domain.EventCommented}
+
+	complaint, err := s.modify(ctx, id, expectedVersion, event, func(complaint *domain.Complaint) error {
+		complaint.AddComment(comment)
+
+		return nil
+	})
+	if err != nil {
+		return domain.Comment{}, nil, err
+	}
+
+	return comment, complaint, nil
+}
+
+// ListComments returns a complaint's discussion thread, oldest first.
+func (s *ComplaintService) ListComments(
+	ctx context.Context,
+	id domain.ComplaintID,
+) ([]domain.Comment, error) {
+	complaint, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find complaint: %w", err)
+	}
+
+	return complaint.Comments, nil
+}
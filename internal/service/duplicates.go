@@ -0,0 +1,119 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/larsartmann/complaints-mcp/internal/domain"
+)
+
+const (
+	// maxSimilar bounds the likely duplicates recorded on a new complaint.
+	maxSimilar = 5
+	// maxDuplicateDepth bounds how far mark_duplicate follows duplicate_of links.
+	maxDuplicateDepth = 10
+)
+
+// findSimilar scores the open complaints in complaint's project against it
+// and returns the likely duplicates, most similar first. A failed lookup
+// only means no duplicates are suggested.
+func (s *ComplaintService) findSimilar(ctx context.Context, complaint *domain.Complaint) []domain.Similar {
+	candidates, err := s.repo.FindByProject(ctx, complaint.ProjectID.String(), math.MaxInt)
+	if err != nil {
+		s.logger.Warn("Failed to look for duplicate complaints", "error", err, "project", complaint.ProjectID)
+
+		return nil
+	}
+
+	var similar []domain.Similar
+
+	for _, candidate := range candidates {
+		if candidate.ID == complaint.ID || candidate.IsClosed() {
+			continue
+		}
+
+		if score := domain.Similarity(complaint, candidate); score >= domain.LikelyDuplicateScore {
+			similar = append(similar, domain.Similar{ID: candidate.ID, Score: score})
+		}
+	}
+
+	slices.SortStableFunc(similar, func(a, b domain.Similar) int {
+		return cmp.Compare(b.Score, a.Score)
+	})
+
+	if len(similar) > maxSimilar {
+		similar = similar[:maxSimilar]
+	}
+
+	return similar
+}
+
+// MarkDuplicate closes a complaint as a duplicate of canonicalID on behalf
+// of actor, if it is still at expectedVersion, and links it from the
+// canonical complaint. If canonicalID is itself a duplicate, the complaint
+// is linked to the complaint that one duplicates instead. An empty reason
+// defaults to "duplicate of <id>".
+func (s *ComplaintService) MarkDuplicate(
+	ctx context.Context,
+	id, canonicalID domain.ComplaintID,
+	actor, reason string,
+	expectedVersion uint64,
+) (*domain.Complaint, error) {
+	if actor == "" {
+		return nil, errors.New("actor cannot be empty")
+	}
+
+	canonical, err := s.findCanonical(ctx, canonicalID)
+	if err != nil {
+		return nil, err
+	}
+
+	if reason == "" {
+		reason = "duplicate of " + canonical.ID.String()
+	}
+
+	event := domain.Event{Actor: actor, Action: domain.EventMarkedDuplicate, Reason: reason}
+
+	duplicate, err := s.modify(ctx, id, expectedVersion, event, func(complaint *domain.Complaint) error {
+		return complaint.MarkDuplicateOf(canonical, actor, reason)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	link := domain.Event{Actor: actor, Action: domain.EventDuplicateLinked, Reason: "duplicate " + id.String()}
+
+	_, err = s.modify(ctx, canonical.ID, 0, link, func(complaint *domain.Complaint) error {
+		complaint.LinkDuplicate(id)
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("marked %s as a duplicate but failed to link it from %s: %w", id, canonical.ID, err)
+	}
+
+	return duplicate, nil
+}
+
+// findCanonical follows duplicate_of links from id to the complaint the
+// others duplicate.
+func (s *ComplaintService) findCanonical(ctx context.Context, id domain.ComplaintID) (*domain.Complaint, error) {
+	for range maxDuplicateDepth {
+		complaint, err := s.repo.FindByID(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find complaint %s: %w", id, err)
+		}
+
+		if complaint.DuplicateOf.IsZero() {
+			return complaint, nil
+		}
+
+		id = complaint.DuplicateOf
+	}
+
+	return nil, fmt.Errorf("duplicate_of links from %s are more than %d deep", id, maxDuplicateDepth)
+}
@@ -0,0 +1,36 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/larsartmann/complaints-mcp/internal/domain"
+)
+
+// toolKey is the context key for the tool a request came in through.
+type toolKey struct{}
+
+// WithTool returns a context that attributes the changes made with it to
+// tool, e.g. an MCP tool or CLI command name, in complaint histories.
+func WithTool(ctx context.Context, tool string) context.Context {
+	return context.WithValue(ctx, toolKey{}, tool)
+}
+
+func toolFromContext(ctx context.Context) string {
+	tool, _ := ctx.Value(toolKey{}).(string)
+
+	return tool
+}
+
+// GetComplaintHistory returns every recorded change to a complaint, oldest first.
+func (s *ComplaintService) GetComplaintHistory(
+	ctx context.Context,
+	id domain.ComplaintID,
+) ([]domain.Event, error) {
+	complaint, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find complaint: %w", err)
+	}
+
+	return complaint.History, nil
+}
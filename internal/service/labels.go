@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/larsartmann/complaints-mcp/internal/domain"
+)
+
+// TagComplaint adds and removes free-form tags on a complaint on behalf of
+// actor, if it is still at expectedVersion. An expectedVersion of 0 skips the check.
+func (s *ComplaintService) TagComplaint(
+	ctx context.Context,
+	id domain.ComplaintID,
+	actor string,
+	add, remove []string,
+	expectedVersion uint64,
+) (*domain.Complaint, error) {
+	if actor == "" {
+		return nil, errors.New("actor cannot be empty")
+	}
+
+	add = domain.NormalizeLabels(add)
+	remove = domain.NormalizeLabels(remove)
+
+	event := domain.Event{Actor: actor, Action: domain.EventTagged}
+
+	return s.modify(ctx, id, expectedVersion, event, func(complaint *domain.Complaint) error {
+		tags := slices.DeleteFunc(append(complaint.Tags, add...), func(tag string) bool {
+			return slices.Contains(remove, tag)
+		})
+		complaint.Tags = domain.NormalizeLabels(tags)
+
+		if err := complaint.Validate(); err != nil {
+			return fmt.Errorf("invalid tags: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// CategorizeComplaint replaces a complaint's categories on behalf of actor,
+// if it is still at expectedVersion. An expectedVersion of 0 skips the check.
+func (s *ComplaintService) CategorizeComplaint(
+	ctx context.Context,
+	id domain.ComplaintID,
+	actor string,
+	categories []string,
+	expectedVersion uint64,
+) (*domain.Complaint, error) {
+	if actor == "" {
+		return nil, errors.New("actor cannot be empty")
+	}
+
+	categories = domain.NormalizeLabels(categories)
+
+	event := domain.Event{Actor: actor, Action: domain.EventCategorized}
+
+	return s.modify(ctx, id, expectedVersion, event, func(complaint *domain.Complaint) error {
+		complaint.Categories = categories
+
+		if err := complaint.Validate(); err != nil {
+			return fmt.Errorf("invalid categories: %w", err)
+		}
+
+		if err := complaint.ValidateCategories(s.categories); err != nil {
+			return fmt.Errorf("invalid categories: %w", err)
+		}
+
+		return nil
+	})
+}
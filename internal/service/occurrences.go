@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/larsartmann/complaints-mcp/internal/domain"
+)
+
+// ConfirmComplaint records that agentName hit the problem a complaint
+// describes again in sessionName. Confirming a duplicate counts towards the
+// complaint it duplicates, which is returned instead. Each session is only
+// counted once per complaint. A non-zero expectedVersion must match the
+// version of the complaint the occurrence is counted on.
+func (s *ComplaintService) ConfirmComplaint(
+	ctx context.Context,
+	id domain.ComplaintID,
+	agentName, sessionName string,
+	expectedVersion uint64,
+) (*domain.Complaint, error) {
+	agentID, err := domain.ParseAgentID(agentName)
+	if err != nil {
+		return nil, fmt.Errorf("invalid agent name: %w", err)
+	}
+
+	sessionID, err := domain.ParseSessionID(sessionName)
+	if err != nil {
+		return nil, fmt.Errorf("invalid session name: %w", err)
+	}
+
+	canonical, err := s.findCanonical(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if canonical.IsClosed() {
+		return nil, fmt.Errorf("complaint %s is %s; reopen it to report that the problem persists",
+			canonical.ID, canonical.ResolutionState)
+	}
+
+	event := domain.Event{Actor: agentName, Action: domain.EventConfirmed}
+
+	return s.modify(ctx, canonical.ID, expectedVersion, event, func(complaint *domain.Complaint) error {
+		complaint.Confirm(agentID, sessionID, time.Now())
+
+		return nil
+	})
+}
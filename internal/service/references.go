@@ -0,0 +1,90 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/larsartmann/complaints-mcp/internal/domain"
+)
+
+// resolveReferences anchors refs to the repository at root. Absolute paths
+// inside root are made relative to it, and every referenced file must exist
+// and be long enough for its line range. Without a root only the format of
+// the references can be checked, which Complaint.Validate does.
+func resolveReferences(root string, refs []domain.CodeRef) ([]domain.CodeRef, error) {
+	if len(refs) == 0 {
+		return nil, nil
+	}
+
+	resolved := make([]domain.CodeRef, len(refs))
+
+	for i, ref := range refs {
+		ref.Path = strings.TrimSpace(ref.Path)
+		ref.Symbol = strings.TrimSpace(ref.Symbol)
+		ref.Commit = strings.ToLower(strings.TrimSpace(ref.Commit))
+
+		if root != "" && filepath.IsAbs(ref.Path) {
+			rel, err := filepath.Rel(root, ref.Path)
+			if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				return nil, fmt.Errorf("reference %s is outside the repository at %s", ref.Path, root)
+			}
+
+			ref.Path = filepath.ToSlash(rel)
+		}
+
+		resolved[i] = ref
+
+		if root == "" {
+			continue
+		}
+
+		if err := ref.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid reference %d: %w", i+1, err)
+		}
+
+		if err := checkReferenceTarget(root, ref); err != nil {
+			return nil, err
+		}
+	}
+
+	return resolved, nil
+}
+
+// checkReferenceTarget checks that ref names a file under root that has
+// the referenced lines. Directories may be referenced without lines.
+func checkReferenceTarget(root string, ref domain.CodeRef) error {
+	target := filepath.Join(root, filepath.FromSlash(ref.Path))
+
+	info, err := os.Stat(target)
+	if err != nil {
+		return fmt.Errorf("reference %s not found in repository at %s", ref.Path, root)
+	}
+
+	last := ref.LastLine()
+	if last == 0 {
+		return nil
+	}
+
+	if info.IsDir() {
+		return fmt.Errorf("reference %s is a directory and cannot have a line range", ref.Path)
+	}
+
+	content, err := os.ReadFile(target)
+	if err != nil {
+		return fmt.Errorf("failed to read reference %s: %w", ref.Path, err)
+	}
+
+	lines := bytes.Count(content, []byte("\n"))
+	if len(content) > 0 && !bytes.HasSuffix(content, []byte("\n")) {
+		lines++
+	}
+
+	if last > lines {
+		return fmt.Errorf("reference %s: line %d is past the end of the file (%d lines)", ref.Path, last, lines)
+	}
+
+	return nil
+}
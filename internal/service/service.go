@@ -8,8 +8,12 @@ import (
 	"time"
 
 	"github.com/larsartmann/complaints-mcp/internal/domain"
+	"github.com/larsartmann/complaints-mcp/internal/embed"
+	apperrors "github.com/larsartmann/complaints-mcp/internal/errors"
 	"github.com/larsartmann/complaints-mcp/internal/projectdetect"
+	"github.com/larsartmann/complaints-mcp/internal/query"
 	"github.com/larsartmann/complaints-mcp/internal/repo"
+	"github.com/larsartmann/complaints-mcp/internal/search"
 	"github.com/larsartmann/complaints-mcp/internal/tracing"
 )
 
@@ -18,12 +22,24 @@ type ProjectDetector interface {
 	Detect(ctx context.Context, workingDir string) (*projectdetect.ProjectInfo, error)
 }
 
+// DocsExporter renders complaints to human-readable documents.
+type DocsExporter interface {
+	// Prepare renders a complaint's document, returning where it goes, without writing it.
+	Prepare(complaint *domain.Complaint, projectRoot string) (path string, content []byte, err error)
+	Write(path string, content []byte) error
+}
+
 // ComplaintService handles complaint business logic.
 type ComplaintService struct {
 	repo            repo.Repository
 	tracer          tracing.Tracer
 	logger          *v2.Logger
 	projectDetector ProjectDetector
+	changeDetector  ChangeDetector
+	docsExporter    DocsExporter
+	categories      []string
+	fuzziness       int
+	embeddings      *embed.Store
 }
 
 // NewComplaintService creates a new complaint service.
@@ -35,6 +51,10 @@ func NewComplaintService(repository repo.Repository, tracer tracing.Tracer) *Com
 		tracer:          tracer,
 		logger:          v2.NewWithOptions(os.Stderr, v2.Options{Level: level}),
 		projectDetector: projectdetect.NewGitDetector(),
+		changeDetector:  projectdetect.NewGitDetector(),
+		categories:      domain.DefaultCategories,
+		fuzziness:       search.DefaultFuzziness,
+		embeddings:      embed.NewStore(embed.NewHashEmbedder(embed.DefaultDimensions), ""),
 	}
 }
 
@@ -51,6 +71,55 @@ func NewComplaintServiceWithDetector(
 		tracer:          tracer,
 		logger:          v2.NewWithOptions(os.Stderr, v2.Options{Level: level}),
 		projectDetector: detector,
+		changeDetector:  projectdetect.NewGitDetector(),
+		categories:      domain.DefaultCategories,
+		fuzziness:       search.DefaultFuzziness,
+		embeddings:      embed.NewStore(embed.NewHashEmbedder(embed.DefaultDimensions), ""),
+	}
+}
+
+// SetDocsExporter enables writing a document for each filed or resolved complaint.
+func (s *ComplaintService) SetDocsExporter(exporter DocsExporter) {
+	s.docsExporter = exporter
+}
+
+// SetCategories sets the vocabulary complaint categories are checked against.
+func (s *ComplaintService) SetCategories(categories []string) {
+	s.categories = categories
+}
+
+// SetFuzziness sets the number of typos per word fuzzy searches tolerate.
+func (s *ComplaintService) SetFuzziness(edits int) {
+	s.fuzziness = edits
+}
+
+// Categories returns the vocabulary complaint categories are checked against.
+func (s *ComplaintService) Categories() []string {
+	return s.categories
+}
+
+// ComplaintOption sets optional fields on a complaint being filed.
+type ComplaintOption func(*domain.Complaint)
+
+// WithTags files the complaint with the given free-form tags.
+func WithTags(tags ...string) ComplaintOption {
+	return func(c *domain.Complaint) {
+		c.Tags = domain.NormalizeLabels(tags)
+	}
+}
+
+// WithCategories files the complaint under the given categories.
+func WithCategories(categories ...string) ComplaintOption {
+	return func(c *domain.Complaint) {
+		c.Categories = domain.NormalizeLabels(categories)
+	}
+}
+
+// WithReferences files the complaint with references to the code it is about.
+// Paths may be absolute or relative to the detected repository root.
+func WithReferences(refs ...domain.CodeRef) ComplaintOption {
+	return func(c *domain.Complaint) {
+		c.References = refs
 	}
 }
 
@@ -61,6 +130,7 @@ func (s *ComplaintService) CreateComplaint(
 	agentName, sessionName, taskDescription, contextInfo, missingInfo, confusedBy, futureWishes string,
 	severity domain.Severity,
 	projectName, workingDir string,
+	opts ...ComplaintOption,
 ) (*domain.Complaint, error) {
 	// Validate required fields
 	if agentName == "" {
@@ -71,15 +141,22 @@ func (s *ComplaintService) CreateComplaint(
 		return nil, errors.New("session name is required")
 	}
 
-	// Auto-detect project if not provided
-	if projectName == "" && workingDir != "" {
+	// Auto-detect project if not provided; the project root also anchors
+	// exported docs and the project-local copy of the complaint
+	var projectRoot string
+
+	if workingDir != "" {
 		info, err := s.projectDetector.Detect(ctx, workingDir)
 		if err != nil {
 			s.logger.Warn("Failed to auto-detect project", "error", err, "workingDir", workingDir)
 			// Continue with empty project name - it will fail validation below if truly required
 		} else {
-			projectName = info.Name
-			s.logger.Info("Auto-detected project", "project", projectName, "remote", info.RemoteURL)
+			projectRoot = info.RootPath
+
+			if projectName == "" {
+				projectName = info.Name
+				s.logger.Info("Auto-detected project", "project", projectName, "remote", info.RemoteURL)
+			}
 		}
 	}
 
@@ -109,6 +186,11 @@ func (s *ComplaintService) CreateComplaint(
 		return nil, fmt.Errorf("invalid project name: %w", err)
 	}
 
+	// Keep a project-local copy in the project detected on this machine
+	if registry, ok := s.repo.(repo.ProjectRegistry); ok && projectRoot != "" {
+		registry.AddProject(projectID.String(), projectRoot)
+	}
+
 	// Create complaint with phantom type ID
 	complaint := &domain.Complaint{
 		ID:              id,
@@ -123,16 +205,44 @@ func (s *ComplaintService) CreateComplaint(
 		Severity:        severity,
 		Timestamp:       time.Now(),
 		ResolutionState: domain.ResolutionStateOpen,
+		ProjectRoot:     projectRoot,
+		Version:         1,
+	}
+
+	for _, opt := range opts {
+		opt(complaint)
+	}
+
+	complaint.References, err = resolveReferences(projectRoot, complaint.References)
+	if err != nil {
+		return nil, fmt.Errorf("invalid complaint: %w", err)
 	}
 
+	complaint.History = []domain.Event{{
+		At:     complaint.Timestamp,
+		Actor:  agentName,
+		Tool:   toolFromContext(ctx),
+		Action: domain.EventFiled,
+	}}
+
 	if err := complaint.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid complaint: %w", err)
 	}
 
+	if err := complaint.ValidateCategories(s.categories); err != nil {
+		return nil, fmt.Errorf("invalid complaint: %w", err)
+	}
+
+	complaint.SimilarTo = s.findSimilar(ctx, complaint)
+
+	doc, rendered := s.renderDocs(complaint, projectRoot)
+
 	if err := s.repo.Save(ctx, complaint); err != nil {
 		return nil, fmt.Errorf("failed to save complaint: %w", err)
 	}
 
+	s.writeDocs(complaint, doc, rendered)
+
 	return complaint, nil
 }
 
@@ -152,28 +262,149 @@ func (s *ComplaintService) ListComplaints(
 	return s.repo.FindAll(ctx, limit, offset)
 }
 
+// FindComplaints returns up to limit complaints passing the query's filters,
+// in its sort order. The filters are evaluated in storage where it can.
+func (s *ComplaintService) FindComplaints(
+	ctx context.Context,
+	q query.Query,
+	limit int,
+) ([]*domain.Complaint, error) {
+	return s.repo.FindByQuery(ctx, q, limit)
+}
+
 // ResolveComplaint marks a complaint as resolved.
 func (s *ComplaintService) ResolveComplaint(
 	ctx context.Context,
 	id domain.ComplaintID,
 	resolvedBy string,
 ) (*domain.Complaint, error) {
-	complaint, err := s.repo.FindByID(ctx, id)
+	return s.ResolveComplaintAtVersion(ctx, id, resolvedBy, 0)
+}
+
+// ResolveComplaintAtVersion marks a complaint as resolved if it is still at
+// expectedVersion. An expectedVersion of 0 skips the check.
+func (s *ComplaintService) ResolveComplaintAtVersion(
+	ctx context.Context,
+	id domain.ComplaintID,
+	resolvedBy string,
+	expectedVersion uint64,
+) (*domain.Complaint, error) {
+	event := domain.Event{Actor: resolvedBy, Action: domain.EventResolved}
+
+	return s.modify(ctx, id, expectedVersion, event, func(complaint *domain.Complaint) error {
+		if err := complaint.Resolve(resolvedBy); err != nil {
+			return fmt.Errorf("failed to resolve complaint: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// TransitionComplaint moves a complaint through its lifecycle on behalf of
+// actor, if it is still at expectedVersion. An expectedVersion of 0 skips the check.
+func (s *ComplaintService) TransitionComplaint(
+	ctx context.Context,
+	id domain.ComplaintID,
+	to domain.ResolutionState,
+	actor, reason string,
+	expectedVersion uint64,
+) (*domain.Complaint, error) {
+	event := domain.Event{Actor: actor, Action: domain.EventStateChanged, Reason: reason}
+
+	return s.modify(ctx, id, expectedVersion, event, func(complaint *domain.Complaint) error {
+		if err := complaint.Transition(to, actor, reason); err != nil {
+			return fmt.Errorf("failed to move complaint to %s: %w", to, err)
+		}
+
+		return nil
+	})
+}
+
+// modify applies change to a copy of a complaint and writes it back with the
+// next version, recording what changed as event in the complaint's history.
+// Changes that leave the complaint as it was are not written. The complaint
+// is locked across the read-modify-write, and the repository rejects the
+// write if another process got there first.
+func (s *ComplaintService) modify(
+	ctx context.Context,
+	id domain.ComplaintID,
+	expectedVersion uint64,
+	event domain.Event,
+	change func(*domain.Complaint) error,
+) (*domain.Complaint, error) {
+	if locker, ok := s.repo.(repo.ComplaintLocker); ok {
+		lockedCtx, unlock, err := locker.LockComplaint(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to lock complaint: %w", err)
+		}
+		defer unlock()
+
+		ctx = lockedCtx
+	}
+
+	current, err := s.repo.FindByID(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find complaint: %w", err)
 	}
 
-	if err := complaint.Resolve(resolvedBy); err != nil {
-		return nil, fmt.Errorf("failed to resolve complaint: %w", err)
+	if expectedVersion != 0 && current.Version != expectedVersion {
+		return nil, apperrors.NewVersionConflictError(id.String(), expectedVersion, current.Version)
+	}
+
+	// Never modify the stored copy in place: a cache may share it
+	complaint := current.Clone()
+	if err := change(complaint); err != nil {
+		return nil, err
 	}
 
+	event.Tool = toolFromContext(ctx)
+	if !complaint.Record(current, event) {
+		return complaint, nil
+	}
+
+	doc, rendered := s.renderDocs(complaint, "")
+
 	if err := s.repo.Update(ctx, complaint); err != nil {
 		return nil, fmt.Errorf("failed to update complaint: %w", err)
 	}
 
+	s.writeDocs(complaint, doc, rendered)
+
 	return complaint, nil
 }
 
+// renderDocs renders the complaint document and records its path on the complaint,
+// so the path is saved with it. The document is only written by writeDocs once
+// the complaint is: a rejected write leaves no document behind.
+// Render failures are logged rather than returned: the complaint itself is still worth keeping.
+func (s *ComplaintService) renderDocs(complaint *domain.Complaint, projectRoot string) (content []byte, ok bool) {
+	if s.docsExporter == nil {
+		return nil, false
+	}
+
+	path, content, err := s.docsExporter.Prepare(complaint, projectRoot)
+	if err != nil {
+		s.logger.Warn("Failed to export complaint document", "error", err, "id", complaint.ID.String())
+
+		return nil, false
+	}
+
+	complaint.DocsPath = path
+
+	return content, true
+}
+
+// writeDocs writes the document rendered for a complaint that has been saved.
+func (s *ComplaintService) writeDocs(complaint *domain.Complaint, content []byte, rendered bool) {
+	if !rendered {
+		return
+	}
+
+	if err := s.docsExporter.Write(complaint.DocsPath, content); err != nil {
+		s.logger.Warn("Failed to export complaint document", "error", err, "id", complaint.ID.String())
+	}
+}
+
 // GetFilePaths returns file and docs paths for a complaint.
 func (s *ComplaintService) GetFilePaths(
 	ctx context.Context,
@@ -202,6 +433,23 @@ func (s *ComplaintService) GetCacheStats() repo.CacheStats {
 	return s.repo.GetCacheStats()
 }
 
+// GetStorageStats returns storage quota usage. enabled is false when no quota is enforced.
+func (s *ComplaintService) GetStorageStats(
+	ctx context.Context,
+) (stats repo.StorageStats, enabled bool, err error) {
+	provider, ok := s.repo.(repo.StorageStatsProvider)
+	if !ok {
+		return repo.StorageStats{}, false, nil
+	}
+
+	stats, err = provider.GetStorageStats(ctx)
+	if err != nil {
+		return repo.StorageStats{}, true, fmt.Errorf("failed to get storage stats: %w", err)
+	}
+
+	return stats, true, nil
+}
+
 // SearchComplaints searches complaints by text query.
 func (s *ComplaintService) SearchComplaints(
 	ctx context.Context,
@@ -211,6 +459,31 @@ func (s *ComplaintService) SearchComplaints(
 	return s.repo.Search(ctx, query, limit)
 }
 
+// SearchComplaintsRanked searches complaints with a query in the syntax of
+// query.Parse, best match first, with a highlighted snippet of each. A fuzzy
+// search also matches words with typos, up to the configured edit distance.
+// Hits must also pass filters, which are applied before the limit.
+func (s *ComplaintService) SearchComplaintsRanked(
+	ctx context.Context,
+	text string,
+	limit int,
+	fuzzy bool,
+	filters ...query.Filter,
+) ([]repo.SearchHit, error) {
+	q, err := query.Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid query: %w", err)
+	}
+
+	q.Filters = append(q.Filters, filters...)
+
+	if fuzzy {
+		q.Fuzziness = s.fuzziness
+	}
+
+	return repo.RankedSearch(ctx, s.repo, q, limit)
+}
+
 // ListUnresolvedComplaints retrieves unresolved complaints.
 func (s *ComplaintService) ListUnresolvedComplaints(
 	ctx context.Context,
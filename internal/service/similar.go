@@ -0,0 +1,136 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/larsartmann/complaints-mcp/internal/domain"
+	"github.com/larsartmann/complaints-mcp/internal/embed"
+)
+
+// similarityPageSize is the number of complaints read at a time to compare.
+const similarityPageSize = 500
+
+// SimilarComplaint is a complaint and how close it is to what was compared.
+type SimilarComplaint struct {
+	Complaint *domain.Complaint
+	Score     float64 // cosine similarity of the embeddings, up to 1
+}
+
+// SetEmbeddings replaces the in-memory, hash-embedded store of complaint
+// vectors used by FindSimilarComplaints.
+func (s *ComplaintService) SetEmbeddings(store *embed.Store) {
+	s.embeddings = store
+}
+
+// FindSimilarComplaints returns up to limit complaints whose embeddings are
+// nearest to the complaint with id or, if id is zero, to text, most similar
+// first. Unlike search it finds complaints that describe the same problem in
+// other words. Complaints new or changed since the last comparison are
+// embedded first.
+func (s *ComplaintService) FindSimilarComplaints(
+	ctx context.Context,
+	id domain.ComplaintID,
+	text string,
+	limit int,
+) ([]SimilarComplaint, error) {
+	text = strings.TrimSpace(text)
+
+	switch {
+	case id.IsZero() && text == "":
+		return nil, errors.New("either a complaint ID or text is required")
+	case !id.IsZero() && text != "":
+		return nil, errors.New("give either a complaint ID or text, not both")
+	}
+
+	if !id.IsZero() {
+		if _, err := s.repo.FindByID(ctx, id); err != nil {
+			return nil, fmt.Errorf("failed to find complaint %s: %w", id, err)
+		}
+	}
+
+	complaints, err := s.allComplaints(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	vectors, err := s.embeddings.Vectors(ctx, complaints)
+	if err != nil {
+		return nil, err
+	}
+
+	// A failed write only means the vectors are computed again next time
+	if err := s.embeddings.Flush(); err != nil {
+		s.logger.Warn("Failed to persist complaint embeddings", "error", err)
+	}
+
+	target, err := s.targetVector(ctx, id, text, complaints, vectors)
+	if err != nil {
+		return nil, err
+	}
+
+	similar := make([]SimilarComplaint, 0, len(complaints))
+
+	for i, complaint := range complaints {
+		if complaint.ID == id {
+			continue
+		}
+
+		if score := embed.Cosine(target, vectors[i]); score > 0 {
+			similar = append(similar, SimilarComplaint{Complaint: complaint, Score: score})
+		}
+	}
+
+	slices.SortStableFunc(similar, func(a, b SimilarComplaint) int {
+		return cmp.Compare(b.Score, a.Score)
+	})
+
+	if len(similar) > limit {
+		similar = similar[:limit]
+	}
+
+	return similar, nil
+}
+
+// targetVector returns the vector of the complaint with id among complaints
+// or, if id is zero, of text.
+func (s *ComplaintService) targetVector(
+	ctx context.Context,
+	id domain.ComplaintID,
+	text string,
+	complaints []*domain.Complaint,
+	vectors [][]float32,
+) ([]float32, error) {
+	if id.IsZero() {
+		return s.embeddings.Embed(ctx, text)
+	}
+
+	i := slices.IndexFunc(complaints, func(c *domain.Complaint) bool { return c.ID == id })
+	if i < 0 {
+		return nil, fmt.Errorf("complaint %s was deleted while comparing", id)
+	}
+
+	return vectors[i], nil
+}
+
+// allComplaints reads every complaint from storage.
+func (s *ComplaintService) allComplaints(ctx context.Context) ([]*domain.Complaint, error) {
+	var all []*domain.Complaint
+
+	for offset := 0; ; offset += similarityPageSize {
+		page, err := s.repo.FindAll(ctx, similarityPageSize, offset)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list complaints: %w", err)
+		}
+
+		all = append(all, page...)
+
+		if len(page) < similarityPageSize {
+			return all, nil
+		}
+	}
+}
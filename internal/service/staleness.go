@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/larsartmann/complaints-mcp/internal/domain"
+	"github.com/larsartmann/complaints-mcp/internal/projectdetect"
+)
+
+// ChangeDetector finds the files changed in a repository since a point in time.
+type ChangeDetector interface {
+	ChangesSince(ctx context.Context, root string, since time.Time) ([]projectdetect.PathChange, error)
+}
+
+// PossiblyAddressed is an open complaint whose files changed after it was filed.
+type PossiblyAddressed struct {
+	Complaint *domain.Complaint
+	Changes   []projectdetect.PathChange // latest change per mentioned file, newest first
+}
+
+// SetChangeDetector replaces the git-based detector used to find changed files.
+func (s *ComplaintService) SetChangeDetector(detector ChangeDetector) {
+	s.changeDetector = detector
+}
+
+// FindPossiblyAddressed returns up to limit open complaints whose referenced
+// or mentioned files were changed by a commit made after the complaint was
+// filed, newest complaint first. Complaints filed outside a known repository
+// cannot be checked and are left out.
+func (s *ComplaintService) FindPossiblyAddressed(ctx context.Context, limit int) ([]PossiblyAddressed, error) {
+	open, err := s.repo.FindUnresolved(ctx, math.MaxInt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find open complaints: %w", err)
+	}
+
+	// Walk each repository's log once, back to its oldest open complaint
+	oldest := make(map[string]time.Time)
+
+	for _, complaint := range open {
+		if complaint.ProjectRoot == "" || len(complaint.MentionedPaths()) == 0 {
+			continue
+		}
+
+		if since, ok := oldest[complaint.ProjectRoot]; !ok || complaint.Timestamp.Before(since) {
+			oldest[complaint.ProjectRoot] = complaint.Timestamp
+		}
+	}
+
+	changesByRoot := make(map[string][]projectdetect.PathChange, len(oldest))
+
+	for root, since := range oldest {
+		changes, err := s.changeDetector.ChangesSince(ctx, root, since)
+		if err != nil {
+			// The checkout may have moved or been deleted since the complaint was filed
+			s.logger.Warn("Failed to check repository for changes", "error", err, "root", root)
+
+			continue
+		}
+
+		changesByRoot[root] = changes
+	}
+
+	var found []PossiblyAddressed
+
+	for _, complaint := range open {
+		changes := changedAfter(complaint, changesByRoot[complaint.ProjectRoot])
+		if len(changes) > 0 {
+			found = append(found, PossiblyAddressed{Complaint: complaint, Changes: changes})
+		}
+	}
+
+	slices.SortFunc(found, func(a, b PossiblyAddressed) int {
+		return b.Complaint.Timestamp.Compare(a.Complaint.Timestamp)
+	})
+
+	if limit > 0 && len(found) > limit {
+		found = found[:limit]
+	}
+
+	return found, nil
+}
+
+// changedAfter picks the latest of changes, newest first, to each file the
+// complaint mentions, or under each directory it mentions, made after it was filed.
+func changedAfter(complaint *domain.Complaint, changes []projectdetect.PathChange) []projectdetect.PathChange {
+	mentioned := complaint.MentionedPaths()
+
+	var latest []projectdetect.PathChange
+
+	for _, change := range changes {
+		if !change.At.After(complaint.Timestamp) {
+			continue
+		}
+
+		if slices.ContainsFunc(latest, func(seen projectdetect.PathChange) bool { return seen.Path == change.Path }) {
+			continue
+		}
+
+		if slices.ContainsFunc(mentioned, func(path string) bool {
+			return change.Path == path || strings.HasPrefix(change.Path, path+"/")
+		}) {
+			latest = append(latest, change)
+		}
+	}
+
+	return latest
+}
@@ -0,0 +1,46 @@
+package types
+
+import (
+	"fmt"
+)
+
+// RetentionMode selects what the retention sweeper does with expired complaints.
+type RetentionMode string
+
+const (
+	RetentionModeDelete  RetentionMode = "delete"
+	RetentionModeArchive RetentionMode = "archive"
+)
+
+// NewRetentionMode creates a validated retention mode.
+func NewRetentionMode(mode string) (RetentionMode, error) {
+	if mode == "" {
+		return RetentionModeDelete, nil // Default to plain deletion
+	}
+
+	m := RetentionMode(mode)
+	switch m {
+	case RetentionModeDelete, RetentionModeArchive:
+		return m, nil
+	default:
+		return RetentionModeDelete, fmt.Errorf(
+			"invalid retention mode: %s (must be delete or archive)",
+			mode,
+		)
+	}
+}
+
+// String returns the retention mode as string.
+func (rm RetentionMode) String() string {
+	return string(rm)
+}
+
+// IsValid returns true if the retention mode is supported.
+func (rm RetentionMode) IsValid() bool {
+	switch rm {
+	case RetentionModeDelete, RetentionModeArchive:
+		return true
+	default:
+		return false
+	}
+}
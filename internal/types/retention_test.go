@@ -0,0 +1,55 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRetentionMode(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		expected    RetentionMode
+		expectError bool
+	}{
+		{
+			name:        "valid delete",
+			input:       "delete",
+			expected:    RetentionModeDelete,
+			expectError: false,
+		},
+		{
+			name:        "valid archive",
+			input:       "archive",
+			expected:    RetentionModeArchive,
+			expectError: false,
+		},
+		{
+			name:        "empty defaults to delete",
+			input:       "",
+			expected:    RetentionModeDelete,
+			expectError: false,
+		},
+		{
+			name:        "invalid mode",
+			input:       "shred",
+			expected:    RetentionModeDelete,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := NewRetentionMode(tt.input)
+			assertConstructorResult(t, tt.expectError, tt.expected, result, err)
+		})
+	}
+}
+
+func TestRetentionModeMethods(t *testing.T) {
+	assert.Equal(t, "archive", RetentionModeArchive.String())
+	assert.True(t, RetentionModeDelete.IsValid())
+	assert.True(t, RetentionModeArchive.IsValid())
+	assert.False(t, RetentionMode("shred").IsValid())
+}
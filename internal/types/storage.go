@@ -0,0 +1,46 @@
+package types
+
+import (
+	"fmt"
+)
+
+// StorageBackend provides type-safe selection of the repository backend.
+type StorageBackend string
+
+const (
+	StorageBackendFile   StorageBackend = "file"
+	StorageBackendSQLite StorageBackend = "sqlite"
+)
+
+// NewStorageBackend creates a validated storage backend.
+func NewStorageBackend(backend string) (StorageBackend, error) {
+	if backend == "" {
+		return StorageBackendFile, nil // Default to flat JSON files
+	}
+
+	b := StorageBackend(backend)
+	switch b {
+	case StorageBackendFile, StorageBackendSQLite:
+		return b, nil
+	default:
+		return StorageBackendFile, fmt.Errorf(
+			"invalid storage backend: %s (must be file or sqlite)",
+			backend,
+		)
+	}
+}
+
+// String returns the storage backend as string.
+func (sb StorageBackend) String() string {
+	return string(sb)
+}
+
+// IsValid returns true if the storage backend is supported.
+func (sb StorageBackend) IsValid() bool {
+	switch sb {
+	case StorageBackendFile, StorageBackendSQLite:
+		return true
+	default:
+		return false
+	}
+}
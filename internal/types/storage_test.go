@@ -0,0 +1,55 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewStorageBackend(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		expected    StorageBackend
+		expectError bool
+	}{
+		{
+			name:        "valid file",
+			input:       "file",
+			expected:    StorageBackendFile,
+			expectError: false,
+		},
+		{
+			name:        "valid sqlite",
+			input:       "sqlite",
+			expected:    StorageBackendSQLite,
+			expectError: false,
+		},
+		{
+			name:        "empty defaults to file",
+			input:       "",
+			expected:    StorageBackendFile,
+			expectError: false,
+		},
+		{
+			name:        "invalid backend",
+			input:       "postgres",
+			expected:    StorageBackendFile,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := NewStorageBackend(tt.input)
+			assertConstructorResult(t, tt.expectError, tt.expected, result, err)
+		})
+	}
+}
+
+func TestStorageBackendMethods(t *testing.T) {
+	assert.Equal(t, "sqlite", StorageBackendSQLite.String())
+	assert.True(t, StorageBackendFile.IsValid())
+	assert.True(t, StorageBackendSQLite.IsValid())
+	assert.False(t, StorageBackend("postgres").IsValid())
+}